@@ -0,0 +1,218 @@
+package alipay
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+const (
+	// MinAmount 支付宝金额取值范围的最小值：0.01元
+	MinAmount Amount = 1
+	// MaxAmount 支付宝金额取值范围的最大值：100000000元
+	MaxAmount Amount = 100000000 * 100
+)
+
+var amountFormatErr = errors.New("invalid amount, the amount must be a decimal with at most two decimal places")
+
+// Amount 金额，以币种的最小单位保存，避免浮点数精度问题；人民币等两位小数的币种为分，
+// 请求参数指定标价币种（TransCurrency）时为该币种的最小单位，如JPY为円、KWD为千分之一第纳尔
+// JSON序列化为两位小数的字符串，如"0.01"；反序列化同时支持字符串和数字，如"0.01"、0.01
+// 支付宝所有金额字段（Price类型）均支持以字符串形式传入，要求数字类型的字段使用 NumberAmount
+type Amount int64
+
+// AmountFromFen 以分为单位创建金额
+func AmountFromFen(fen int64) Amount {
+	return Amount(fen)
+}
+
+// ParseAmount 解析以元为单位的金额字符串，如"0.01"、"100"、"1.5"，最多两位小数
+func ParseAmount(s string) (Amount, error) {
+	fen, err := parseDecimal(s, 2)
+	if err != nil {
+		return 0, err
+	}
+	return Amount(fen), nil
+}
+
+// MustParseAmount 解析金额字符串，解析失败时panic，用于常量初始化
+func MustParseAmount(s string) Amount {
+	amount, err := ParseAmount(s)
+	if err != nil {
+		panic(err)
+	}
+	return amount
+}
+
+// Fen 以分为单位的金额
+func (a Amount) Fen() int64 {
+	return int64(a)
+}
+
+// String 以元为单位、保留两位小数的金额字符串，如"0.01"
+func (a Amount) String() string {
+	return formatDecimal(int64(a), 2)
+}
+
+// Add 金额相加
+func (a Amount) Add(b Amount) Amount {
+	return a + b
+}
+
+// Sub 金额相减
+func (a Amount) Sub(b Amount) Amount {
+	return a - b
+}
+
+// Mul 金额乘以数量，如商品单价乘以商品数量
+func (a Amount) Mul(n int64) Amount {
+	return a * Amount(n)
+}
+
+// Cmp 比较金额大小，a<b 返回-1，a==b 返回0，a>b 返回1
+func (a Amount) Cmp(b Amount) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+// IsZero 金额是否为0
+func (a Amount) IsZero() bool {
+	return a == 0
+}
+
+// Validate 校验金额是否在支付宝的取值范围 [0.01,100000000] 内
+func (a Amount) Validate() error {
+	return a.ValidateIn(CurrencyCNY)
+}
+
+// ValidateIn 校验以 currency 最小单位保存的金额是否在 [最小单位,100000000] 范围内，如JPY为[1,100000000]，KWD为[0.001,100000000]
+// 币种为空时按人民币处理
+func (a Amount) ValidateIn(currency Currency) error {
+	maxAmount := Amount(100000000)
+	for i := 0; i < currency.MinorUnits(); i++ {
+		maxAmount *= 10
+	}
+	if a < 1 || a > maxAmount {
+		return fmt.Errorf("amount %s is out of range [%s,%s]", a.CurrencyAmount(currency), Amount(1).CurrencyAmount(currency), maxAmount.CurrencyAmount(currency))
+	}
+	return nil
+}
+
+func (a Amount) MarshalJSON() ([]byte, error) {
+	return []byte(strconv.Quote(a.String())), nil
+}
+
+func (a *Amount) UnmarshalJSON(data []byte) error {
+	fen, err := unmarshalDecimal(data, 2)
+	if err != nil {
+		return err
+	}
+	*a = Amount(fen)
+	return nil
+}
+
+// NumberAmount 以数字形式序列化的金额，用于要求 Number 类型的接口字段，如 0.01
+// 除JSON序列化外与 Amount 相同，两者可直接转换：NumberAmount(amount)、Amount(numberAmount)
+type NumberAmount Amount
+
+// String 以元为单位、保留两位小数的金额字符串，如"0.01"
+func (n NumberAmount) String() string {
+	return Amount(n).String()
+}
+
+// Validate 校验金额是否在支付宝的取值范围 [0.01,100000000] 内
+func (n NumberAmount) Validate() error {
+	return Amount(n).Validate()
+}
+
+func (n NumberAmount) MarshalJSON() ([]byte, error) {
+	return []byte(n.String()), nil
+}
+
+func (n *NumberAmount) UnmarshalJSON(data []byte) error {
+	return (*Amount)(n).UnmarshalJSON(data)
+}
+
+// unmarshalDecimal 解析JSON中的小数，支持字符串和数字，空字符串及null解析为0
+func unmarshalDecimal(data []byte, precision int) (int64, error) {
+	data = bytes.TrimSpace(data)
+	if bytes.Equal(data, []byte("null")) {
+		return 0, nil
+	}
+	s := string(data)
+	if strings.HasPrefix(s, `"`) {
+		var err error
+		if s, err = strconv.Unquote(s); err != nil {
+			return 0, err
+		}
+	}
+	if s == "" {
+		return 0, nil
+	}
+	return parseDecimal(s, precision)
+}
+
+// parseDecimal 将小数字符串解析为最小单位的整数，precision 为小数位数
+func parseDecimal(s string, precision int) (int64, error) {
+	s = strings.TrimSpace(s)
+	negative := strings.HasPrefix(s, "-")
+	if negative {
+		s = s[1:]
+	}
+	intPart, fracPart := s, ""
+	if index := strings.IndexByte(s, '.'); index >= 0 {
+		intPart, fracPart = s[:index], s[index+1:]
+	}
+	// 去掉多余的尾部0，如"1.500"
+	if len(fracPart) > precision {
+		trimmed := strings.TrimRight(fracPart[precision:], "0")
+		if trimmed != "" {
+			return 0, amountFormatErr
+		}
+		fracPart = fracPart[:precision]
+	}
+	if intPart == "" || !isDigits(intPart) || !isDigits(fracPart) {
+		return 0, amountFormatErr
+	}
+	fracPart += strings.Repeat("0", precision-len(fracPart))
+	value, err := strconv.ParseInt(intPart+fracPart, 10, 64)
+	if err != nil {
+		return 0, amountFormatErr
+	}
+	if negative {
+		value = -value
+	}
+	return value, nil
+}
+
+// formatDecimal 将最小单位的整数格式化为小数字符串，precision 为小数位数
+func formatDecimal(value int64, precision int) string {
+	sign := ""
+	if value < 0 {
+		sign, value = "-", -value
+	}
+	digits := strconv.FormatInt(value, 10)
+	if precision == 0 {
+		return sign + digits
+	}
+	if len(digits) <= precision {
+		digits = strings.Repeat("0", precision-len(digits)+1) + digits
+	}
+	return sign + digits[:len(digits)-precision] + "." + digits[len(digits)-precision:]
+}
+
+func isDigits(s string) bool {
+	for _, c := range s {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,415 @@
+package alipay
+
+import (
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"reflect"
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/text/encoding/simplifiedchinese"
+	"golang.org/x/text/transform"
+)
+
+const (
+	// BillTypeTrade 业务账单：商户基于支付宝交易收单的业务账单
+	BillTypeTrade = "trade"
+	// BillTypeSignCustomer 账务账单：基于商户支付宝余额收入及支出等资金变动的账务账单
+	BillTypeSignCustomer = "signcustomer"
+
+	// billTagName 账单字段对应的表头名称的struct tag
+	billTagName = "bill"
+	// billSummaryFlag 汇总文件名中的标识
+	billSummaryFlag = "汇总"
+)
+
+var (
+	billDetailNotFoundErr  = errors.New("the bill detail file does not exist in the bill archive")
+	billSummaryNotFoundErr = errors.New("the bill summary file does not exist in the bill archive")
+	billHeaderNotFoundErr  = errors.New("the bill header line is not found")
+)
+
+// TradeBillRecord 业务账单（trade）明细
+type TradeBillRecord struct {
+	TradeNo           string `bill:"支付宝交易号"`      // 支付宝交易号
+	OutTradeNo        string `bill:"商户订单号"`       // 商户订单号
+	BizType           string `bill:"业务类型"`        // 业务类型：交易、退款
+	Subject           string `bill:"商品名称"`        // 商品名称
+	CreateTime        string `bill:"创建时间"`        // 创建时间
+	FinishTime        string `bill:"完成时间"`        // 完成时间
+	StoreId           string `bill:"门店编号"`        // 门店编号
+	StoreName         string `bill:"门店名称"`        // 门店名称
+	OperatorId        string `bill:"操作员"`         // 操作员
+	TerminalId        string `bill:"终端号"`         // 终端号
+	BuyerAccount      string `bill:"对方账户"`        // 对方账户
+	TotalAmount       Amount `bill:"订单金额（元）"`     // 订单金额，退款时为负数
+	ReceiptAmount     Amount `bill:"商家实收（元）"`     // 商家实收
+	AlipayRedPacket   Amount `bill:"支付宝红包（元）"`    // 支付宝红包
+	PointAmount       Amount `bill:"集分宝（元）"`      // 集分宝
+	AlipayDiscount    Amount `bill:"支付宝优惠（元）"`    // 支付宝优惠
+	MerchantDiscount  Amount `bill:"商家优惠（元）"`     // 商家优惠
+	VoucherAmount     Amount `bill:"券核销金额（元）"`    // 券核销金额
+	VoucherName       string `bill:"券名称"`         // 券名称
+	MerchantRedPacket Amount `bill:"商家红包消费金额（元）"` // 商家红包消费金额
+	CardAmount        Amount `bill:"卡消费金额（元）"`    // 卡消费金额
+	OutRequestNo      string `bill:"退款批次号/请求号"`   // 退款批次号/请求号
+	ServiceFee        Amount `bill:"服务费（元）"`      // 服务费
+	Royalty           Amount `bill:"分润（元）"`       // 分润
+	Remark            string `bill:"备注"`          // 备注
+}
+
+// IsRefund 是否为退款记录
+func (r *TradeBillRecord) IsRefund() bool {
+	return r.BizType == "退款"
+}
+
+// AccountLogRecord 账务账单（signcustomer）明细
+type AccountLogRecord struct {
+	AccountLogId string `bill:"账务流水号"`    // 账务流水号
+	BizNo        string `bill:"业务流水号"`    // 业务流水号
+	OutTradeNo   string `bill:"商户订单号"`    // 商户订单号
+	Subject      string `bill:"商品名称"`     // 商品名称
+	TransTime    string `bill:"发生时间"`     // 发生时间
+	OtherAccount string `bill:"对方账号"`     // 对方账号
+	InAmount     Amount `bill:"收入金额（+元）"` // 收入金额
+	OutAmount    Amount `bill:"支出金额（-元）"` // 支出金额
+	Balance      Amount `bill:"账户余额（元）"`  // 账户余额
+	Channel      string `bill:"交易渠道"`     // 交易渠道
+	BizType      string `bill:"业务类型"`     // 业务类型
+	Remark       string `bill:"备注"`       // 备注
+}
+
+// BillSummary 账单汇总文件的内容，不同账单类型的汇总列不同，按表头原样保存
+type BillSummary struct {
+	Header []string   // 汇总列表的表头
+	Rows   [][]string // 汇总列表的数据行，包含“合计”行
+	Notes  []string   // 以#开头的说明行，如账号、起止日期、导出时间等
+}
+
+// Bill 已下载的账单压缩包，包含明细文件及汇总文件
+// DownloadBill 返回的账单保存在临时文件中，使用完后需调用 Close 删除
+type Bill struct {
+	BillType string // 账单类型
+	BillDate string // 账单时间
+	archive  *zip.Reader
+	file     *os.File // 下载的账单临时文件，OpenBill 打开时为空
+}
+
+// DownloadBill 查询对账单下载地址并下载、解压账单
+// 下载地址30秒后失效，因此查询后立即下载；账单压缩包流式写入临时文件，不会整体读入内存，使用完后需调用 Bill.Close 删除临时文件
+// 账单文件为GBK编码的CSV，读取时自动转换为UTF-8
+func (a *Client) DownloadBill(ctx context.Context, billType, billDate string) (bill *Bill, err error) {
+	response, err := a.TradeBillDownloadUrlQuery(TradeBillDownloadUrlQueryRequestParams{BillType: billType, BillDate: billDate})
+	if err != nil {
+		return
+	}
+	if response.Data.Code != SuccessCode {
+		err = fmt.Errorf("query bill download url fail: %s %s %s", response.Data.Code, response.Data.SubCode, response.Data.SubMsg)
+		return
+	}
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, response.Data.BillDownloadUrl, nil)
+	if err != nil {
+		return
+	}
+	httpResponse, err := a.Client.Do(request)
+	if err != nil {
+		return
+	}
+	defer httpResponse.Body.Close()
+	if httpResponse.StatusCode != http.StatusOK {
+		err = fmt.Errorf("download bill fail: http status %d", httpResponse.StatusCode)
+		return
+	}
+	file, err := os.CreateTemp("", "alipay-bill-*.zip")
+	if err != nil {
+		return
+	}
+	bill = &Bill{BillType: billType, BillDate: billDate, file: file}
+	defer func() {
+		if err != nil {
+			_ = bill.Close()
+			bill = nil
+		}
+	}()
+	size, err := io.Copy(file, httpResponse.Body)
+	if err != nil {
+		return
+	}
+	if bill.archive, err = zip.NewReader(file, size); err != nil {
+		err = fmt.Errorf("open bill archive: %w", err)
+	}
+	return
+}
+
+// Close 关闭并删除下载的账单临时文件，OpenBill 打开的账单无需关闭
+func (b *Bill) Close() error {
+	if b.file == nil {
+		return nil
+	}
+	closeErr := b.file.Close()
+	removeErr := os.Remove(b.file.Name())
+	b.file = nil
+	if closeErr != nil {
+		return closeErr
+	}
+	return removeErr
+}
+
+// OpenBill 打开已下载的账单压缩包（zip格式），可用于解析本地保存的账单
+func OpenBill(data []byte) (bill *Bill, err error) {
+	archive, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, fmt.Errorf("open bill archive: %w", err)
+	}
+	return &Bill{archive: archive}, nil
+}
+
+// TradeRecords 遍历业务账单明细
+func (b *Bill) TradeRecords() (iterator *TradeBillIterator, err error) {
+	reader, err := b.openRecords(false)
+	if err != nil {
+		return
+	}
+	return &TradeBillIterator{reader: reader}, nil
+}
+
+// AccountLogRecords 遍历账务账单明细
+func (b *Bill) AccountLogRecords() (iterator *AccountLogIterator, err error) {
+	reader, err := b.openRecords(false)
+	if err != nil {
+		return
+	}
+	return &AccountLogIterator{reader: reader}, nil
+}
+
+// Summary 读取账单汇总文件
+func (b *Bill) Summary() (summary *BillSummary, err error) {
+	reader, err := b.openRecords(true)
+	if err != nil {
+		return
+	}
+	defer reader.Close()
+	summary = &BillSummary{}
+	for reader.next() {
+		summary.Rows = append(summary.Rows, reader.values)
+	}
+	if err = reader.err; err != nil {
+		return nil, err
+	}
+	summary.Header, summary.Notes = reader.header, reader.notes
+	return
+}
+
+func (b *Bill) openRecords(isSummary bool) (reader *billReader, err error) {
+	for _, file := range b.archive.File {
+		if file.FileInfo().IsDir() || strings.Contains(billFileName(file), billSummaryFlag) != isSummary {
+			continue
+		}
+		var rc io.ReadCloser
+		if rc, err = file.Open(); err != nil {
+			return
+		}
+		return newBillReader(rc), nil
+	}
+	if isSummary {
+		return nil, billSummaryNotFoundErr
+	}
+	return nil, billDetailNotFoundErr
+}
+
+// TradeBillIterator 业务账单明细迭代器
+//
+//	for iterator.Next() {
+//		record := iterator.Record()
+//	}
+//	err := iterator.Err()
+type TradeBillIterator struct {
+	reader *billReader
+	record TradeBillRecord
+}
+
+// Next 读取下一条明细，没有更多明细或出现错误时返回 false
+func (i *TradeBillIterator) Next() bool {
+	return i.reader.scan(&i.record)
+}
+
+// Record 当前明细
+func (i *TradeBillIterator) Record() TradeBillRecord {
+	return i.record
+}
+
+// Err 遍历过程中出现的错误
+func (i *TradeBillIterator) Err() error {
+	return i.reader.err
+}
+
+// Notes 已读取的以#开头的说明行，遍历结束后包含文件末尾的交易合计等信息
+func (i *TradeBillIterator) Notes() []string {
+	return i.reader.notes
+}
+
+// Close 关闭明细文件
+func (i *TradeBillIterator) Close() error {
+	return i.reader.Close()
+}
+
+// AccountLogIterator 账务账单明细迭代器，用法同 TradeBillIterator
+type AccountLogIterator struct {
+	reader *billReader
+	record AccountLogRecord
+}
+
+// Next 读取下一条明细，没有更多明细或出现错误时返回 false
+func (i *AccountLogIterator) Next() bool {
+	return i.reader.scan(&i.record)
+}
+
+// Record 当前明细
+func (i *AccountLogIterator) Record() AccountLogRecord {
+	return i.record
+}
+
+// Err 遍历过程中出现的错误
+func (i *AccountLogIterator) Err() error {
+	return i.reader.err
+}
+
+// Notes 已读取的以#开头的说明行，遍历结束后包含文件末尾的合计等信息
+func (i *AccountLogIterator) Notes() []string {
+	return i.reader.notes
+}
+
+// Close 关闭明细文件
+func (i *AccountLogIterator) Close() error {
+	return i.reader.Close()
+}
+
+// billReader 逐行读取账单CSV，跳过以#开头的说明行，第一行数据作为表头
+type billReader struct {
+	closer  io.Closer
+	csv     *csv.Reader
+	header  []string
+	columns map[string]int
+	values  []string
+	notes   []string
+	err     error
+}
+
+func newBillReader(rc io.ReadCloser) *billReader {
+	buffered := bufio.NewReader(rc)
+	var source io.Reader = buffered
+	// 账单一般为GBK编码，也兼容UTF-8编码的账单
+	if head, _ := buffered.Peek(4096); !isUTF8Prefix(head) {
+		source = transform.NewReader(buffered, simplifiedchinese.GBK.NewDecoder())
+	}
+	reader := csv.NewReader(source)
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+	return &billReader{closer: rc, csv: reader}
+}
+
+// next 读取下一行数据到 values
+func (r *billReader) next() bool {
+	for r.err == nil {
+		values, err := r.csv.Read()
+		if err != nil {
+			if err != io.EOF {
+				r.err = err
+			}
+			return false
+		}
+		for i := range values {
+			// 账单中的数值后常带有制表符，避免Excel打开时以科学计数法显示
+			values[i] = strings.TrimSpace(values[i])
+		}
+		if len(values) == 0 || len(values) == 1 && values[0] == "" {
+			continue
+		}
+		if strings.HasPrefix(values[0], "#") {
+			r.notes = append(r.notes, strings.Join(values, ","))
+			continue
+		}
+		if r.header == nil {
+			r.header = values
+			r.columns = make(map[string]int, len(values))
+			for i, name := range values {
+				r.columns[normalizeBillColumn(name)] = i
+			}
+			continue
+		}
+		r.values = values
+		return true
+	}
+	return false
+}
+
+// scan 读取下一行数据并按 bill tag 填充到 record
+func (r *billReader) scan(record interface{}) bool {
+	if !r.next() {
+		if r.err == nil && r.header == nil {
+			r.err = billHeaderNotFoundErr
+		}
+		return false
+	}
+	value := reflect.ValueOf(record).Elem()
+	value.Set(reflect.Zero(value.Type()))
+	valueType := value.Type()
+	for i := 0; i < valueType.NumField(); i++ {
+		index, ok := r.columns[normalizeBillColumn(valueType.Field(i).Tag.Get(billTagName))]
+		if !ok || index >= len(r.values) || r.values[index] == "" {
+			continue
+		}
+		field := value.Field(i)
+		switch field.Interface().(type) {
+		case Amount:
+			amount, err := ParseAmount(r.values[index])
+			if err != nil {
+				r.err = fmt.Errorf("parse bill column %s: %w", r.header[index], err)
+				return false
+			}
+			field.Set(reflect.ValueOf(amount))
+		case string:
+			field.SetString(r.values[index])
+		}
+	}
+	return true
+}
+
+func (r *billReader) Close() error {
+	return r.closer.Close()
+}
+
+// billFileName 压缩包内的文件名，不是UTF-8编码的文件名按GBK解码
+func billFileName(file *zip.File) string {
+	if !utf8.ValidString(file.Name) {
+		if name, _, err := transform.String(simplifiedchinese.GBK.NewDecoder(), file.Name); err == nil {
+			return name
+		}
+	}
+	return file.Name
+}
+
+// normalizeBillColumn 统一表头中的全角、半角括号
+func normalizeBillColumn(name string) string {
+	return strings.NewReplacer("(", "（", ")", "）", " ", "").Replace(strings.TrimSpace(name))
+}
+
+// isUTF8Prefix 判断内容是否为UTF-8编码，末尾被截断的字符不影响判断
+func isUTF8Prefix(p []byte) bool {
+	for len(p) > 0 {
+		r, size := utf8.DecodeRune(p)
+		if r == utf8.RuneError && size == 1 {
+			return len(p) < utf8.UTFMax && !utf8.FullRune(p)
+		}
+		p = p[size:]
+	}
+	return true
+}
@@ -0,0 +1,370 @@
+package alipay
+
+import (
+	"alipay/utils"
+	"context"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+var (
+	certNotFoundErr      = errors.New("the certificate does not exist in the cert store")
+	certSnIsEmptyErr     = errors.New("the certificate serial number is empty")
+	aliCertNotLoadedErr  = errors.New("the alipay public key certificate is not loaded")
+	aliCertSnMismatchErr = errors.New("the downloaded alipay public key certificate does not match the requested serial number")
+	rootCertNotLoadedErr = errors.New("the alipay root certificate is not loaded, cannot verify the downloaded alipay public key certificate")
+)
+
+// CertStore 支付宝公钥证书存储，以证书序列号SN为key保存证书内容（PEM格式，包含begin，end）
+type CertStore interface {
+	// Get 获取证书序列号对应的证书内容，不存在时返回 IsCertNotFound 可判断的错误
+	Get(certSN string) (certContent string, err error)
+	// Put 保存证书序列号对应的证书内容
+	Put(certSN, certContent string) error
+	// List 获取所有已保存的证书序列号
+	List() (certSNs []string, err error)
+}
+
+// IsCertNotFound 判断错误是否为证书不存在
+func IsCertNotFound(err error) bool {
+	return errors.Is(err, certNotFoundErr)
+}
+
+// MemoryCertStore 内存证书存储，进程重启后失效
+type MemoryCertStore struct {
+	mutex sync.RWMutex
+	certs map[string]string
+}
+
+// NewMemoryCertStore 初始化内存证书存储
+func NewMemoryCertStore() *MemoryCertStore {
+	return &MemoryCertStore{certs: make(map[string]string)}
+}
+
+func (m *MemoryCertStore) Get(certSN string) (certContent string, err error) {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+	certContent, ok := m.certs[certSN]
+	if !ok {
+		err = certNotFoundErr
+	}
+	return
+}
+
+func (m *MemoryCertStore) Put(certSN, certContent string) error {
+	if certSN == "" {
+		return certSnIsEmptyErr
+	}
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.certs[certSN] = certContent
+	return nil
+}
+
+func (m *MemoryCertStore) List() (certSNs []string, err error) {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+	for certSN := range m.certs {
+		certSNs = append(certSNs, certSN)
+	}
+	sort.Strings(certSNs)
+	return
+}
+
+// FileCertStore 文件证书存储，每个证书保存为 dir 目录下的 {certSN}.crt 文件
+type FileCertStore struct {
+	mutex sync.RWMutex
+	dir   string
+}
+
+// NewFileCertStore 初始化文件证书存储，dir 不存在时自动创建
+func NewFileCertStore(dir string) (*FileCertStore, error) {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, err
+	}
+	return &FileCertStore{dir: dir}, nil
+}
+
+func (f *FileCertStore) Get(certSN string) (certContent string, err error) {
+	f.mutex.RLock()
+	defer f.mutex.RUnlock()
+	data, err := os.ReadFile(f.certPath(certSN))
+	if err != nil {
+		if os.IsNotExist(err) {
+			err = certNotFoundErr
+		}
+		return
+	}
+	certContent = string(data)
+	return
+}
+
+func (f *FileCertStore) Put(certSN, certContent string) error {
+	if certSN == "" {
+		return certSnIsEmptyErr
+	}
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	// 先写入临时文件再重命名，避免进程异常退出时留下不完整的证书文件
+	tmpFile, err := os.CreateTemp(f.dir, certSN+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmpFile.Name())
+	if _, err = tmpFile.WriteString(certContent); err != nil {
+		tmpFile.Close()
+		return err
+	}
+	if err = tmpFile.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmpFile.Name(), f.certPath(certSN))
+}
+
+func (f *FileCertStore) List() (certSNs []string, err error) {
+	f.mutex.RLock()
+	defer f.mutex.RUnlock()
+	entries, err := os.ReadDir(f.dir)
+	if err != nil {
+		return
+	}
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != ".crt" {
+			continue
+		}
+		certSNs = append(certSNs, strings.TrimSuffix(entry.Name(), ".crt"))
+	}
+	sort.Strings(certSNs)
+	return
+}
+
+func (f *FileCertStore) certPath(certSN string) string {
+	// 证书序列号为MD5值，这里仍取Base避免非法SN穿越目录
+	return filepath.Join(f.dir, filepath.Base(certSN)+".crt")
+}
+
+// AddCertStore 指定支付宝公钥证书存储，默认使用内存存储
+func AddCertStore(store CertStore) OptionFunc {
+	return func(c *Client) {
+		c.certStore = store
+	}
+}
+
+// getAliPublicKeyBySN 获取支付宝公钥证书序列号对应的公钥
+// 查找顺序：内存缓存 -> 证书存储 -> 调用支付宝公钥证书下载接口（下载后写入证书存储）
+func (a *Client) getAliPublicKeyBySN(certSN string) (publicKey *rsa.PublicKey, err error) {
+	a.mutex.RLock()
+	publicKey = a.certSnRelationPublicKey[certSN]
+	a.mutex.RUnlock()
+	if publicKey != nil {
+		return
+	}
+
+	certContent, err := a.certStore.Get(certSN)
+	if err != nil && !IsCertNotFound(err) {
+		return
+	}
+	if err == nil {
+		var x509Cert *x509.Certificate
+		if publicKey, x509Cert, err = a.parseAliCert(certContent); err != nil {
+			return
+		}
+		a.mutex.Lock()
+		a.certSnRelationPublicKey[certSN] = publicKey
+		a.mutex.Unlock()
+		a.setAliCertIfNewer(certSN, x509Cert)
+		return
+	}
+
+	var x509Cert *x509.Certificate
+	if publicKey, x509Cert, err = a.downloadAliCert(certSN); err != nil {
+		return
+	}
+	a.setAliCertIfNewer(certSN, x509Cert)
+	return
+}
+
+// downloadAliCert 下载支付宝公钥证书序列号对应的证书，并保存到证书存储中
+// 下载的证书必须通过支付宝根证书校验，未调用 LoadAlipayRootCertSN 加载根证书时拒绝下载
+func (a *Client) downloadAliCert(certSN string) (publicKey *rsa.PublicKey, x509Cert *x509.Certificate, err error) {
+	a.mutex.RLock()
+	rootCertLoaded := len(a.alipayRootCerts) > 0
+	a.mutex.RUnlock()
+	if !rootCertLoaded {
+		err = rootCertNotLoadedErr
+		return
+	}
+	requestParam := AppAliPayCertDownloadRequestParams{AlipayCertSn: certSN}
+	apiMethodName := requestParam.GetOtherParams().Get(ApiMethodNameFiled)
+	resContent, err := a.doRequest("POST", &requestParam)
+	if err != nil {
+		return
+	}
+	var responseParam AppAliPayCertDownloadResponseParams
+	if err = json.Unmarshal([]byte(resContent), &responseParam); err != nil {
+		return
+	}
+	if responseParam.Data.Code != SuccessCode {
+		err = fmt.Errorf("download alipay cert %s fail: %s %s", certSN, responseParam.Data.SubCode, responseParam.Data.SubMsg)
+		return
+	}
+	// 对公钥证书进行base64解码
+	alipayCertContent, err := base64.StdEncoding.DecodeString(responseParam.Data.AlipayCertContent)
+	if err != nil {
+		return
+	}
+	if publicKey, x509Cert, err = a.parseAliCert(string(alipayCertContent)); err != nil {
+		return
+	}
+	if getCertSN(x509Cert) != certSN {
+		err = aliCertSnMismatchErr
+		return
+	}
+	// 下载接口的响应由新证书签名，证书本身已通过根证书校验，这里用新证书的公钥验签
+	resSignContent, signStr, _ := a.parseJSONSource(apiMethodName, resContent)
+	if signStr == "" {
+		err = signDataIsEmptyErr
+		return
+	}
+	if err = utils.RSAVerify(resSignContent, publicKey, signStr, a.signType); err != nil {
+		return
+	}
+
+	if err = a.certStore.Put(certSN, string(alipayCertContent)); err != nil {
+		return
+	}
+	a.mutex.Lock()
+	a.certSnRelationPublicKey[certSN] = publicKey
+	a.mutex.Unlock()
+	return
+}
+
+// parseAliCert 解析支付宝公钥证书，加载了支付宝根证书时会校验证书链
+func (a *Client) parseAliCert(certContent string) (publicKey *rsa.PublicKey, x509Cert *x509.Certificate, err error) {
+	publicKey, x509Cert, err = utils.GetPublicKeyFromCertContent(certContent)
+	if err != nil {
+		return
+	}
+	if x509Cert == nil || publicKey == nil {
+		err = fmt.Errorf("invalid alipay public key certificate")
+		return
+	}
+	a.mutex.RLock()
+	rootCerts := a.alipayRootCerts
+	a.mutex.RUnlock()
+	if len(rootCerts) == 0 {
+		return
+	}
+	roots := x509.NewCertPool()
+	for _, rootCert := range rootCerts {
+		roots.AddCert(rootCert)
+	}
+	// 支付宝公钥证书文件中第一个为支付宝公钥证书，其余为中间证书
+	intermediates := x509.NewCertPool()
+	rest := []byte(certContent)
+	for isFirst := true; ; isFirst = false {
+		var block *pem.Block
+		if block, rest = pem.Decode(rest); block == nil {
+			break
+		}
+		if isFirst {
+			continue
+		}
+		if cert, parseErr := x509.ParseCertificate(block.Bytes); parseErr == nil {
+			intermediates.AddCert(cert)
+		}
+	}
+	_, err = x509Cert.Verify(x509.VerifyOptions{
+		Roots:         roots,
+		Intermediates: intermediates,
+		KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageAny},
+	})
+	return
+}
+
+// setAliCertIfNewer 如果证书比当前使用的支付宝公钥证书更新，则切换为当前证书
+func (a *Client) setAliCertIfNewer(certSN string, x509Cert *x509.Certificate) {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+	if a.aliCert == nil || x509Cert.NotAfter.After(a.aliCert.NotAfter) {
+		a.aliCertSN = certSN
+		a.aliCert = x509Cert
+	}
+}
+
+// RefreshAliCert 重新下载支付宝公钥证书序列号对应的证书并切换为当前证书
+// certSN 为空时使用最近一次网关响应中的支付宝公钥证书序列号
+func (a *Client) RefreshAliCert(certSN string) (err error) {
+	if certSN == "" {
+		a.mutex.RLock()
+		certSN = a.latestAliCertSN
+		a.mutex.RUnlock()
+	}
+	if certSN == "" {
+		return certSnIsEmptyErr
+	}
+	_, x509Cert, err := a.downloadAliCert(certSN)
+	if err != nil {
+		return
+	}
+	a.setAliCertIfNewer(certSN, x509Cert)
+	return
+}
+
+// RefreshAliCertIfExpiring 当前支付宝公钥证书在 before 时间内过期时，主动刷新证书
+// refreshed 返回是否发生了刷新
+func (a *Client) RefreshAliCertIfExpiring(before time.Duration) (refreshed bool, err error) {
+	a.mutex.RLock()
+	aliCert, certSN, latestCertSN := a.aliCert, a.aliCertSN, a.latestAliCertSN
+	a.mutex.RUnlock()
+	if aliCert == nil {
+		return false, aliCertNotLoadedErr
+	}
+	if time.Until(aliCert.NotAfter) > before {
+		return
+	}
+	// 网关已下发新的证书序列号时优先下载新证书
+	if latestCertSN != "" {
+		certSN = latestCertSN
+	}
+	if err = a.RefreshAliCert(certSN); err != nil {
+		return
+	}
+	return true, nil
+}
+
+// StartAliCertRefresh 启动后台任务，每隔 interval 检查一次支付宝公钥证书，在过期前 before 时间内主动刷新
+// ctx 取消后任务退出，onError 可为空，用于接收刷新失败的错误
+func (a *Client) StartAliCertRefresh(ctx context.Context, interval, before time.Duration, onError func(err error)) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if _, err := a.RefreshAliCertIfExpiring(before); err != nil && onError != nil {
+					onError(err)
+				}
+			}
+		}
+	}()
+}
+
+// getCertSN 计算证书序列号，SN 值是通过解析 X.509 证书文件中签发机构名称（name）以及内置序列号（serialNumber），
+// 将二者拼接后的字符串计算 MD5 值获取
+func getCertSN(x509Cert *x509.Certificate) string {
+	return utils.Md5(x509Cert.Issuer.String() + x509Cert.SerialNumber.String())
+}
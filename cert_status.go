@@ -0,0 +1,151 @@
+package alipay
+
+import (
+	"context"
+	"crypto/x509"
+	"time"
+)
+
+const (
+	// SignModePublicKey 公钥模式
+	SignModePublicKey = "PUBLIC_KEY"
+	// SignModeCert 公钥证书模式
+	SignModeCert = "CERT"
+
+	// CertKindApp 应用公钥证书
+	CertKindApp = "APP_CERT"
+	// CertKindAlipay 支付宝公钥证书
+	CertKindAlipay = "ALIPAY_CERT"
+	// CertKindAlipayRoot 支付宝根证书
+	CertKindAlipayRoot = "ALIPAY_ROOT_CERT"
+)
+
+// CertificateInfo 证书状态信息
+type CertificateInfo struct {
+	Kind          string    `json:"kind"`           // 证书类型：APP_CERT、ALIPAY_CERT、ALIPAY_ROOT_CERT
+	Subject       string    `json:"subject"`        // 证书主题
+	SN            string    `json:"sn"`             // 证书序列号SN
+	NotBefore     time.Time `json:"not_before"`     // 生效时间
+	NotAfter      time.Time `json:"not_after"`      // 过期时间
+	DaysRemaining int       `json:"days_remaining"` // 剩余有效天数，已过期时为负数
+	Expired       bool      `json:"expired"`        // 是否已过期
+}
+
+// CertificateStatusReport 证书状态报告
+type CertificateStatusReport struct {
+	Mode         string            `json:"mode"`         // 当前加签模式：PUBLIC_KEY、CERT
+	Certificates []CertificateInfo `json:"certificates"` // 应用公钥证书、支付宝公钥证书及每个支付宝根证书的状态，公钥模式下为空
+}
+
+// AddCertExpiryNotifier 设置证书过期预警，证书在 window 时间内过期时回调 notifier
+// 预警检查由 CheckCertificateExpiry 或 StartCertExpiryMonitor 触发
+func AddCertExpiryNotifier(window time.Duration, notifier func(info CertificateInfo)) OptionFunc {
+	return func(c *Client) {
+		c.certExpiryWindow = window
+		c.certExpiryNotifier = notifier
+	}
+}
+
+// CertificateStatus 获取当前加签模式及各证书的有效期状态
+func (a *Client) CertificateStatus() (report CertificateStatusReport) {
+	a.mutex.RLock()
+	appCert, aliCert, rootCerts := a.appCert, a.aliCert, a.alipayRootCerts
+	isCertMode := a.appCertSN != ""
+	a.mutex.RUnlock()
+
+	report.Mode = SignModePublicKey
+	if isCertMode {
+		report.Mode = SignModeCert
+	}
+	now := time.Now()
+	if appCert != nil {
+		report.Certificates = append(report.Certificates, newCertificateInfo(CertKindApp, appCert, now))
+	}
+	if aliCert != nil {
+		report.Certificates = append(report.Certificates, newCertificateInfo(CertKindAlipay, aliCert, now))
+	}
+	for _, rootCert := range rootCerts {
+		report.Certificates = append(report.Certificates, newCertificateInfo(CertKindAlipayRoot, rootCert, now))
+	}
+	return
+}
+
+// certExpiryStage 证书的过期预警阶段
+type certExpiryStage int
+
+const (
+	certExpiryStageValid    certExpiryStage = iota // 不在预警窗口内
+	certExpiryStageExpiring                        // 在预警窗口内，尚未过期
+	certExpiryStageExpired                         // 已过期
+)
+
+// CheckCertificateExpiry 检查证书是否即将过期，返回在预警窗口内过期（包括已过期）的证书
+// 设置了 AddCertExpiryNotifier 时，每个证书在进入预警窗口及过期时各回调一次，重复检查不会重复回调
+func (a *Client) CheckCertificateExpiry() (expiring []CertificateInfo) {
+	window := a.certExpiryWindow
+	var notify []CertificateInfo
+	a.certExpiryMutex.Lock()
+	if a.certExpiryNotified == nil {
+		a.certExpiryNotified = make(map[string]certExpiryStage)
+	}
+	for _, info := range a.CertificateStatus().Certificates {
+		stage := certExpiryStageValid
+		switch {
+		case info.Expired:
+			stage = certExpiryStageExpired
+		case time.Until(info.NotAfter) <= window:
+			stage = certExpiryStageExpiring
+		}
+		key := info.Kind + "_" + info.SN
+		if stage == certExpiryStageValid {
+			delete(a.certExpiryNotified, key)
+			continue
+		}
+		expiring = append(expiring, info)
+		if stage > a.certExpiryNotified[key] {
+			a.certExpiryNotified[key] = stage
+			notify = append(notify, info)
+		}
+	}
+	a.certExpiryMutex.Unlock()
+	if a.certExpiryNotifier != nil {
+		for _, info := range notify {
+			a.certExpiryNotifier(info)
+		}
+	}
+	return
+}
+
+// StartCertExpiryMonitor 启动后台任务，每隔 interval 调用一次 CheckCertificateExpiry，ctx 取消后任务退出
+func (a *Client) StartCertExpiryMonitor(ctx context.Context, interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				a.CheckCertificateExpiry()
+			}
+		}
+	}()
+}
+
+func newCertificateInfo(kind string, x509Cert *x509.Certificate, now time.Time) CertificateInfo {
+	remaining := x509Cert.NotAfter.Sub(now)
+	// 向下取整，已过期不足一天时为-1
+	daysRemaining := int(remaining / (24 * time.Hour))
+	if remaining < 0 && remaining%(24*time.Hour) != 0 {
+		daysRemaining--
+	}
+	return CertificateInfo{
+		Kind:          kind,
+		Subject:       x509Cert.Subject.String(),
+		SN:            getCertSN(x509Cert),
+		NotBefore:     x509Cert.NotBefore,
+		NotAfter:      x509Cert.NotAfter,
+		DaysRemaining: daysRemaining,
+		Expired:       remaining <= 0,
+	}
+}
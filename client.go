@@ -4,7 +4,6 @@ import (
 	"alipay/utils"
 	"crypto/rsa"
 	"crypto/x509"
-	"encoding/base64"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -26,25 +25,37 @@ var (
 )
 
 type Client struct {
-	appId         string          // 支付宝分配给开发者的应用ID
-	format        string          // (可不设置) 仅支持JSON
-	charset       string          // 请求使用的编码格式，如utf-8,gbk,gb2312等
-	signType      string          // 商户生成签名字符串所使用的签名算法类型，目前支持RSA2和RSA，推荐使用RSA2
-	version       string          // (可不设置) 调用的接口版本，固定为：1.0
-	appPrivateKey *rsa.PrivateKey // 应用私钥，开发者自己生成
-	aliPublicKey  *rsa.PublicKey  // 支付宝公钥（公钥模式下设置，证书模式下无需设置），创建支付宝应用之后，从支付宝后台获取
-	Client        *http.Client    // http client
-	gatewayUrl    string          // 支付宝网关地址
-	encryptKey    string          // 加密密钥
-	encryptType   string          // 加密类型，默认AES
-
-	mutex     sync.Mutex // 互斥锁
-	appCertSN string     // 应用公钥证书序列号SN（证书模式下设置，公钥模式下无需设置）
+	appId           string          // 支付宝分配给开发者的应用ID
+	format          string          // (可不设置) 仅支持JSON
+	charset         string          // 请求使用的编码格式，如utf-8,gbk,gb2312等
+	signType        string          // 商户生成签名字符串所使用的签名算法类型，目前支持RSA2和RSA，推荐使用RSA2
+	version         string          // (可不设置) 调用的接口版本，固定为：1.0
+	appPrivateKey   *rsa.PrivateKey // 应用私钥，开发者自己生成
+	aliPublicKey    *rsa.PublicKey  // 支付宝公钥（公钥模式下设置，证书模式下无需设置），创建支付宝应用之后，从支付宝后台获取
+	appPublicKey    *rsa.PublicKey  // (可不设置) 应用公钥，仅用于 SelfCheck 校验与应用私钥是否匹配
+	appPublicKeyRaw string          // (可不设置) 应用公钥原始字符串
+	Client          *http.Client    // http client
+	gatewayUrl      string          // 支付宝网关地址
+	encryptKey      string          // 加密密钥
+	encryptType     string          // 加密类型，默认AES
+
+	mutex     sync.RWMutex // 读写锁，保护证书相关字段
+	appCertSN string       // 应用公钥证书序列号SN（证书模式下设置，公钥模式下无需设置）
 	// 注意：如果使用公钥证书签名则需要在请求参数中将"app_cert_sn"和"alipay_root_cert_sn"传入，
 	// 序列号SN 值是通过解析 X.509 证书文件中签发机构名称（name）以及内置序列号（serialNumber），将二者拼接后的字符串计算 MD5 值获取
-	alipayRootCertSn        string                    // 支付宝根证书序列号SN（证书模式下设置，公钥模式下无需设置）
-	aliCertSN               string                    // 支付宝公钥证书序列号SN（证书模式下设置，公钥模式下无需设置），主要用于验签，参考：https://opendocs.alipay.com/common/02mse7
-	certSnRelationPublicKey map[string]*rsa.PublicKey // 证书序列号对应的公钥
+	alipayRootCertSn        string                     // 支付宝根证书序列号SN（证书模式下设置，公钥模式下无需设置）
+	aliCertSN               string                     // 支付宝公钥证书序列号SN（证书模式下设置，公钥模式下无需设置），主要用于验签，参考：https://opendocs.alipay.com/common/02mse7
+	certSnRelationPublicKey map[string]*rsa.PublicKey  // 证书序列号对应的公钥
+	appCert                 *x509.Certificate          // 应用公钥证书
+	nextAppKey              *appSigningKey             // 待切换的应用私钥，到达切换时间后替换当前应用私钥
+	certStore               CertStore                  // 支付宝公钥证书存储，默认为内存存储
+	aliCert                 *x509.Certificate          // 当前使用的支付宝公钥证书
+	alipayRootCerts         []*x509.Certificate        // 支付宝根证书，用于校验下载的支付宝公钥证书
+	latestAliCertSN         string                     // 最近一次网关响应中的支付宝公钥证书序列号
+	certExpiryWindow        time.Duration              // 证书过期预警窗口
+	certExpiryNotifier      func(info CertificateInfo) // 证书即将过期时的回调
+	certExpiryMutex         sync.Mutex                 // 保护 certExpiryNotified
+	certExpiryNotified      map[string]certExpiryStage // 每个证书已回调的预警阶段，key 为证书类型_证书序列号
 
 	location     *time.Location
 	isProduction bool // 是否是生产环境
@@ -75,6 +86,9 @@ func NewClient(appId, aliPublicKey, appPrivateKey, signType string, isProduction
 		version:      ApiVersion,
 		Client:       http.DefaultClient,
 		isProduction: isProduction,
+
+		certSnRelationPublicKey: make(map[string]*rsa.PublicKey),
+		certStore:               NewMemoryCertStore(),
 	}
 	if len(aliPublicKey) > 0 {
 		aliClient.aliPublicKey, err = utils.ParsePKIXPublicKey(utils.GetPemPublic(aliPublicKey))
@@ -83,7 +97,7 @@ func NewClient(appId, aliPublicKey, appPrivateKey, signType string, isProduction
 		}
 	}
 	if len(appPrivateKey) > 0 {
-		aliClient.appPrivateKey, err = utils.ParsePKCS1PrivateKey(utils.GetPemPrivate(appPrivateKey))
+		aliClient.appPrivateKey, err = utils.ParsePrivateKey(utils.GetPemPrivate(appPrivateKey))
 		if err != nil {
 			return
 		}
@@ -106,6 +120,35 @@ func NewClient(appId, aliPublicKey, appPrivateKey, signType string, isProduction
 // apiName 接口名
 // requestParams 请求的参数struct
 func (a *Client) HandlerRequest(httpMethod string, requestParams RequestParams, result interface{}) (err error) {
+	var resContent string
+	resContent, err = a.doRequest(httpMethod, requestParams)
+	if err != nil {
+		return
+	}
+	//fmt.Println("响应数据:", resContent)
+	// 对返回结果验签
+	apiMethodName := requestParams.GetOtherParams().Get(ApiMethodNameFiled)
+	_, err = a.SyncVerifySign(resContent, apiMethodName)
+	if err != nil {
+		return
+	}
+
+	// 对内容解密，这一块有问题
+	if requestParams.GetNeedEncrypt() {
+		resContent, err = a.decryptJSONSignSource(apiMethodName, resContent)
+		if err != nil {
+			return
+		}
+	}
+	if err = json.Unmarshal([]byte(resContent), &result); err != nil {
+		return
+	}
+
+	return
+}
+
+// doRequest 签名并发送请求，返回未验签的原始响应内容
+func (a *Client) doRequest(httpMethod string, requestParams RequestParams) (resContent string, err error) {
 	var urlValues url.Values
 	urlValues, err = a.handlerParams(requestParams)
 	if err != nil {
@@ -132,26 +175,7 @@ func (a *Client) HandlerRequest(httpMethod string, requestParams RequestParams,
 	if err != nil {
 		return
 	}
-	resContent := string(data)
-	//fmt.Println("响应数据:", resContent)
-	// 对返回结果验签
-	apiMethodName := requestParams.GetOtherParams().Get(ApiMethodNameFiled)
-	_, err = a.SyncVerifySign(resContent, apiMethodName)
-	if err != nil {
-		return
-	}
-
-	// 对内容解密，这一块有问题
-	if requestParams.GetNeedEncrypt() {
-		resContent, err = a.decryptJSONSignSource(apiMethodName, resContent)
-		if err != nil {
-			return
-		}
-	}
-	if err = json.Unmarshal([]byte(resContent), &result); err != nil {
-		return
-	}
-
+	resContent = string(data)
 	return
 }
 
@@ -176,35 +200,53 @@ func (a *Client) HandlerPageRequest(httpMethod string, requestParams RequestPara
 		return
 	}
 	if strings.ToUpper(httpMethod) == "GET" {
-		// 拼接GET请求串字符 & 将字符解析为URL对象
-		rawUrl := fmt.Sprintf("%s?%s", a.gatewayUrl, urlValues.Encode())
-		urlResult, err = url.Parse(rawUrl)
+		urlResult, err = a.buildRequestUrl(urlValues)
 	} else {
 		// 拼接表单字符串
-		result = a.buildRequestForm(urlValues)
+		result, err = a.renderRequestForm(urlValues, nil)
 	}
 	return
 }
 
-// 建立请求，以表单HTML形式构造（默认）
-// urlValues: 请求参数
-func (a *Client) buildRequestForm(urlValues url.Values) (fromHtml string) {
-	// 响应为表单格式，可嵌入页面，具体以返回的结果为准
-	fromHtml = "<form id='alipaySubmit' name='alipaySubmit' action='" + a.gatewayUrl + "?charset=" + a.charset + "' method='POST'>"
-	for field, values := range urlValues {
-		if len(values) == 0 {
-			continue
-		}
-		fromHtml += "<input type='hidden' name='" + field + "' value='" + values[0] + "'/>"
+// HandlerPageRequestBoth 页面提交执行方法，对请求参数只签名一次，同时返回跳转URL（GET）和String形式的form（POST）
+// 适用于由前端决定跳转方式的场景，两者使用相同的签名及时间戳
+func (a *Client) HandlerPageRequestBoth(requestParams RequestParams) (formHtml string, urlResult *url.URL, err error) {
+	var urlValues url.Values
+	urlValues, err = a.handlerParams(requestParams)
+	if err != nil {
+		return
 	}
-	fromHtml += "<input type='submit' value='ok' style='display:none;''></form>"
-	fromHtml += "<script>document.forms['alipaySubmit'].submit();</script>"
+	if urlResult, err = a.buildRequestUrl(urlValues); err != nil {
+		return
+	}
+	formHtml, err = a.renderRequestForm(urlValues, nil)
 	return
 }
 
+// HandlerPageFormRequest 页面提交执行方法，返回按 opts 渲染的POST表单，opts 为空时使用 DefaultFormOptions
+// 表单使用 html/template 渲染，参数值中的引号等特殊字符会被转义
+func (a *Client) HandlerPageFormRequest(requestParams RequestParams, opts *FormOptions) (formHtml string, err error) {
+	var urlValues url.Values
+	urlValues, err = a.handlerParams(requestParams)
+	if err != nil {
+		return
+	}
+	return a.renderRequestForm(urlValues, opts)
+}
+
+// buildRequestUrl 拼接GET请求串字符 & 将字符解析为URL对象
+func (a *Client) buildRequestUrl(urlValues url.Values) (urlResult *url.URL, err error) {
+	rawUrl := fmt.Sprintf("%s?%s", a.gatewayUrl, urlValues.Encode())
+	return url.Parse(rawUrl)
+}
+
 // handlerParams 处理请求参数
 // requestParams 请求的参数struct
 func (a *Client) handlerParams(requestParams RequestParams) (url.Values, error) {
+	// 签名前校验请求参数，避免无效请求发送到网关
+	if err := ValidateRequest(requestParams); err != nil {
+		return nil, err
+	}
 	// biz_content,notify_url,return_url,app_auth_token,method 这几个参数需要在调用该方法的时候就传入requestParams中
 	urlValues := requestParams.GetOtherParams()
 	// 系统参数数据组装
@@ -214,11 +256,15 @@ func (a *Client) handlerParams(requestParams RequestParams) (url.Values, error)
 	urlValues.Add("sign_type", a.signType)
 	urlValues.Add("timestamp", time.Unix(time.Now().In(a.location).Unix(), 0).Format(RequestTimestampFormat))
 	urlValues.Add("version", a.version)
-	if a.appCertSN != "" {
-		urlValues.Add("app_cert_sn", a.appCertSN)
+	appPrivateKey, appCertSN := a.signingKey()
+	if appCertSN != "" {
+		urlValues.Add("app_cert_sn", appCertSN)
 	}
-	if a.alipayRootCertSn != "" {
-		urlValues.Add("alipay_root_cert_sn", a.alipayRootCertSn)
+	a.mutex.RLock()
+	alipayRootCertSn := a.alipayRootCertSn
+	a.mutex.RUnlock()
+	if alipayRootCertSn != "" {
+		urlValues.Add("alipay_root_cert_sn", alipayRootCertSn)
 	}
 
 	rawBizContent := urlValues.Get(BizContentFiled)
@@ -230,7 +276,7 @@ func (a *Client) handlerParams(requestParams RequestParams) (url.Values, error)
 	}
 
 	// 获取签名
-	sign, err := a.getSign(urlValues)
+	sign, err := a.getSign(urlValues, appPrivateKey)
 	if err != nil {
 		return urlValues, err
 	}
@@ -263,18 +309,42 @@ func (a *Client) AsyncNotifyVerifySign(urlValues url.Values, isLifeIsNo bool) (r
 	}
 	// 待签名字符串
 	var strParams = strings.Join(valueList, "&")
-	// 获取异步通知返回的签名和签名算法类型
+	// 获取异步通知返回的签名和签名算法类型，签名在 RSAVerify 中进行base64解码
 	sign, signType := urlValues.Get(SignFiled), urlValues.Get(SignTypeFiled)
-	// base64解码
-	signStr := base64.StdEncoding.EncodeToString([]byte(sign))
+	if sign == "" {
+		err = signDataIsEmptyErr
+		return
+	}
+	aliPublicKey, err := a.notifyVerifyKey()
+	if err != nil {
+		return
+	}
 	// 签名验证
-	if err = utils.RSAVerify(strParams, a.aliPublicKey, signStr, signType); err != nil {
+	if err = utils.RSAVerify(strParams, aliPublicKey, sign, signType); err != nil {
 		return
 	}
 	result = true
 	return
 }
 
+// notifyVerifyKey 异步通知验签使用的支付宝公钥：公钥模式下为支付宝公钥，证书模式下为当前支付宝公钥证书中的公钥
+func (a *Client) notifyVerifyKey() (aliPublicKey *rsa.PublicKey, err error) {
+	if a.aliPublicKey != nil {
+		return a.aliPublicKey, nil
+	}
+	a.mutex.RLock()
+	aliCert := a.aliCert
+	a.mutex.RUnlock()
+	if aliCert == nil {
+		return nil, aliCertNotLoadedErr
+	}
+	aliPublicKey, ok := aliCert.PublicKey.(*rsa.PublicKey)
+	if !ok {
+		return nil, aliCertNotLoadedErr
+	}
+	return
+}
+
 // SyncVerifySign 同步返回验签，参考：https://opendocs.alipay.com/common/02mse7
 // 开发者只对支付宝返回的 JSON 中 xxx_response 的值做验签（xxx 代表接口名），公钥、公钥证书两种模式下，异步通知验签方式不相同。
 // 公钥模式说明：
@@ -300,32 +370,14 @@ func (a *Client) SyncVerifySign(rawData, apiMethodName string) (result bool, err
 
 	// 如果使用了公钥证书模式签名则就从支付宝证书中提取公钥
 	if len(alipayCertSn) != 0 {
+		a.mutex.Lock()
+		a.latestAliCertSN = alipayCertSn
+		a.mutex.Unlock()
 		// 当前使用的支付宝公钥证书 SN 与网关响应报文中的 SN 是否一致。若不一致，开发者需先调用 支付宝公钥证书下载接口 下载对应的支付宝公钥证书，再做验签
-		if a.aliCertSN != alipayCertSn || a.certSnRelationPublicKey[alipayCertSn] == nil {
-			var responseParam AppAliPayCertDownloadResponseParams
-			responseParam, err = a.AppAliPayCertDownload(AppAliPayCertDownloadRequestParams{})
-			if err != nil {
-				return
-			}
-			// 对公钥证书进行base64解码
-			var alipayCertContent []byte
-			alipayCertContent, err = base64.StdEncoding.DecodeString(responseParam.Data.AlipayCertContent)
-			if err != nil {
-				return
-			}
-			// 提取公钥证书中的公钥
-			var x509Cert *x509.Certificate
-			aliPublicKey, x509Cert, err = utils.GetPublicKeyFromCertContent(string(alipayCertContent))
-			if err != nil {
-				return
-			}
-			// 计算新的证书序列号&保存
-			certSN := utils.Md5(x509Cert.Issuer.String() + x509Cert.SerialNumber.String())
-			a.mutex.Lock()
-			a.certSnRelationPublicKey[certSN] = aliPublicKey
-			a.mutex.Unlock()
-		} else {
-			aliPublicKey = a.certSnRelationPublicKey[alipayCertSn]
+		// 先从缓存及证书存储中查找，都不存在时才下载
+		aliPublicKey, err = a.getAliPublicKeyBySN(alipayCertSn)
+		if err != nil {
+			return
 		}
 	} else {
 		// 说明签名方式是公钥模式则直接取支付宝公钥即可
@@ -457,10 +509,10 @@ func sortParams(urlValues url.Values) (strParams string) {
 }
 
 // getSign 获取签名
-func (a *Client) getSign(urlValues url.Values) (signStr string, err error) {
+func (a *Client) getSign(urlValues url.Values, appPrivateKey *rsa.PrivateKey) (signStr string, err error) {
 	var strParams string
 	strParams = sortParams(urlValues)
-	return utils.RSASign(strParams, a.appPrivateKey, a.signType)
+	return utils.RSASign(strParams, appPrivateKey, a.signType)
 }
 
 // 对bizContent内容进行加密
@@ -529,9 +581,13 @@ func (a *Client) GetCertSNFromContent(certContent string) (certSN string, err er
 	if err != nil {
 		return
 	}
+	if publicKey == nil {
+		err = errors.New("the certificate does not contain an RSA public key")
+		return
+	}
 
 	// 证书序列号的计算
-	certSN = utils.Md5(x509Cert.Issuer.String() + x509Cert.SerialNumber.String())
+	certSN = getCertSN(x509Cert)
 	a.mutex.Lock()
 	a.certSnRelationPublicKey[certSN] = publicKey
 	a.mutex.Unlock()
@@ -558,15 +614,20 @@ func (a *Client) GetRootCertSNFromPath(rootCertPath string) (rootCertSN string,
 func (a *Client) GetRootCertSNFromContent(rootCertContent string) (rootCertSN string, err error) {
 	certStrSlice := strings.Split(rootCertContent, CertificateSuffix)
 	var rootCertSnSlice []string
+	var rootCerts []*x509.Certificate
 	for _, v := range certStrSlice {
 		x509Cert, _ := utils.ParseX509Certificate(v + CertificateSuffix)
 		if x509Cert == nil || x509Cert.SignatureAlgorithm != x509.SHA1WithRSA && x509Cert.SignatureAlgorithm != x509.SHA256WithRSA {
 			continue
 		}
 		// 证书序列号的计算
-		certSN := utils.Md5(x509Cert.Issuer.String() + x509Cert.SerialNumber.String())
+		certSN := getCertSN(x509Cert)
 		rootCertSnSlice = append(rootCertSnSlice, certSN)
+		rootCerts = append(rootCerts, x509Cert)
 	}
+	a.mutex.Lock()
+	a.alipayRootCerts = rootCerts
+	a.mutex.Unlock()
 	if len(rootCertSnSlice) > 0 {
 		rootCertSN = strings.Join(rootCertSnSlice, "_")
 	}
@@ -575,38 +636,72 @@ func (a *Client) GetRootCertSNFromContent(rootCertContent string) (rootCertSN st
 
 // LoadAppCertSN 从应用公钥证书中加载 应用公钥证书序列号SN
 // certPath：从证书中提取序列号，certContent：从证书内容中提取序列号
-func (a *Client) LoadAppCertSN(certPath, certContent string) {
-	var certSN string
-	if certPath != "" {
-		certSN, _ = a.GetCertSNFromPath(certPath)
-	} else {
-		certSN, _ = a.GetCertSNFromContent(certContent)
+func (a *Client) LoadAppCertSN(certPath, certContent string) (err error) {
+	if certContent, err = readCertContent(certPath, certContent); err != nil {
+		return fmt.Errorf("load app cert: %w", err)
+	}
+	certSN, err := a.GetCertSNFromContent(certContent)
+	if err != nil {
+		return fmt.Errorf("load app cert: %w", err)
 	}
+	x509Cert, _ := utils.ParseX509Certificate(certContent)
+	a.mutex.Lock()
 	a.appCertSN = certSN
+	a.appCert = x509Cert
+	a.mutex.Unlock()
+	return
 }
 
-// LoadAliCertSN 从支付宝公钥证书中加载 支付宝公钥证书序列号SN
+// LoadAliCertSN 从支付宝公钥证书中加载 支付宝公钥证书序列号SN，并将证书保存到证书存储中
 // certPath：从证书中提取序列号，certContent：从证书内容中提取序列号
-func (a *Client) LoadAliCertSN(certPath, certContent string) {
-	var certSN string
-	if certPath != "" {
-		certSN, _ = a.GetCertSNFromPath(certPath)
-	} else {
-		certSN, _ = a.GetCertSNFromContent(certContent)
+func (a *Client) LoadAliCertSN(certPath, certContent string) (err error) {
+	if certContent, err = readCertContent(certPath, certContent); err != nil {
+		return fmt.Errorf("load alipay cert: %w", err)
 	}
+	certSN, err := a.GetCertSNFromContent(certContent)
+	if err != nil {
+		return fmt.Errorf("load alipay cert: %w", err)
+	}
+	x509Cert, _ := utils.ParseX509Certificate(certContent)
+	if err = a.certStore.Put(certSN, certContent); err != nil {
+		return fmt.Errorf("load alipay cert: %w", err)
+	}
+	a.mutex.Lock()
 	a.aliCertSN = certSN
+	a.aliCert = x509Cert
+	a.mutex.Unlock()
+	return
 }
 
 // LoadAlipayRootCertSN 从支付宝根证书书中加载 支付宝根证书序列号SN
 // certPath：从证书中提取序列号，certRootContent：从证书内容中提取序列号
-func (a *Client) LoadAlipayRootCertSN(certRootPath, certRootContent string) {
-	var certRootSN string
-	if certRootPath != "" {
-		certRootSN, _ = a.GetRootCertSNFromPath(certRootPath)
-	} else {
-		certRootSN, _ = a.GetRootCertSNFromContent(certRootContent)
+func (a *Client) LoadAlipayRootCertSN(certRootPath, certRootContent string) (err error) {
+	if certRootContent, err = readCertContent(certRootPath, certRootContent); err != nil {
+		return fmt.Errorf("load alipay root cert: %w", err)
 	}
+	certRootSN, err := a.GetRootCertSNFromContent(certRootContent)
+	if err != nil {
+		return fmt.Errorf("load alipay root cert: %w", err)
+	}
+	if certRootSN == "" {
+		return errors.New("load alipay root cert: no RSA certificate found")
+	}
+	a.mutex.Lock()
 	a.alipayRootCertSn = certRootSN
+	a.mutex.Unlock()
+	return
+}
+
+// readCertContent 读取证书内容，certPath 不为空时从文件读取
+func readCertContent(certPath, certContent string) (string, error) {
+	if certPath == "" {
+		return certContent, nil
+	}
+	certPEMBlock, err := ioutil.ReadFile(certPath)
+	if err != nil {
+		return "", err
+	}
+	return string(certPEMBlock), nil
 }
 
 // EncodeURLParam 将参数mapParams编码为url编码格式
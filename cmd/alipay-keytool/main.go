@@ -0,0 +1,116 @@
+// alipay-keytool 支付宝开放平台密钥工具，用于生成应用密钥对及申请应用公钥证书所需的CSR
+//
+// 用法：
+//
+//	alipay-keytool genkey -bits 2048 -format PKCS1 -out ./keys
+//	alipay-keytool csr -key ./keys/app_private_key.pem -cn 应用名称 -o 公司名称 -out ./keys
+package main
+
+import (
+	"alipay/utils"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+		os.Exit(2)
+	}
+	var err error
+	switch os.Args[1] {
+	case "genkey":
+		err = genKey(os.Args[2:])
+	case "csr":
+		err = genCSR(os.Args[2:])
+	default:
+		usage()
+		os.Exit(2)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%s error:%s\n", os.Args[1], err.Error())
+		os.Exit(1)
+	}
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "usage: alipay-keytool <genkey|csr> [flags]")
+	fmt.Fprintln(os.Stderr, "  genkey  生成应用密钥对")
+	fmt.Fprintln(os.Stderr, "  csr     生成申请应用公钥证书的CSR")
+}
+
+// genKey 生成应用密钥对，输出PEM文件及 NewClient 需要的原始base64字符串
+func genKey(args []string) error {
+	fs := flag.NewFlagSet("genkey", flag.ExitOnError)
+	bits := fs.Int("bits", 2048, "密钥长度")
+	format := fs.String("format", utils.KeyFormatPKCS1, "私钥格式：PKCS1、PKCS8")
+	out := fs.String("out", ".", "输出目录")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	keyPair, err := utils.GenerateRSAKeyPair(*bits, *format)
+	if err != nil {
+		return err
+	}
+	files := map[string]string{
+		"app_private_key.pem": keyPair.PrivateKeyPem,
+		"app_public_key.pem":  keyPair.PublicKeyPem,
+		"app_private_key.txt": keyPair.RawPrivateKey,
+		"app_public_key.txt":  keyPair.RawPublicKey,
+	}
+	if err = writeFiles(*out, files); err != nil {
+		return err
+	}
+	fmt.Printf("应用私钥（NewClient appPrivateKey）：\n%s\n\n", keyPair.RawPrivateKey)
+	fmt.Printf("应用公钥（上传到支付宝开放平台）：\n%s\n", keyPair.RawPublicKey)
+	return nil
+}
+
+// genCSR 使用已有的应用私钥生成CSR
+func genCSR(args []string) error {
+	fs := flag.NewFlagSet("csr", flag.ExitOnError)
+	keyPath := fs.String("key", "app_private_key.pem", "应用私钥PEM文件")
+	var subject utils.CSRSubject
+	fs.StringVar(&subject.CommonName, "cn", "", "公用名称（必填）")
+	fs.StringVar(&subject.Organization, "o", "", "组织/公司名称，需与支付宝账号认证主体一致（必填）")
+	fs.StringVar(&subject.OrganizationalUnit, "ou", "", "部门")
+	fs.StringVar(&subject.Country, "c", "CN", "国家/地区")
+	fs.StringVar(&subject.Province, "st", "", "省份")
+	fs.StringVar(&subject.Locality, "l", "", "城市")
+	fs.StringVar(&subject.Email, "email", "", "邮箱")
+	out := fs.String("out", ".", "输出目录")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	keyPem, err := os.ReadFile(*keyPath)
+	if err != nil {
+		return err
+	}
+	privateKey, err := utils.ParsePrivateKey(string(keyPem))
+	if err != nil {
+		return err
+	}
+	csrPem, err := utils.GenerateCSR(privateKey, subject)
+	if err != nil {
+		return err
+	}
+	if err = writeFiles(*out, map[string]string{"app.csr": csrPem}); err != nil {
+		return err
+	}
+	fmt.Print(csrPem)
+	return nil
+}
+
+func writeFiles(dir string, files map[string]string) error {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return err
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0600); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -25,31 +25,54 @@ func (a *Client) AuthTokenApp(requestParam AuthTokenAppRequestParams) (
 
 // AsyncNotify 处理异步通知回调
 // isLifeNotify 是否是生活号通知
+// 通知为表单格式，验签通过后按json tag解码到 TradeNotificationParams，解码规则见 decodeNotifyValues
 func (a *Client) AsyncNotify(rawBody string, isLifeNotify ...struct{}) (notifyResult TradeNotificationParams, err error) {
-	var urlValues url.Values
+	err = a.verifyAndDecodeNotify(rawBody, len(isLifeNotify) > 0, &notifyResult, nil)
+	return
+}
+
+// AsyncNotifyRefundDepositBack 处理退款银行卡冲退完成通知（alipay.trade.refund.depositback.completed）
+// 退款资金原路退回银行卡时，支付宝在银行卡冲退完成后发送该通知
+func (a *Client) AsyncNotifyRefundDepositBack(rawBody string) (notifyResult RefundDepositBackNotificationParams, err error) {
+	err = a.verifyAndDecodeNotify(rawBody, false, &notifyResult, &notifyResult.Content)
+	return
+}
+
+// verifyAndDecodeNotify 解析异步通知并验签，验签通过后按 decodeNotifyValues 的规则解码到 notifyResult；
+// content 不为空时再将消息服务通知的 biz_content 以JSON解码到 content
+func (a *Client) verifyAndDecodeNotify(rawBody string, isLifeIsNo bool, notifyResult, content interface{}) (err error) {
 	// 调用url.ParseQuery来获取到参数列表，url.ParseQuery会自动完成url decode
-	urlValues, err = url.ParseQuery(rawBody)
+	urlValues, err := url.ParseQuery(rawBody)
 	if err != nil {
 		return
 	}
-
-	isLifeIsNo := false
-	if len(isLifeNotify) > 0 {
-		isLifeIsNo = true
-	}
-
-	// 异步验签
-	_, err = a.AsyncNotifyVerifySign(urlValues, isLifeIsNo)
-	if err != nil {
+	if _, err = a.AsyncNotifyVerifySign(urlValues, isLifeIsNo); err != nil {
 		return
 	}
-	if err = json.Unmarshal([]byte(rawBody), &notifyResult); err != nil {
+	if err = decodeNotifyValues(urlValues, notifyResult); err != nil {
 		return
 	}
-
+	if content != nil {
+		err = json.Unmarshal([]byte(urlValues.Get(BizContentFiled)), content)
+	}
 	return
 }
 
+// decodeNotifyValues 将异步通知的表单参数转换为对应的结构体，字段按json tag匹配
+// 表单参数均以JSON字符串解码，结构体字段只能是 string（含 TradeStatus 等基于 string 的类型）、Amount 或 CurrencyAmount，
+// 其他类型（如 int、float64、bool）会解码失败；同名参数只取第一个值
+func decodeNotifyValues(urlValues url.Values, notifyResult interface{}) (err error) {
+	params := make(map[string]string, len(urlValues))
+	for key := range urlValues {
+		params[key] = urlValues.Get(key)
+	}
+	bytes, err := json.Marshal(params)
+	if err != nil {
+		return
+	}
+	return json.Unmarshal(bytes, notifyResult)
+}
+
 // AppAliPayCertDownload 应用支付宝公钥证书下载
 func (a *Client) AppAliPayCertDownload(requestParam AppAliPayCertDownloadRequestParams) (
 	responseParam AppAliPayCertDownloadResponseParams, err error) {
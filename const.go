@@ -37,6 +37,27 @@ const (
 
 	// SuccessCode 接口调用成功时的返回码
 	SuccessCode = "10000"
+	// ProcessingCode 业务处理中，如用户支付中（需要输入密码）
+	ProcessingCode = "10003"
+	// UnknownErrorCode 业务出现未知错误或者系统异常，需要查询确认结果
+	UnknownErrorCode = "20000"
+	// BusinessFailCode 业务处理失败
+	BusinessFailCode = "40004"
+
+	// SubCodeUserPaying 用户支付中
+	SubCodeUserPaying = "ACQ.USER_PAYING"
+	// SubCodeSystemError 系统错误，需要查询确认结果
+	SubCodeSystemError = "ACQ.SYSTEM_ERROR"
+	// SubCodeTradeNotExist 交易不存在
+	SubCodeTradeNotExist = "ACQ.TRADE_NOT_EXIST"
+	// SubCodeTradeHasSuccess 交易已被支付，使用相同的商户订单号重复支付时返回
+	SubCodeTradeHasSuccess = "ACQ.TRADE_HAS_SUCCESS"
+	// SubCodeTradeHasClose 交易已关闭，使用相同的商户订单号无法再支付
+	SubCodeTradeHasClose = "ACQ.TRADE_HAS_CLOSE"
+	// SubCodeFundSystemError 资金类接口系统繁忙，需要查询确认结果
+	SubCodeFundSystemError = "SYSTEM_ERROR"
+	// SubCodeOrderNotExist 转账单据不存在
+	SubCodeOrderNotExist = "ORDER_NOT_EXIST"
 
 	// EncryptTypeAes 加密类型
 	EncryptTypeAes = "AES"
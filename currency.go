@@ -0,0 +1,152 @@
+package alipay
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// Currency ISO 4217 币种代码
+type Currency string
+
+const (
+	CurrencyCNY Currency = "CNY" // 人民币
+	CurrencyUSD Currency = "USD" // 美元
+	CurrencyEUR Currency = "EUR" // 欧元
+	CurrencyGBP Currency = "GBP" // 英镑
+	CurrencyHKD Currency = "HKD" // 港币
+	CurrencyMOP Currency = "MOP" // 澳门元
+	CurrencyTWD Currency = "TWD" // 新台币
+	CurrencyJPY Currency = "JPY" // 日元
+	CurrencyKRW Currency = "KRW" // 韩元
+	CurrencySGD Currency = "SGD" // 新加坡元
+	CurrencyAUD Currency = "AUD" // 澳元
+	CurrencyNZD Currency = "NZD" // 新西兰元
+	CurrencyCAD Currency = "CAD" // 加拿大元
+	CurrencyCHF Currency = "CHF" // 瑞士法郎
+	CurrencySEK Currency = "SEK" // 瑞典克朗
+	CurrencyDKK Currency = "DKK" // 丹麦克朗
+	CurrencyNOK Currency = "NOK" // 挪威克朗
+	CurrencyTHB Currency = "THB" // 泰铢
+	CurrencyMYR Currency = "MYR" // 马来西亚林吉特
+	CurrencyIDR Currency = "IDR" // 印尼盾
+	CurrencyPHP Currency = "PHP" // 菲律宾比索
+	CurrencyVND Currency = "VND" // 越南盾
+	CurrencyAED Currency = "AED" // 阿联酋迪拉姆
+	CurrencyKWD Currency = "KWD" // 科威特第纳尔
+	CurrencyBHD Currency = "BHD" // 巴林第纳尔
+)
+
+// currencyMinorUnits ISO 4217 中小数位数不为2的币种
+var currencyMinorUnits = map[Currency]int{
+	"BIF": 0, "CLP": 0, "DJF": 0, "GNF": 0, "ISK": 0, "JPY": 0, "KMF": 0, "KRW": 0,
+	"PYG": 0, "RWF": 0, "UGX": 0, "UYI": 0, "VND": 0, "VUV": 0, "XAF": 0, "XOF": 0, "XPF": 0,
+	"BHD": 3, "IQD": 3, "JOD": 3, "KWD": 3, "LYD": 3, "OMR": 3, "TND": 3,
+	"CLF": 4, "UYW": 4,
+}
+
+// MinorUnits 币种的小数位数（ISO 4217 minor unit），如CNY为2，JPY为0，KWD为3
+// 空币种按人民币处理
+func (c Currency) MinorUnits() int {
+	if units, ok := currencyMinorUnits[c]; ok {
+		return units
+	}
+	return 2
+}
+
+// CurrencyAmount 指定币种的金额，按币种的小数位数精确保存（如结算币种金额、支付币种金额）
+// 由于JSON中金额与币种是两个独立字段，这里保存原始的十进制字符串，使用时结合币种转换为最小单位
+type CurrencyAmount string
+
+// NewCurrencyAmount 以币种最小单位（如人民币的分、日元的円）创建金额
+func NewCurrencyAmount(minor int64, currency Currency) CurrencyAmount {
+	return CurrencyAmount(formatDecimal(minor, currency.MinorUnits()))
+}
+
+// ParseCurrencyAmount 解析指定币种的金额字符串，小数位数不能超过币种的小数位数
+func ParseCurrencyAmount(s string, currency Currency) (CurrencyAmount, error) {
+	minor, err := parseDecimal(s, currency.MinorUnits())
+	if err != nil {
+		return "", fmt.Errorf("invalid %s amount %q: %w", currency, s, err)
+	}
+	return NewCurrencyAmount(minor, currency), nil
+}
+
+// ParseAmountIn 解析指定币种的金额字符串，返回以该币种最小单位保存的 Amount，如JPY的"1000"为1000，KWD的"1.234"为1234
+// 请求参数指定标价币种（TransCurrency）时，金额字段需以该币种的最小单位保存
+func ParseAmountIn(s string, currency Currency) (Amount, error) {
+	minor, err := parseDecimal(s, currency.MinorUnits())
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s amount %q: %w", currency, s, err)
+	}
+	return Amount(minor), nil
+}
+
+// CurrencyAmount 将以币种最小单位保存的金额转换为 CurrencyAmount，币种为空时按人民币处理
+func (a Amount) CurrencyAmount(currency Currency) CurrencyAmount {
+	return NewCurrencyAmount(int64(a), currency)
+}
+
+// optionalCurrencyAmount 金额为0时返回空，用于 omitempty 的金额字段
+func optionalCurrencyAmount(a Amount, currency Currency) CurrencyAmount {
+	if a.IsZero() {
+		return ""
+	}
+	return a.CurrencyAmount(currency)
+}
+
+// Minor 转换为币种最小单位的整数
+func (c CurrencyAmount) Minor(currency Currency) (int64, error) {
+	if c == "" {
+		return 0, nil
+	}
+	return parseDecimal(string(c), currency.MinorUnits())
+}
+
+// Amount 转换为人民币金额，仅适用于CNY等两位小数的币种
+func (c CurrencyAmount) Amount() (Amount, error) {
+	if c == "" {
+		return 0, nil
+	}
+	return ParseAmount(string(c))
+}
+
+func (c CurrencyAmount) String() string {
+	return string(c)
+}
+
+func (c CurrencyAmount) MarshalJSON() ([]byte, error) {
+	return []byte(strconv.Quote(string(c))), nil
+}
+
+func (c *CurrencyAmount) UnmarshalJSON(data []byte) error {
+	var s string
+	if len(data) > 0 && data[0] == '"' {
+		var err error
+		if s, err = strconv.Unquote(string(data)); err != nil {
+			return err
+		}
+	} else if string(data) != "null" {
+		s = string(data)
+	}
+	// 只校验是否为合法的小数，精度在结合币种使用时校验
+	if s != "" && !isDecimal(s) {
+		return amountFormatErr
+	}
+	*c = CurrencyAmount(s)
+	return nil
+}
+
+// isDecimal 是否为合法的十进制小数字符串，如"1"、"-1.5"、"0.001"
+func isDecimal(s string) bool {
+	if len(s) > 0 && s[0] == '-' {
+		s = s[1:]
+	}
+	intPart, fracPart := s, ""
+	for i := 0; i < len(s); i++ {
+		if s[i] == '.' {
+			intPart, fracPart = s[:i], s[i+1:]
+			break
+		}
+	}
+	return intPart != "" && isDigits(intPart) && isDigits(fracPart)
+}
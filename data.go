@@ -0,0 +1,171 @@
+package alipay
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// DefaultBillQueryPageSize 账务明细、交易明细查询的默认分页大小（支付宝允许的最大值）
+const DefaultBillQueryPageSize = 2000
+
+// DataBillBalanceQuery 支付宝商家账户当前余额查询
+func (a *Client) DataBillBalanceQuery(requestParam DataBillBalanceQueryRequestParams) (
+	responseParam DataBillBalanceQueryResponseParams, err error) {
+	if err = a.HandlerRequest("POST", &requestParam, &responseParam); err != nil {
+		return
+	}
+	return
+}
+
+// DataBillAccountLogQuery 支付宝商家账户账务明细查询
+func (a *Client) DataBillAccountLogQuery(requestParam DataBillAccountLogQueryRequestParams) (
+	responseParam DataBillAccountLogQueryResponseParams, err error) {
+	if err = a.HandlerRequest("POST", &requestParam, &responseParam); err != nil {
+		return
+	}
+	return
+}
+
+// DataBillSellQuery 支付宝商家账户交易明细查询
+func (a *Client) DataBillSellQuery(requestParam DataBillSellQueryRequestParams) (
+	responseParam DataBillSellQueryResponseParams, err error) {
+	if err = a.HandlerRequest("POST", &requestParam, &responseParam); err != nil {
+		return
+	}
+	return
+}
+
+// pageCursor 分页查询的游标，按页号依次查询直到没有更多数据
+type pageCursor struct {
+	pageNo   int
+	pageSize int
+	index    int // 当前页中的位置
+	count    int // 当前页的记录数
+	fetched  int // 已查询的记录数
+	done     bool
+	err      error
+}
+
+// next 移动到下一条记录，当前页读取完时调用 fetch 查询下一页
+// fetch 返回该页的记录数及总记录数
+func (c *pageCursor) next(fetch func(pageNo, pageSize int) (count, total int, err error)) bool {
+	if c.err != nil {
+		return false
+	}
+	if c.index+1 < c.count {
+		c.index++
+		return true
+	}
+	if c.done {
+		return false
+	}
+	c.pageNo++
+	count, total, err := fetch(c.pageNo, c.pageSize)
+	if err != nil {
+		c.err = err
+		return false
+	}
+	c.fetched += count
+	c.index, c.count = 0, count
+	// 不足一页或已查询全部记录时结束
+	if count < c.pageSize || total > 0 && c.fetched >= total {
+		c.done = true
+	}
+	return count > 0
+}
+
+func newPageCursor(pageSize string) pageCursor {
+	size, _ := strconv.Atoi(pageSize)
+	if size <= 0 {
+		size = DefaultBillQueryPageSize
+	}
+	return pageCursor{pageSize: size}
+}
+
+// DataBillAccountLogIterator 账务明细分页迭代器，按页依次查询时间范围内的全部账务明细
+//
+//	iterator := c.DataBillAccountLogIterator(requestParam)
+//	for iterator.Next() {
+//		item := iterator.Item()
+//	}
+//	err := iterator.Err()
+type DataBillAccountLogIterator struct {
+	client       *Client
+	requestParam DataBillAccountLogQueryRequestParams
+	cursor       pageCursor
+	items        []AccountLogItemParams
+}
+
+// DataBillAccountLogIterator 创建账务明细分页迭代器，requestParam 中的 PageNo 会被忽略
+func (a *Client) DataBillAccountLogIterator(requestParam DataBillAccountLogQueryRequestParams) *DataBillAccountLogIterator {
+	return &DataBillAccountLogIterator{client: a, requestParam: requestParam, cursor: newPageCursor(requestParam.PageSize)}
+}
+
+// Next 移动到下一条账务明细，没有更多明细或查询出错时返回 false
+func (i *DataBillAccountLogIterator) Next() bool {
+	return i.cursor.next(func(pageNo, pageSize int) (count, total int, err error) {
+		i.requestParam.PageNo, i.requestParam.PageSize = strconv.Itoa(pageNo), strconv.Itoa(pageSize)
+		response, err := i.client.DataBillAccountLogQuery(i.requestParam)
+		if err != nil {
+			return
+		}
+		if response.Data.Code != SuccessCode {
+			err = fmt.Errorf("query account log page %d fail: %s %s %s", pageNo, response.Data.Code, response.Data.SubCode, response.Data.SubMsg)
+			return
+		}
+		i.items = response.Data.DetailList
+		total, _ = strconv.Atoi(response.Data.TotalSize)
+		return len(i.items), total, nil
+	})
+}
+
+// Item 当前账务明细
+func (i *DataBillAccountLogIterator) Item() AccountLogItemParams {
+	return i.items[i.cursor.index]
+}
+
+// Err 查询过程中出现的错误
+func (i *DataBillAccountLogIterator) Err() error {
+	return i.cursor.err
+}
+
+// DataBillSellIterator 交易明细分页迭代器，按页依次查询时间范围内的全部交易明细，用法同 DataBillAccountLogIterator
+type DataBillSellIterator struct {
+	client       *Client
+	requestParam DataBillSellQueryRequestParams
+	cursor       pageCursor
+	items        []TradeItemResultParams
+}
+
+// DataBillSellIterator 创建交易明细分页迭代器，requestParam 中的 PageNo 会被忽略
+func (a *Client) DataBillSellIterator(requestParam DataBillSellQueryRequestParams) *DataBillSellIterator {
+	return &DataBillSellIterator{client: a, requestParam: requestParam, cursor: newPageCursor(requestParam.PageSize)}
+}
+
+// Next 移动到下一条交易明细，没有更多明细或查询出错时返回 false
+func (i *DataBillSellIterator) Next() bool {
+	return i.cursor.next(func(pageNo, pageSize int) (count, total int, err error) {
+		i.requestParam.PageNo, i.requestParam.PageSize = strconv.Itoa(pageNo), strconv.Itoa(pageSize)
+		response, err := i.client.DataBillSellQuery(i.requestParam)
+		if err != nil {
+			return
+		}
+		if response.Data.Code != SuccessCode {
+			err = fmt.Errorf("query sell bill page %d fail: %s %s %s", pageNo, response.Data.Code, response.Data.SubCode, response.Data.SubMsg)
+			return
+		}
+		i.items = response.Data.DetailList
+		total, _ = strconv.Atoi(response.Data.TotalSize)
+		return len(i.items), total, nil
+	})
+}
+
+// Item 当前交易明细
+func (i *DataBillSellIterator) Item() TradeItemResultParams {
+	return i.items[i.cursor.index]
+}
+
+// Err 查询过程中出现的错误
+func (i *DataBillSellIterator) Err() error {
+	return i.cursor.err
+}
@@ -0,0 +1,208 @@
+package main
+
+import (
+	"alipay"
+	"alipay/utils"
+	"archive/zip"
+	"bytes"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"golang.org/x/text/encoding/simplifiedchinese"
+	"golang.org/x/text/transform"
+)
+
+// fakeGateway 模拟支付宝网关，使用测试生成的支付宝密钥对响应签名
+type fakeGateway struct {
+	mutex         sync.Mutex
+	aliPrivateKey *rsa.PrivateKey
+	handlers      map[string]func(bizContent map[string]interface{}) interface{}
+	calls         map[string]int
+	files         map[string][]byte // GET 请求的下载地址对应的文件内容，如账单
+}
+
+func (g *fakeGateway) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Method == http.MethodGet {
+		g.mutex.Lock()
+		file, ok := g.files[req.URL.String()]
+		g.mutex.Unlock()
+		if !ok {
+			return &http.Response{StatusCode: http.StatusNotFound, Body: io.NopCloser(strings.NewReader("")), Request: req}, nil
+		}
+		return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(bytes.NewReader(file)), Header: http.Header{}, Request: req}, nil
+	}
+	body, _ := io.ReadAll(req.Body)
+	values, err := url.ParseQuery(string(body))
+	if err != nil {
+		return nil, err
+	}
+	method := values.Get("method")
+	bizContent := map[string]interface{}{}
+	_ = json.Unmarshal([]byte(values.Get("biz_content")), &bizContent)
+
+	g.mutex.Lock()
+	g.calls[method]++
+	handler := g.handlers[method]
+	g.mutex.Unlock()
+	if handler == nil {
+		return nil, fmt.Errorf("unexpected method %s", method)
+	}
+	content, _ := json.Marshal(handler(bizContent))
+	sign, err := utils.RSASign(string(content), g.aliPrivateKey, alipay.SignTypeRSA2)
+	if err != nil {
+		return nil, err
+	}
+	nodeName := strings.Replace(method, ".", "_", -1) + alipay.ResponseSuffix
+	resBody := fmt.Sprintf(`{"%s":%s,"sign":"%s"}`, nodeName, content, sign)
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Body:       io.NopCloser(strings.NewReader(resBody)),
+		Header:     http.Header{},
+		Request:    req,
+	}, nil
+}
+
+func (g *fakeGateway) handle(method string, handler func(bizContent map[string]interface{}) interface{}) {
+	g.mutex.Lock()
+	g.handlers[method] = handler
+	g.mutex.Unlock()
+}
+
+func (g *fakeGateway) serveFile(fileUrl string, content []byte) {
+	g.mutex.Lock()
+	g.files[fileUrl] = content
+	g.mutex.Unlock()
+}
+
+func (g *fakeGateway) callCount(method string) int {
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+	return g.calls[method]
+}
+
+// newFakeGatewayClient 创建请求发送到模拟网关的Client
+func newFakeGatewayClient(t *testing.T) (*alipay.Client, *fakeGateway) {
+	appKeyPair, err := utils.GenerateRSAKeyPair(2048, utils.KeyFormatPKCS1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	aliKeyPair, err := utils.GenerateRSAKeyPair(2048, utils.KeyFormatPKCS1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	gateway := &fakeGateway{
+		aliPrivateKey: aliKeyPair.PrivateKey,
+		handlers:      make(map[string]func(bizContent map[string]interface{}) interface{}),
+		calls:         make(map[string]int),
+		files:         make(map[string][]byte),
+	}
+	c, err := alipay.NewClient("2016091200490539", aliKeyPair.RawPublicKey, appKeyPair.RawPrivateKey, alipay.SignTypeRSA2, false,
+		alipay.AddClient(&http.Client{Transport: gateway}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return c, gateway
+}
+
+// signNotify 使用模拟网关的支付宝私钥对异步通知签名，返回urlencoded的通知内容
+func (g *fakeGateway) signNotify(values url.Values) string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	pairs := make([]string, 0, len(keys))
+	for _, key := range keys {
+		pairs = append(pairs, key+"="+values.Get(key))
+	}
+	sign, _ := utils.RSASign(strings.Join(pairs, "&"), g.aliPrivateKey, alipay.SignTypeRSA2)
+	values.Set("sign", sign)
+	values.Set("sign_type", alipay.SignTypeRSA2)
+	return values.Encode()
+}
+
+// aliCert 使用模拟网关的支付宝私钥生成自签名的支付宝公钥证书，返回PEM格式的证书内容
+func (g *fakeGateway) aliCert(t *testing.T) string {
+	return g.aliCertExpiring(t, time.Now().Add(24*time.Hour))
+}
+
+// aliCertExpiring 生成在 notAfter 过期的自签名支付宝公钥证书
+func (g *fakeGateway) aliCertExpiring(t *testing.T, notAfter time.Time) string {
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: "fake alipay"},
+		NotBefore:    notAfter.Add(-365 * 24 * time.Hour),
+		NotAfter:     notAfter,
+	}
+	certDer, err := x509.CreateCertificate(rand.Reader, template, template, &g.aliPrivateKey.PublicKey, g.aliPrivateKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: certDer}))
+}
+
+// gbkZip 生成与支付宝账单格式相同的压缩包：文件名及内容均为GBK编码
+func gbkZip(t *testing.T, files map[string]string) []byte {
+	var buf bytes.Buffer
+	writer := zip.NewWriter(&buf)
+	encoder := simplifiedchinese.GBK.NewEncoder()
+	for name, content := range files {
+		gbkName, _, err := transform.String(encoder, name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		file, err := writer.CreateHeader(&zip.FileHeader{Name: gbkName, Method: zip.Deflate, NonUTF8: true})
+		if err != nil {
+			t.Fatal(err)
+		}
+		gbkContent, _, err := transform.String(encoder, content)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err = file.Write([]byte(gbkContent)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+// tradeBillZip 包含两笔交易、一笔退款的业务账单
+func tradeBillZip(t *testing.T) []byte {
+	return gbkZip(t, map[string]string{
+		"20881234_20220817_业务明细.csv": `#支付宝业务明细查询
+#账号：[20881234]
+#起始日期：[2022年08月17日 00:00:00]   终止日期：[2022年08月18日 00:00:00]
+#-----------------------------------------业务明细列表----------------------------------------
+支付宝交易号,商户订单号,业务类型,商品名称,创建时间,完成时间,门店编号,门店名称,操作员,终端号,对方账户,订单金额（元）,商家实收（元）,支付宝红包（元）,集分宝（元）,支付宝优惠（元）,商家优惠（元）,券核销金额（元）,券名称,商家红包消费金额（元）,卡消费金额（元）,退款批次号/请求号,服务费（元）,分润（元）,备注
+2022081722001	,T001	,交易,商品一,2022-08-17 10:00:00,2022-08-17 10:00:05,,,,,buyer@example.com,10.00,10.00,0.00,0.00,0.00,0.00,0.00,,0.00,0.00,,-0.06,0.00,
+2022081722002	,T002	,交易,商品二,2022-08-17 11:00:00,2022-08-17 11:00:05,,,,,buyer@example.com,5.00,5.00,0.00,0.00,0.00,0.00,0.00,,0.00,0.00,,-0.03,0.00,
+2022081722001	,T001	,退款,商品一,2022-08-17 10:00:00,2022-08-17 12:00:00,,,,,buyer@example.com,-2.00,-2.00,0.00,0.00,0.00,0.00,0.00,,0.00,0.00,R001	,0.01,0.00,
+#-----------------------------------------业务明细列表结束------------------------------------
+#交易合计：2笔，退款合计：1笔
+#导出时间：[2022年08月18日 09:00:00]
+`,
+		"20881234_20220817_业务明细(汇总).csv": `#支付宝业务汇总查询
+#-----------------------------------------业务汇总列表----------------------------------------
+门店编号,门店名称,交易订单总笔数,退款订单总笔数,订单金额（元）,商家实收（元）,服务费（元）,实收净额（元）
+合计,,2,1,13.00,13.00,-0.08,12.92
+#-----------------------------------------业务汇总列表结束------------------------------------
+`,
+	})
+}
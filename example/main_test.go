@@ -2,8 +2,19 @@ package main
 
 import (
 	"alipay"
+	"alipay/reconcile"
+	"alipay/utils"
+	"bytes"
+	"context"
+	"encoding/base64"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 )
@@ -43,7 +54,7 @@ func TestAlipayTradePreCreate(t *testing.T) {
 			AppAuthToken: "",
 		},
 		OutTradeNo:  fmt.Sprintf("%d", time.Now().UnixNano()),
-		TotalAmount: 100,
+		TotalAmount: alipay.MustParseAmount("100"),
 		Subject:     "统一收单线下交易预创建",
 	}
 	aliClient.AddEncryptKey(encryptKey)
@@ -66,7 +77,7 @@ func TestTradeAppPay(t *testing.T) {
 			AppAuthToken: "",
 		},
 		OutTradeNo:  fmt.Sprintf("%d", time.Now().UnixNano()),
-		TotalAmount: "0.01",
+		TotalAmount: alipay.MustParseAmount("0.01"),
 		Subject:     "app支付接口2.0",
 	}
 	res, err := aliClient.TradeAppPay(req)
@@ -87,7 +98,7 @@ func TestTradePagePay(t *testing.T) {
 			AppAuthToken: "",
 		},
 		OutTradeNo:  fmt.Sprintf("%d", time.Now().UnixNano()),
-		TotalAmount: "100",
+		TotalAmount: alipay.MustParseAmount("100"),
 		Subject:     "统一收单下单并支付页面接口",
 		ProductCode: "FAST_INSTANT_TRADE_PAY",
 	}
@@ -100,10 +111,56 @@ func TestTradePagePay(t *testing.T) {
 	t.Log("POST 返回值：", html)
 }
 
+func TestTradePagePayForm(t *testing.T) {
+	req := alipay.TradePagePayRequestParams{
+		OutTradeNo:     fmt.Sprintf("%d", time.Now().UnixNano()),
+		TotalAmount:    alipay.MustParseAmount("100"),
+		Subject:        `it's a "test" <script>alert(1)</script>`,
+		ProductCode:    "FAST_INSTANT_TRADE_PAY",
+		PassbackParams: "a='1'",
+	}
+	html, err := aliClient.TradePagePayForm(req, &alipay.FormOptions{FormId: "payForm", Target: "_blank"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(html, "<script>alert") || strings.Contains(html, "it's") || strings.Contains(html, "submit();") {
+		t.Fatalf("form is not escaped or should not auto submit: %s", html)
+	}
+	if !strings.Contains(html, `id="payForm"`) || !strings.Contains(html, `target="_blank"`) {
+		t.Fatalf("form options not applied: %s", html)
+	}
+
+	html, err = aliClient.TradePagePayForm(req, nil)
+	if err != nil || !strings.Contains(html, `document.forms["alipaySubmit"].submit();`) {
+		t.Fatalf("default form should auto submit: %s, %v", html, err)
+	}
+}
+
+func TestTradeWapPay(t *testing.T) {
+	req := alipay.TradeWapPayRequestParams{
+		OutTradeNo:  fmt.Sprintf("%d", time.Now().UnixNano()),
+		TotalAmount: alipay.MustParseAmount("0.01"),
+		Subject:     "手机网站支付",
+		QuitUrl:     "https://example.com/quit",
+	}
+	html, urlRe, err := aliClient.TradeWapPay(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	query := urlRe.Query()
+	if query.Get("method") != "alipay.trade.wap.pay" || !strings.Contains(query.Get("biz_content"), `"product_code":"QUICK_WAP_WAY"`) {
+		t.Fatalf("unexpected url %s", urlRe)
+	}
+	// URL 与 form 使用同一次签名
+	if !strings.Contains(html, query.Get("timestamp")) {
+		t.Fatalf("form and url should share the same signature: %s", html)
+	}
+}
+
 func TestFundTransUniTransfer(t *testing.T) {
 	req := alipay.FundTransUniTransferRequestParams{
 		OutBizNo:    fmt.Sprintf("%d", time.Now().UnixNano()),
-		TransAmount: 0.01,
+		TransAmount: alipay.MustParseAmount("0.01"),
 		ProductCode: "TRANS_ACCOUNT_NO_PWD",
 		BizScene:    "DIRECT_TRANSFER",
 		OrderTitle:  "单笔转账接口调试",
@@ -166,3 +223,1208 @@ func TestAesCBCDecrypt(t *testing.T) {
 	//}
 	//t.Log(res)
 }
+
+func TestFileCertStore(t *testing.T) {
+	store, err := alipay.NewFileCertStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = store.Get("80121e8b64901cf31d529c70dd6cd8c4"); !alipay.IsCertNotFound(err) {
+		t.Fatalf("Get err = %v, want cert not found", err)
+	}
+	if err = store.Put("80121e8b64901cf31d529c70dd6cd8c4", "certContent"); err != nil {
+		t.Fatal(err)
+	}
+	certContent, err := store.Get("80121e8b64901cf31d529c70dd6cd8c4")
+	if err != nil || certContent != "certContent" {
+		t.Fatalf("Get = %q, %v", certContent, err)
+	}
+	certSNs, _ := store.List()
+	if len(certSNs) != 1 || certSNs[0] != "80121e8b64901cf31d529c70dd6cd8c4" {
+		t.Fatalf("List = %v", certSNs)
+	}
+}
+
+func TestRefreshAliCertWithoutRootCert(t *testing.T) {
+	c, gateway := newFakeGatewayClient(t)
+	gateway.handle("alipay.open.app.alipaycert.download", func(bizContent map[string]interface{}) interface{} {
+		return map[string]string{"code": "10000", "msg": "Success", "alipay_cert_content": base64.StdEncoding.EncodeToString([]byte(gateway.aliCert(t)))}
+	})
+	// 未加载根证书时无法校验下载的证书，不应下载
+	if err := c.RefreshAliCert("80121e8b64901cf31d529c70dd6cd8c4"); err == nil {
+		t.Fatal("RefreshAliCert without root cert should fail")
+	}
+	if gateway.callCount("alipay.open.app.alipaycert.download") != 0 {
+		t.Fatal("alipay cert should not be downloaded without root cert")
+	}
+}
+
+func TestLoadAlipayRootCertSNConcurrent(t *testing.T) {
+	c, gateway := newFakeGatewayClient(t)
+	rootCert := gateway.aliCert(t)
+	req := alipay.TradePagePayRequestParams{OutTradeNo: "20220817010101004", TotalAmount: alipay.MustParseAmount("0.01"), Subject: "root cert sn"}
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			if err := c.LoadAlipayRootCertSN("", rootCert); err != nil {
+				t.Error(err)
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			if _, _, err := c.TradePagePay(req); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+	_, payUrl, err := c.TradePagePay(req)
+	if err != nil || payUrl.Query().Get("alipay_root_cert_sn") == "" {
+		t.Fatalf("TradePagePay = %v, %v, want alipay_root_cert_sn", payUrl, err)
+	}
+}
+
+func TestCheckCertificateExpiry(t *testing.T) {
+	c, gateway := newFakeGatewayClient(t)
+	var notified []alipay.CertificateInfo
+	alipay.AddCertExpiryNotifier(30*24*time.Hour, func(info alipay.CertificateInfo) {
+		notified = append(notified, info)
+	})(c)
+	if err := c.LoadAliCertSN("", gateway.aliCertExpiring(t, time.Now().Add(-3*time.Hour))); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 3; i++ {
+		expiring := c.CheckCertificateExpiry()
+		if len(expiring) != 1 || !expiring[0].Expired || expiring[0].DaysRemaining != -1 {
+			t.Fatalf("CheckCertificateExpiry = %+v", expiring)
+		}
+	}
+	if len(notified) != 1 {
+		t.Fatalf("notified %d times, want once", len(notified))
+	}
+	// 更换证书后对新证书重新预警
+	if err := c.LoadAliCertSN("", gateway.aliCertExpiring(t, time.Now().Add(10*24*time.Hour))); err != nil {
+		t.Fatal(err)
+	}
+	expiring := c.CheckCertificateExpiry()
+	if len(expiring) != 1 || expiring[0].Expired || expiring[0].DaysRemaining != 9 || len(notified) != 2 {
+		t.Fatalf("CheckCertificateExpiry = %+v, notified %d times", expiring, len(notified))
+	}
+}
+
+func TestSelfCheck(t *testing.T) {
+	c, err := NewClient()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = c.SelfCheck(); err != nil {
+		t.Fatal(err)
+	}
+	if err = c.LoadAppCertSN("", "invalid cert"); err == nil {
+		t.Fatal("LoadAppCertSN with invalid cert content should fail")
+	}
+
+	pathErr := &os.PathError{Op: "open", Path: "app_cert.crt", Err: os.ErrNotExist}
+	err = &alipay.SelfCheckError{Errors: []error{errors.New("the alipay public key is not set"), fmt.Errorf("load app cert: %w", pathErr)}}
+	var target *os.PathError
+	if !errors.Is(err, os.ErrNotExist) || !errors.As(err, &target) || target != pathErr {
+		t.Fatalf("errors.Is/errors.As should match the wrapped check errors of %v", err)
+	}
+	if errors.Is(err, os.ErrPermission) {
+		t.Fatal("errors.Is should not match unrelated errors")
+	}
+}
+
+func TestGenerateRSAKeyPair(t *testing.T) {
+	for _, format := range []string{utils.KeyFormatPKCS1, utils.KeyFormatPKCS8} {
+		keyPair, err := utils.GenerateRSAKeyPair(2048, format)
+		if err != nil {
+			t.Fatal(err)
+		}
+		c, err := alipay.NewClient("2016091200490539", keyPair.RawPublicKey, keyPair.RawPrivateKey, alipay.SignTypeRSA2, false,
+			alipay.AddAppPublicKey(keyPair.RawPublicKey))
+		if err != nil {
+			t.Fatalf("%s NewClient err:%s", format, err.Error())
+		}
+		if err = c.SelfCheck(); err != nil {
+			t.Fatalf("%s SelfCheck err:%s", format, err.Error())
+		}
+		if _, err = utils.GenerateCSR(keyPair.PrivateKey, utils.CSRSubject{CommonName: "app", Organization: "org"}); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestRotateAppKey(t *testing.T) {
+	c, gateway := newFakeGatewayClient(t)
+	keyPair, err := utils.GenerateRSAKeyPair(2048, utils.KeyFormatPKCS1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = c.RotateAppKey(keyPair.RawPrivateKey, gateway.aliCert(t), time.Time{}); err == nil {
+		t.Fatal("RotateAppKey with an app cert should fail in public key mode")
+	}
+	if report := c.CertificateStatus(); report.Mode != alipay.SignModePublicKey {
+		t.Fatalf("mode = %s, want %s", report.Mode, alipay.SignModePublicKey)
+	}
+	if err = c.RotateAppKey(keyPair.RawPrivateKey, "", time.Time{}); err != nil {
+		t.Fatal(err)
+	}
+	alipay.AddAppPublicKey(keyPair.RawPublicKey)(c)
+	if err = c.SelfCheck(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestAmount(t *testing.T) {
+	for _, c := range []struct {
+		in   string
+		fen  int64
+		fail bool
+	}{
+		{in: "0.01", fen: 1},
+		{in: "100", fen: 10000},
+		{in: "1.5", fen: 150},
+		{in: "1.500", fen: 150},
+		{in: "1.005", fail: true},
+		{in: "abc", fail: true},
+		{in: ".5", fail: true},
+	} {
+		amount, err := alipay.ParseAmount(c.in)
+		if (err != nil) != c.fail || !c.fail && amount.Fen() != c.fen {
+			t.Fatalf("ParseAmount(%q) = %d, %v", c.in, amount.Fen(), err)
+		}
+	}
+
+	var res alipay.TradeRefundResponseParams
+	if err := json.Unmarshal([]byte(`{"alipay_trade_refund_response":{"refund_fee":"88.88","send_back_fee":0.1}}`), &res); err != nil {
+		t.Fatal(err)
+	}
+	if res.Data.RefundFee.Fen() != 8888 || res.Data.SendBackFee.String() != "0.10" {
+		t.Fatalf("unmarshal amount = %s, %s", res.Data.RefundFee, res.Data.SendBackFee)
+	}
+	bytes, _ := json.Marshal(alipay.GoodsDetailParams{Price: alipay.AmountFromFen(1)})
+	if !strings.Contains(string(bytes), `"price":"0.01"`) {
+		t.Fatalf("marshal amount = %s", bytes)
+	}
+	if err := alipay.AmountFromFen(0).Validate(); err == nil {
+		t.Fatal("zero amount should be out of range")
+	}
+
+	// 要求数字类型的字段使用 NumberAmount
+	number := struct {
+		Amount alipay.NumberAmount `json:"amount"`
+	}{Amount: alipay.NumberAmount(alipay.MustParseAmount("8.8"))}
+	if bytes, _ = json.Marshal(number); string(bytes) != `{"amount":8.80}` {
+		t.Fatalf("marshal number amount = %s", bytes)
+	}
+	if err := json.Unmarshal([]byte(`{"amount":"0.01"}`), &number); err != nil || alipay.Amount(number.Amount) != alipay.MinAmount {
+		t.Fatalf("unmarshal number amount = %s, %v", number.Amount, err)
+	}
+}
+
+func TestCurrencyAmount(t *testing.T) {
+	if alipay.CurrencyJPY.MinorUnits() != 0 || alipay.CurrencyKWD.MinorUnits() != 3 || alipay.CurrencyUSD.MinorUnits() != 2 {
+		t.Fatal("unexpected currency minor units")
+	}
+	if _, err := alipay.ParseCurrencyAmount("100.5", alipay.CurrencyJPY); err == nil {
+		t.Fatal("JPY amount with decimals should fail")
+	}
+	amount := alipay.NewCurrencyAmount(1234, alipay.CurrencyKWD)
+	if amount != "1.234" {
+		t.Fatalf("NewCurrencyAmount = %s", amount)
+	}
+	var res alipay.TradeQueryResponseParams
+	if err := json.Unmarshal([]byte(`{"alipay_trade_query_response":{"settle_currency":"JPY","settle_amount":"1500"}}`), &res); err != nil {
+		t.Fatal(err)
+	}
+	if minor, err := res.Data.SettleAmount.Minor(res.Data.SettleCurrency); err != nil || minor != 1500 {
+		t.Fatalf("SettleAmount.Minor = %d, %v", minor, err)
+	}
+
+	// 指定标价币种时金额按币种的小数位数序列化
+	for _, c := range []struct {
+		currency alipay.Currency
+		in, want string
+	}{
+		{currency: alipay.CurrencyJPY, in: "1000", want: `"total_amount":"1000"`},
+		{currency: alipay.CurrencyKWD, in: "1.234", want: `"total_amount":"1.234"`},
+		{currency: "", in: "0.01", want: `"total_amount":"0.01"`},
+	} {
+		total, err := alipay.ParseAmountIn(c.in, c.currency)
+		if err != nil {
+			t.Fatal(err)
+		}
+		req := alipay.TradePagePayRequestParams{OutTradeNo: "20220817010101009", TotalAmount: total, Subject: "跨境支付", TransCurrency: c.currency}
+		if bizContent := req.GetOtherParams().Get("biz_content"); !strings.Contains(bizContent, c.want) {
+			t.Fatalf("%s biz_content = %s, want %s", c.currency, bizContent, c.want)
+		}
+	}
+	preCreate := alipay.TradePreCreateRequestParams{OutTradeNo: "20220817010101009", TotalAmount: 1234, DiscountableAmount: 1000, Subject: "跨境支付", TransCurrency: alipay.CurrencyKWD}
+	if bizContent := preCreate.GetOtherParams().Get("biz_content"); !strings.Contains(bizContent, `"total_amount":"1.234","discountable_amount":"1.000"`) {
+		t.Fatalf("KWD biz_content = %s", bizContent)
+	}
+	if err := json.Unmarshal([]byte(`{"alipay_trade_query_response":{"trans_currency":"KWD","total_amount":"1.234"}}`), &res); err != nil {
+		t.Fatal(err)
+	}
+	if minor, err := res.Data.TotalAmount.Minor(res.Data.TransCurrency); err != nil || minor != 1234 {
+		t.Fatalf("TotalAmount.Minor = %d, %v", minor, err)
+	}
+
+	// 金额范围按标价币种的小数位数校验
+	for _, c := range []struct {
+		currency alipay.Currency
+		in       string
+		valid    bool
+	}{
+		{currency: alipay.CurrencyJPY, in: "100000000", valid: true},
+		{currency: alipay.CurrencyJPY, in: "100000001", valid: false},
+		{currency: alipay.CurrencyKWD, in: "0.001", valid: true},
+		{currency: alipay.CurrencyKWD, in: "99999999.999", valid: true},
+		{currency: "", in: "100000000.01", valid: false},
+	} {
+		total, err := alipay.ParseAmountIn(c.in, c.currency)
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = alipay.ValidateRequest(&alipay.TradePayRequestParams{OutTradeNo: "20220817010101009", TotalAmount: total, Subject: "跨境支付",
+			AuthCode: "28763443825664394", Scene: "bar_code", TransCurrency: c.currency})
+		if (err == nil) != c.valid {
+			t.Fatalf("%s %s validate = %v, want valid %v", c.currency, c.in, err, c.valid)
+		}
+	}
+}
+
+func TestValidateRequest(t *testing.T) {
+	err := alipay.ValidateRequest(&alipay.TradeQueryRequestParams{})
+	if errs, ok := err.(alipay.ValidationErrors); !ok || len(errs) != 1 || errs[0].Rule != "oneof" {
+		t.Fatalf("TradeQuery validation = %v", err)
+	}
+	err = alipay.ValidateRequest(&alipay.TradePreCreateRequestParams{
+		OutTradeNo:  "order-1",
+		TotalAmount: alipay.MustParseAmount("0.01"),
+		GoodsDetail: []*alipay.GoodsDetailParams{{GoodsId: "1", GoodsName: "goods"}},
+	})
+	errs, _ := err.(alipay.ValidationErrors)
+	var fields []string
+	for _, e := range errs {
+		fields = append(fields, e.Field+":"+e.Rule)
+	}
+	if strings.Join(fields, ",") != "out_trade_no:pattern,subject:required,goods_detail[0].quantity:required" {
+		t.Fatalf("TradePreCreate validation fields = %v", fields)
+	}
+	if _, err = aliClient.TradeQuery(alipay.TradeQueryRequestParams{}); err == nil {
+		t.Fatal("TradeQuery without trade_no and out_trade_no should fail before sending")
+	}
+	if !alipay.IsValidationError(fmt.Errorf("refund: %w", err)) || alipay.IsValidationError(errors.New("timeout")) {
+		t.Fatal("IsValidationError should match wrapped validation errors only")
+	}
+	err = alipay.ValidateRequest(&alipay.FundAuthTradePayRequestParams{
+		OutTradeNo: "order-1", TotalAmount: alipay.MustParseAmount("0.01"), Subject: "租金", AuthNo: "A001", ProductCode: alipay.ProductCodePreAuth,
+	})
+	if errs, _ = err.(alipay.ValidationErrors); len(errs) != 1 || errs[0].Field != "out_trade_no" || errs[0].Rule != "pattern" {
+		t.Fatalf("FundAuthTradePay validation = %v", err)
+	}
+}
+
+func TestPayAndConfirm(t *testing.T) {
+	c, gateway := newFakeGatewayClient(t)
+	gateway.handle("alipay.trade.pay", func(bizContent map[string]interface{}) interface{} {
+		return map[string]string{"code": "10003", "msg": "order success pay inprocess", "out_trade_no": bizContent["out_trade_no"].(string)}
+	})
+	gateway.handle("alipay.trade.query", func(bizContent map[string]interface{}) interface{} {
+		status := "WAIT_BUYER_PAY"
+		if gateway.callCount("alipay.trade.query") >= 2 {
+			status = "TRADE_SUCCESS"
+		}
+		return map[string]string{"code": "10000", "msg": "Success", "trade_no": "2022", "trade_status": status}
+	})
+	req := alipay.TradePayRequestParams{
+		OutTradeNo:  "20220817010101004",
+		TotalAmount: alipay.MustParseAmount("0.01"),
+		Subject:     "条码支付",
+		AuthCode:    "28763443825664394",
+		Scene:       "bar_code",
+	}
+	opts := &alipay.PayConfirmOptions{QueryInterval: time.Millisecond, Timeout: time.Second}
+	result, err := c.PayAndConfirm(context.Background(), req, opts)
+	if err != nil || !result.Paid || result.TradeNo != "2022" {
+		t.Fatalf("PayAndConfirm = %+v, %v", result, err)
+	}
+
+	// 重复提交已支付的商户订单号
+	gateway.handle("alipay.trade.pay", func(bizContent map[string]interface{}) interface{} {
+		return map[string]string{"code": "40004", "msg": "Business Failed", "sub_code": alipay.SubCodeTradeHasSuccess, "sub_msg": "交易已被支付"}
+	})
+	queryCalls := gateway.callCount("alipay.trade.query")
+	result, err = c.PayAndConfirm(context.Background(), req, opts)
+	if err != nil || !result.Paid || result.Reason != "" || gateway.callCount("alipay.trade.query") != queryCalls {
+		t.Fatalf("PayAndConfirm with paid out_trade_no = %+v, %v", result, err)
+	}
+
+	// 一直等待用户支付，超时后撤销交易
+	gateway.handle("alipay.trade.pay", func(bizContent map[string]interface{}) interface{} {
+		return map[string]string{"code": "10003", "msg": "order success pay inprocess", "out_trade_no": bizContent["out_trade_no"].(string)}
+	})
+	gateway.handle("alipay.trade.query", func(bizContent map[string]interface{}) interface{} {
+		return map[string]string{"code": "40004", "msg": "Business Failed", "sub_code": alipay.SubCodeTradeNotExist}
+	})
+	gateway.handle("alipay.trade.cancel", func(bizContent map[string]interface{}) interface{} {
+		return map[string]string{"code": "10000", "msg": "Success", "action": "close"}
+	})
+	opts.Timeout = 20 * time.Millisecond
+	result, err = c.PayAndConfirm(context.Background(), req, opts)
+	if err != nil || result.Paid || !result.Cancelled || gateway.callCount("alipay.trade.cancel") != 1 {
+		t.Fatalf("PayAndConfirm timeout = %+v, %v", result, err)
+	}
+
+	// 撤销返回 retry_flag=Y 时间隔重试
+	var cancelTimes []time.Time
+	gateway.handle("alipay.trade.cancel", func(bizContent map[string]interface{}) interface{} {
+		cancelTimes = append(cancelTimes, time.Now())
+		if len(cancelTimes) < 3 {
+			return map[string]string{"code": "20000", "msg": "Service Currently Unavailable", "sub_code": "aop.ACQ.SYSTEM_ERROR", "retry_flag": "Y"}
+		}
+		return map[string]string{"code": "10000", "msg": "Success", "action": "close"}
+	})
+	opts.CancelRetryInterval = 20 * time.Millisecond
+	result, err = c.PayAndConfirm(context.Background(), req, opts)
+	if err != nil || !result.Cancelled || len(cancelTimes) != 3 {
+		t.Fatalf("PayAndConfirm cancel retry = %+v, %v", result, err)
+	}
+	if cancelTimes[1].Sub(cancelTimes[0]) < 20*time.Millisecond || cancelTimes[2].Sub(cancelTimes[1]) < 40*time.Millisecond {
+		t.Fatalf("cancel retries should back off, got %v", cancelTimes)
+	}
+
+	// ctx 取消后只撤销一次，不再等待重试
+	cancelTimes = nil
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	opts.CancelRetryInterval = time.Hour
+	if result, err = c.PayAndConfirm(ctx, req, opts); err == nil || result.Cancelled || len(cancelTimes) != 1 {
+		t.Fatalf("PayAndConfirm with cancelled ctx = %+v, %v, cancel calls %d", result, err, len(cancelTimes))
+	}
+}
+
+func TestWaitForTrade(t *testing.T) {
+	c, gateway := newFakeGatewayClient(t)
+	gateway.handle("alipay.trade.query", func(bizContent map[string]interface{}) interface{} {
+		switch gateway.callCount("alipay.trade.query") {
+		case 1, 2:
+			return map[string]string{"code": "40004", "msg": "Business Failed", "sub_code": alipay.SubCodeTradeNotExist}
+		case 3:
+			return map[string]string{"code": "10000", "msg": "Success", "trade_no": "2023", "trade_status": "WAIT_BUYER_PAY"}
+		}
+		return map[string]string{"code": "10000", "msg": "Success", "trade_no": "2023", "trade_status": "TRADE_SUCCESS"}
+	})
+	opts := &alipay.WaitTradeOptions{InitialInterval: time.Millisecond, MaxInterval: 2 * time.Millisecond, Multiplier: 2}
+	result, err := c.WaitForTrade(context.Background(), "20220817010101006", opts)
+	if err != nil || !result.Paid || result.TradeNo != "2023" || gateway.callCount("alipay.trade.query") != 4 {
+		t.Fatalf("WaitForTrade = %+v, %v", result, err)
+	}
+
+	// 超时未支付
+	gateway.handle("alipay.trade.query", func(bizContent map[string]interface{}) interface{} {
+		return map[string]string{"code": "10000", "msg": "Success", "trade_no": "2023", "trade_status": "WAIT_BUYER_PAY"}
+	})
+	opts.Timeout = 20 * time.Millisecond
+	if _, err = c.WaitForTrade(context.Background(), "20220817010101006", opts); err != alipay.ErrWaitTradeTimeout {
+		t.Fatalf("WaitForTrade timeout err = %v", err)
+	}
+	gateway.handle("alipay.trade.close", func(bizContent map[string]interface{}) interface{} {
+		return map[string]string{"code": "10000", "msg": "Success", "out_trade_no": bizContent["out_trade_no"].(string)}
+	})
+	opts.CloseOnTimeout = true
+	result, err = c.WaitForTrade(context.Background(), "20220817010101006", opts)
+	if err != nil || !result.ClosedByWait || result.TradeStatus != alipay.TradeStatusClosed {
+		t.Fatalf("WaitForTrade close = %+v, %v", result, err)
+	}
+}
+
+func TestTradeStatus(t *testing.T) {
+	if !alipay.TradeStatusWaitBuyerPay.CanTransitionTo(alipay.TradeStatusSuccess) ||
+		!alipay.TradeStatusSuccess.CanTransitionTo(alipay.TradeStatusClosed) ||
+		alipay.TradeStatusClosed.CanTransitionTo(alipay.TradeStatusSuccess) ||
+		alipay.TradeStatusFinished.CanTransitionTo(alipay.TradeStatusClosed) {
+		t.Fatal("unexpected trade status transitions")
+	}
+	if !alipay.TradeStatusSuccess.CanRefund() || alipay.TradeStatusFinished.CanRefund() || alipay.TradeStatusSuccess.IsTerminal() {
+		t.Fatal("unexpected trade status helpers")
+	}
+	var res alipay.FundTransUniTransferResponseParams
+	if err := json.Unmarshal([]byte(`{"alipay_fund_trans_uni_transfer_response":{"code":"10000","status":"DEALING"}}`), &res); err != nil {
+		t.Fatal(err)
+	}
+	if !res.Data.Status.IsProcessing() || res.Data.Status.IsTerminal() {
+		t.Fatalf("unexpected transfer status %s", res.Data.Status)
+	}
+}
+
+func TestRefundManager(t *testing.T) {
+	c, gateway := newFakeGatewayClient(t)
+	var outRequestNos []string
+	gateway.handle("alipay.trade.refund", func(bizContent map[string]interface{}) interface{} {
+		outRequestNos = append(outRequestNos, bizContent["out_request_no"].(string))
+		return map[string]string{"code": "20000", "msg": "Service Currently Unavailable", "sub_code": alipay.SubCodeSystemError}
+	})
+	gateway.handle("alipay.trade.fastpay.refund.query", func(bizContent map[string]interface{}) interface{} {
+		outRequestNos = append(outRequestNos, bizContent["out_request_no"].(string))
+		if gateway.callCount("alipay.trade.fastpay.refund.query") < 2 {
+			return map[string]string{"code": "10000", "msg": "Success"}
+		}
+		return map[string]string{"code": "10000", "msg": "Success", "refund_status": "REFUND_SUCCESS", "refund_amount": "0.01"}
+	})
+	manager := alipay.NewRefundManager(c, nil, nil)
+	req := alipay.RefundRequest{RefundKey: "AS001", OutTradeNo: "20220817010101007", RefundAmount: alipay.MustParseAmount("0.01")}
+	record, err := manager.Refund(context.Background(), req)
+	if err != nil || record.Status != alipay.RefundRecordSuccess {
+		t.Fatalf("Refund = %+v, %v", record, err)
+	}
+	for _, outRequestNo := range outRequestNos {
+		if outRequestNo != record.OutRequestNo {
+			t.Fatalf("out_request_no changed: %v", outRequestNos)
+		}
+	}
+	// 已成功的退款不会再次请求
+	if record, err = manager.Refund(context.Background(), req); err != nil || gateway.callCount("alipay.trade.refund") != 2 {
+		t.Fatalf("Refund again = %+v, %v, refund calls %d", record, err, gateway.callCount("alipay.trade.refund"))
+	}
+	req.RefundAmount = alipay.MustParseAmount("0.02")
+	if _, err = manager.Refund(context.Background(), req); err == nil {
+		t.Fatal("reusing refund key with different amount should fail")
+	}
+
+	// 重试等待期间 ctx 取消时立即返回，退款保持未确认
+	gateway.handle("alipay.trade.fastpay.refund.query", func(bizContent map[string]interface{}) interface{} {
+		return map[string]string{"code": "10000", "msg": "Success"}
+	})
+	manager = alipay.NewRefundManager(c, nil, &alipay.RefundManagerOptions{RetryInterval: time.Hour})
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	req = alipay.RefundRequest{RefundKey: "AS002", OutTradeNo: "20220817010101007", RefundAmount: alipay.MustParseAmount("0.01")}
+	if record, err = manager.Refund(ctx, req); !errors.Is(err, context.DeadlineExceeded) || record.Status != alipay.RefundRecordPending {
+		t.Fatalf("Refund with cancelled ctx = %+v, %v", record, err)
+	}
+}
+
+func TestTradePageRefund(t *testing.T) {
+	_, urlRe, err := aliClient.TradePageRefund("GET", alipay.TradePageRefundRequestParams{
+		OutTradeNo:   "20220817010101008",
+		OutRequestNo: "R20220817",
+		RefundAmount: alipay.MustParseAmount("2.5"),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if query := urlRe.Query(); query.Get("method") != "alipay.trade.page.refund" || !strings.Contains(query.Get("biz_content"), `"refund_amount":"2.50"`) {
+		t.Fatalf("unexpected url %s", urlRe)
+	}
+	if _, _, err = aliClient.TradePageRefund("GET", alipay.TradePageRefundRequestParams{OutRequestNo: "R20220817", RefundAmount: alipay.MustParseAmount("1")}); err == nil {
+		t.Fatal("page refund without out_trade_no and trade_no should fail")
+	}
+	notify := alipay.TradeNotificationParams{OutBizNo: "R20220817", RefundFee: alipay.MustParseAmount("2.5")}
+	if !notify.IsRefund() {
+		t.Fatalf("notify %+v should be a refund notify", notify)
+	}
+}
+
+func TestAsyncNotifyVerifySign(t *testing.T) {
+	c, gateway := newFakeGatewayClient(t)
+	values, err := url.ParseQuery(gateway.signNotify(url.Values{
+		"notify_type":  {"trade_status_sync"},
+		"out_trade_no": {"20220817010101008"},
+		"total_amount": {"10.00"},
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ok, err := c.AsyncNotifyVerifySign(values, false); !ok || err != nil {
+		t.Fatalf("AsyncNotifyVerifySign = %v, %v", ok, err)
+	}
+	tampered, _ := url.ParseQuery(values.Encode())
+	tampered.Set("total_amount", "11.00")
+	if _, err = c.AsyncNotifyVerifySign(tampered, false); err == nil {
+		t.Fatal("tampered notify should fail to verify")
+	}
+	if _, err = c.AsyncNotifyVerifySign(url.Values{"out_trade_no": {"20220817010101008"}}, false); err == nil {
+		t.Fatal("notify without sign should fail to verify")
+	}
+
+	// 证书模式下使用支付宝公钥证书中的公钥验签
+	certClient, err := alipay.NewClient("2016091200490539", "", "", alipay.SignTypeRSA2, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = certClient.AsyncNotifyVerifySign(values, false); err == nil {
+		t.Fatal("notify should fail to verify before the alipay cert is loaded")
+	}
+	if err = certClient.LoadAliCertSN("", gateway.aliCert(t)); err != nil {
+		t.Fatal(err)
+	}
+	if ok, err := certClient.AsyncNotifyVerifySign(values, false); !ok || err != nil {
+		t.Fatalf("AsyncNotifyVerifySign in cert mode = %v, %v", ok, err)
+	}
+}
+
+func TestAsyncNotifyDecode(t *testing.T) {
+	c, gateway := newFakeGatewayClient(t)
+	notify, err := c.AsyncNotify(gateway.signNotify(url.Values{
+		"notify_type":  {"trade_status_sync"},
+		"out_trade_no": {"20220817010101008"},
+		"out_biz_no":   {"R20220817"},
+		"trade_status": {"TRADE_SUCCESS"},
+		"total_amount": {"10.00"},
+		"refund_fee":   {"2.50"},
+		"gmt_refund":   {"2022-08-17 10:00:00.000"},
+		"subject":      {"a=b&c"},
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if notify.TotalAmount != "10.00" || notify.TradeStatus != alipay.TradeStatusSuccess || notify.Subject != "a=b&c" ||
+		!notify.IsRefund() || notify.RefundFee != alipay.MustParseAmount("2.5") {
+		t.Fatalf("unexpected notify %+v", notify)
+	}
+
+	depositBack, err := c.AsyncNotifyRefundDepositBack(gateway.signNotify(url.Values{
+		"msg_method":  {"alipay.trade.refund.depositback.completed"},
+		"notify_id":   {"2022081700001"},
+		"biz_content": {`{"out_trade_no":"20220817010101008","out_request_no":"R20220817","dback_status":"S","dback_amount":"2.50"}`},
+	}))
+	if err != nil || !depositBack.Content.IsSuccess() || depositBack.Content.DbackAmount != alipay.MustParseAmount("2.50") {
+		t.Fatalf("AsyncNotifyRefundDepositBack = %+v, %v", depositBack, err)
+	}
+}
+
+func TestDownloadBill(t *testing.T) {
+	c, gateway := newFakeGatewayClient(t)
+	billUrl := "https://dwbillcenter.alipay.com/downloadBillFile.resource?bizType=trade&fileId=1"
+	gateway.serveFile(billUrl, tradeBillZip(t))
+	gateway.handle("alipay.data.dataservice.bill.downloadurl.query", func(bizContent map[string]interface{}) interface{} {
+		return map[string]string{"code": "10000", "msg": "Success", "bill_download_url": billUrl}
+	})
+	// 账单下载到临时目录，Close 后删除
+	tempDir := t.TempDir()
+	t.Setenv("TMPDIR", tempDir)
+	bill, err := c.DownloadBill(context.Background(), alipay.BillTypeTrade, "2022-08-17")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if files, _ := os.ReadDir(tempDir); len(files) != 1 {
+		t.Fatalf("bill should be spooled to a temp file, got %d files", len(files))
+	}
+	iterator, err := bill.TradeRecords()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer iterator.Close()
+	var records []alipay.TradeBillRecord
+	for iterator.Next() {
+		records = append(records, iterator.Record())
+	}
+	if err = iterator.Err(); err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 3 || records[0].OutTradeNo != "T001" || records[0].ServiceFee != alipay.MustParseAmount("-0.06") ||
+		!records[2].IsRefund() || records[2].OutRequestNo != "R001" || records[2].TotalAmount != alipay.MustParseAmount("-2") {
+		t.Fatalf("unexpected records %+v", records)
+	}
+	if notes := iterator.Notes(); len(notes) == 0 || !strings.Contains(notes[len(notes)-2], "交易合计：2笔") {
+		t.Fatalf("unexpected notes %v", notes)
+	}
+	summary, err := bill.Summary()
+	if err != nil || len(summary.Rows) != 1 || summary.Rows[0][0] != "合计" || summary.Header[2] != "交易订单总笔数" {
+		t.Fatalf("Summary = %+v, %v", summary, err)
+	}
+	if err = bill.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if files, _ := os.ReadDir(tempDir); len(files) != 0 {
+		t.Fatalf("Close should remove the temp file, got %d files", len(files))
+	}
+}
+
+func TestReconcile(t *testing.T) {
+	bill, err := alipay.OpenBill(tradeBillZip(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	iterator, err := bill.TradeRecords()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer iterator.Close()
+	local := reconcile.NewSliceSource([]reconcile.LocalRecord{
+		{Type: reconcile.RecordTypeTrade, OutTradeNo: "T001", Amount: alipay.MustParseAmount("10"), Status: reconcile.LocalStatusSuccess},
+		{Type: reconcile.RecordTypeTrade, OutTradeNo: "T002", Amount: alipay.MustParseAmount("6"), Status: reconcile.LocalStatusSuccess},
+		{Type: reconcile.RecordTypeTrade, OutTradeNo: "T003", Amount: alipay.MustParseAmount("1"), Status: reconcile.LocalStatusSuccess},
+		{Type: reconcile.RecordTypeTrade, OutTradeNo: "T004", Amount: alipay.MustParseAmount("1"), Status: reconcile.LocalStatusPending},
+	})
+	report, err := reconcile.Reconcile(local, iterator, &reconcile.Options{BillDate: "2022-08-17"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	counts := report.Summary.Counts
+	if counts[reconcile.EntryMatched] != 1 || counts[reconcile.EntryAmountMismatch] != 1 ||
+		counts[reconcile.EntryMissingAlipay] != 1 || counts[reconcile.EntryMissingLocal] != 1 || !report.HasDifference() {
+		t.Fatalf("unexpected counts %v", counts)
+	}
+	if missing := report.Filter(reconcile.EntryMissingLocal); missing[0].OutRequestNo != "R001" || missing[0].AlipayAmount != alipay.MustParseAmount("2") {
+		t.Fatalf("unexpected missing local entries %+v", missing)
+	}
+	if report.Summary.ServiceFee != alipay.MustParseAmount("-0.08") || report.Summary.NetAmount != alipay.MustParseAmount("12.92") {
+		t.Fatalf("unexpected summary %+v", report.Summary)
+	}
+	var buf bytes.Buffer
+	if err = report.WriteCSV(&buf); err != nil || strings.Count(buf.String(), "\n") != len(report.Entries)+1 {
+		t.Fatalf("WriteCSV = %s, %v", buf.String(), err)
+	}
+
+	// 退款请求号为空的多笔退款对账键相同，逐条配对而不是互相覆盖
+	bills := &sliceBillSource{records: []alipay.TradeBillRecord{
+		{OutTradeNo: "T005", BizType: "交易", TotalAmount: alipay.MustParseAmount("10")},
+		{OutTradeNo: "T005", BizType: "退款", TotalAmount: alipay.MustParseAmount("-2")},
+		{OutTradeNo: "T005", BizType: "退款", TotalAmount: alipay.MustParseAmount("-3")},
+	}}
+	local = reconcile.NewSliceSource([]reconcile.LocalRecord{
+		{Type: reconcile.RecordTypeTrade, OutTradeNo: "T005", Amount: alipay.MustParseAmount("10"), Status: reconcile.LocalStatusSuccess},
+		{Type: reconcile.RecordTypeRefund, OutTradeNo: "T005", Amount: alipay.MustParseAmount("3"), Status: reconcile.LocalStatusSuccess},
+	})
+	if report, err = reconcile.Reconcile(local, bills, nil); err != nil {
+		t.Fatal(err)
+	}
+	counts = report.Summary.Counts
+	missing := report.Filter(reconcile.EntryMissingLocal)
+	if counts[reconcile.EntryMatched] != 2 || counts[reconcile.EntryMissingAlipay] != 0 || len(missing) != 1 ||
+		missing[0].AlipayAmount != alipay.MustParseAmount("2") || !missing[0].Duplicate || report.Summary.DuplicateKey != 1 {
+		t.Fatalf("unexpected duplicate refund report %+v", report)
+	}
+	if report.Summary.RefundCount != 2 || report.Summary.RefundAmount != alipay.MustParseAmount("5") {
+		t.Fatalf("unexpected duplicate refund summary %+v", report.Summary)
+	}
+}
+
+// sliceBillSource 以切片实现的账单明细数据源
+type sliceBillSource struct {
+	records []alipay.TradeBillRecord
+	index   int
+}
+
+func (s *sliceBillSource) Next() bool {
+	s.index++
+	return s.index <= len(s.records)
+}
+
+func (s *sliceBillSource) Record() alipay.TradeBillRecord {
+	return s.records[s.index-1]
+}
+
+func (s *sliceBillSource) Err() error {
+	return nil
+}
+
+func TestDataBillAccountLogIterator(t *testing.T) {
+	c, gateway := newFakeGatewayClient(t)
+	gateway.handle("alipay.data.bill.accountlog.query", func(bizContent map[string]interface{}) interface{} {
+		pageNo, _ := strconv.Atoi(bizContent["page_no"].(string))
+		var detailList []map[string]string
+		for i := 0; i < 2 && (pageNo-1)*2+i < 5; i++ {
+			detailList = append(detailList, map[string]string{"account_log_id": fmt.Sprintf("L%d", (pageNo-1)*2+i), "trans_amount": "1.00"})
+		}
+		return map[string]interface{}{"code": "10000", "msg": "Success", "page_no": bizContent["page_no"], "total_size": "5", "detail_list": detailList}
+	})
+	iterator := c.DataBillAccountLogIterator(alipay.DataBillAccountLogQueryRequestParams{
+		StartTime: "2022-08-17 00:00:00",
+		EndTime:   "2022-08-18 00:00:00",
+		PageSize:  "2",
+	})
+	var ids []string
+	for iterator.Next() {
+		ids = append(ids, iterator.Item().AccountLogId)
+	}
+	if err := iterator.Err(); err != nil {
+		t.Fatal(err)
+	}
+	if strings.Join(ids, ",") != "L0,L1,L2,L3,L4" || gateway.callCount("alipay.data.bill.accountlog.query") != 3 {
+		t.Fatalf("unexpected account logs %v", ids)
+	}
+}
+
+func TestFundTransCommonQuery(t *testing.T) {
+	c, gateway := newFakeGatewayClient(t)
+	gateway.handle("alipay.fund.trans.common.query", func(bizContent map[string]interface{}) interface{} {
+		return map[string]string{"code": "10000", "msg": "Success", "out_biz_no": bizContent["out_biz_no"].(string),
+			"order_id": "20220817110070000006030000000001", "status": "FAIL", "trans_amount": "1.00",
+			"error_code": "PAYEE_NOT_EXIST", "fail_reason": "收款账号不存在"}
+	})
+	if _, err := c.FundTransCommonQuery(alipay.FundTransCommonQueryRequestParams{}); err == nil {
+		t.Fatal("expected validation error without out_biz_no and order_id")
+	}
+	response, err := c.FundTransCommonQuery(alipay.FundTransCommonQueryRequestParams{
+		ProductCode: alipay.ProductCodeTransAccountNoPwd,
+		BizScene:    "DIRECT_TRANSFER",
+		OutBizNo:    "P20220817001",
+	})
+	if err != nil || !response.Data.Status.IsFailed() || response.Data.FailReason != "收款账号不存在" ||
+		response.Data.TransAmount != alipay.MustParseAmount("1") {
+		t.Fatalf("FundTransCommonQuery = %+v, %v", response, err)
+	}
+
+	rawBody := gateway.signNotify(url.Values{
+		"msg_method":  {"alipay.fund.trans.order.changed"},
+		"notify_id":   {"2022081700002"},
+		"biz_content": {`{"action_type":"REFUND","out_biz_no":"P20220817002","order_id":"20220817110070000006030000000002","status":"REFUND","trans_amount":"3.00","error_code":"CARD_INFO_ERROR","fail_reason":"银行退票"}`},
+	})
+	notify, err := c.AsyncNotifyFundTransOrderChanged(rawBody)
+	if err != nil || notify.Content.Status != alipay.TransferStatusRefund || notify.Content.FailReason != "银行退票" ||
+		notify.Content.TransAmount != alipay.MustParseAmount("3") {
+		t.Fatalf("AsyncNotifyFundTransOrderChanged = %+v, %v", notify, err)
+	}
+}
+
+func TestPayoutManager(t *testing.T) {
+	c, gateway := newFakeGatewayClient(t)
+	gateway.handle("alipay.fund.trans.uni.transfer", func(bizContent map[string]interface{}) interface{} {
+		switch outBizNo := bizContent["out_biz_no"].(string); outBizNo {
+		case "P002":
+			// 结果未知，需查询确认
+			return map[string]string{"code": "20000", "msg": "Service Currently Unavailable", "sub_code": alipay.SubCodeFundSystemError}
+		case "P003":
+			return map[string]string{"code": "40004", "msg": "Business Failed", "sub_code": "PAYEE_NOT_EXIST", "sub_msg": "收款账号不存在"}
+		case "P004":
+			return map[string]string{"code": "10000", "msg": "Success", "out_biz_no": outBizNo, "order_id": "O" + outBizNo, "status": "DEALING"}
+		default:
+			return map[string]string{"code": "10000", "msg": "Success", "out_biz_no": outBizNo, "order_id": "O" + outBizNo, "status": "SUCCESS"}
+		}
+	})
+	bankSettled := false
+	gateway.handle("alipay.fund.trans.common.query", func(bizContent map[string]interface{}) interface{} {
+		outBizNo := bizContent["out_biz_no"].(string)
+		status := "SUCCESS"
+		if outBizNo == "P004" && !bankSettled {
+			status = "DEALING"
+		}
+		return map[string]string{"code": "10000", "msg": "Success", "out_biz_no": outBizNo, "order_id": "O" + outBizNo, "status": status}
+	})
+
+	var items []alipay.PayoutItem
+	for i := 1; i <= 4; i++ {
+		items = append(items, alipay.PayoutItem{
+			OutBizNo:    fmt.Sprintf("P%03d", i),
+			TransAmount: alipay.MustParseAmount("1.00"),
+			PayeeInfo:   &alipay.Participant{Identity: fmt.Sprintf("208800000000000%d", i), IdentityType: "ALIPAY_USER_ID"},
+		})
+	}
+	manager := alipay.NewPayoutManager(c, nil, &alipay.PayoutOptions{Concurrency: 2, RateLimit: 100})
+	if _, err := manager.Run(context.Background(), "B001", append(items, items[0])); err == nil {
+		t.Fatal("duplicate out_biz_no should fail")
+	}
+	report, err := manager.Run(context.Background(), "B001", items)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.SuccessCount != 2 || report.FailedCount != 1 || report.ProcessingCount != 1 || report.Done() ||
+		report.SuccessAmount != alipay.MustParseAmount("2") || report.Records[2].FailReason == "" {
+		t.Fatalf("unexpected report %+v", report)
+	}
+
+	// 再次运行同一批次：已成功或失败的付款不再转账，处理中的付款只查询
+	transferCalls := gateway.callCount("alipay.fund.trans.uni.transfer")
+	bankSettled = true
+	if report, err = manager.Run(context.Background(), "B001", items); err != nil || !report.Done() || report.SuccessCount != 3 {
+		t.Fatalf("Run again = %+v, %v", report, err)
+	}
+	if gateway.callCount("alipay.fund.trans.uni.transfer") != transferCalls {
+		t.Fatalf("transfer called again for confirmed payouts")
+	}
+	if _, err = manager.Run(context.Background(), "B002", items[:1]); err == nil {
+		t.Fatal("out_biz_no used by another batch should fail")
+	}
+
+	// 转账到银行卡成功后被退票，查询时付款变为失败；转账到支付宝账户的成功付款不再查询
+	queryCalls := gateway.callCount("alipay.fund.trans.common.query")
+	if record, err := manager.Query("P001"); err != nil || record.Status != alipay.PayoutRecordSuccess ||
+		gateway.callCount("alipay.fund.trans.common.query") != queryCalls {
+		t.Fatalf("Query account payout = %+v, %v", record, err)
+	}
+	gateway.handle("alipay.fund.trans.common.query", func(bizContent map[string]interface{}) interface{} {
+		return map[string]string{"code": "10000", "msg": "Success", "out_biz_no": bizContent["out_biz_no"].(string), "status": "REFUND", "fail_reason": "收款账户已销户"}
+	})
+	bankManager := alipay.NewPayoutManager(c, nil, &alipay.PayoutOptions{ProductCode: alipay.ProductCodeTransBankcardNoPwd})
+	bankItem := alipay.PayoutItem{OutBizNo: "P005", TransAmount: alipay.MustParseAmount("1.00"), PayeeInfo: &alipay.Participant{Identity: "6216610000000000000", IdentityType: "BANKCARD_ACCOUNT", Name: "张三"}}
+	if report, err = bankManager.Run(context.Background(), "B004", []alipay.PayoutItem{bankItem}); err != nil || report.SuccessCount != 1 {
+		t.Fatalf("Run bank card payout = %+v, %v", report, err)
+	}
+	if record, err := bankManager.Query("P005"); err != nil || record.Status != alipay.PayoutRecordFailed || record.TransferStatus != alipay.TransferStatusRefund {
+		t.Fatalf("Query refunded bank card payout = %+v, %v", record, err)
+	}
+
+	// 重试等待期间 ctx 取消时立即返回，付款保持未确认
+	unknown := alipay.PayoutItem{OutBizNo: "P002", TransAmount: alipay.MustParseAmount("1.00"), PayeeInfo: items[1].PayeeInfo}
+	gateway.handle("alipay.fund.trans.common.query", func(bizContent map[string]interface{}) interface{} {
+		return map[string]string{"code": "20000", "msg": "Service Currently Unavailable", "sub_code": alipay.SubCodeFundSystemError}
+	})
+	manager = alipay.NewPayoutManager(c, nil, &alipay.PayoutOptions{RetryInterval: time.Hour})
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	if report, err = manager.Run(ctx, "B003", []alipay.PayoutItem{unknown}); !errors.Is(err, context.DeadlineExceeded) ||
+		report == nil || report.PendingCount != 1 || time.Since(start) > time.Minute {
+		t.Fatalf("Run with cancelled ctx = %+v, %v", report, err)
+	}
+}
+
+func TestFundAccountQuery(t *testing.T) {
+	c, gateway := newFakeGatewayClient(t)
+	gateway.handle("alipay.fund.account.query", func(bizContent map[string]interface{}) interface{} {
+		return map[string]string{"code": "10000", "msg": "Success", "available_amount": "1.50", "freeze_amount": "0.50"}
+	})
+	gateway.handle("alipay.fund.trans.uni.transfer", func(bizContent map[string]interface{}) interface{} {
+		return map[string]string{"code": "10000", "msg": "Success", "out_biz_no": bizContent["out_biz_no"].(string), "status": "SUCCESS"}
+	})
+	response, err := c.FundAccountQuery(alipay.FundAccountQueryRequestParams{AlipayUserId: "2088000000000000"})
+	if err != nil || response.Data.AvailableAmount != alipay.MustParseAmount("1.5") || response.Data.FreezeAmount != alipay.MustParseAmount("0.5") {
+		t.Fatalf("FundAccountQuery = %+v, %v", response, err)
+	}
+
+	manager := alipay.NewPayoutManager(c, nil, &alipay.PayoutOptions{BalanceUserId: "2088000000000000"})
+	items := []alipay.PayoutItem{
+		{OutBizNo: "P101", TransAmount: alipay.MustParseAmount("1"), PayeeInfo: &alipay.Participant{Identity: "2088000000000001", IdentityType: "ALIPAY_USER_ID"}},
+		{OutBizNo: "P102", TransAmount: alipay.MustParseAmount("1"), PayeeInfo: &alipay.Participant{Identity: "2088000000000002", IdentityType: "ALIPAY_USER_ID"}},
+	}
+	if _, err = manager.Run(context.Background(), "B101", items); !errors.Is(err, alipay.ErrInsufficientBalance) {
+		t.Fatalf("Run with insufficient balance = %v", err)
+	}
+	if gateway.callCount("alipay.fund.trans.uni.transfer") != 0 {
+		t.Fatal("transfer should not be called when the balance is insufficient")
+	}
+	if report, err := manager.Run(context.Background(), "B101", items[:1]); err != nil || report.SuccessCount != 1 {
+		t.Fatalf("Run = %+v, %v", report, err)
+	}
+
+	// 已发起转账但结果未确认的付款可能已扣款，余额检查时不再计入
+	gateway.handle("alipay.fund.trans.uni.transfer", func(bizContent map[string]interface{}) interface{} {
+		if gateway.callCount("alipay.fund.trans.uni.transfer") == 2 {
+			return map[string]string{"code": "20000", "msg": "Service Currently Unavailable", "sub_code": alipay.SubCodeFundSystemError}
+		}
+		return map[string]string{"code": "10000", "msg": "Success", "out_biz_no": bizContent["out_biz_no"].(string), "status": "SUCCESS"}
+	})
+	gateway.handle("alipay.fund.trans.common.query", func(bizContent map[string]interface{}) interface{} {
+		return map[string]string{"code": "20000", "msg": "Service Currently Unavailable", "sub_code": alipay.SubCodeFundSystemError}
+	})
+	manager = alipay.NewPayoutManager(c, nil, &alipay.PayoutOptions{BalanceUserId: "2088000000000000", MaxAttempts: 1})
+	items = []alipay.PayoutItem{
+		{OutBizNo: "P103", TransAmount: alipay.MustParseAmount("1"), PayeeInfo: &alipay.Participant{Identity: "2088000000000003", IdentityType: "ALIPAY_USER_ID"}},
+		{OutBizNo: "P104", TransAmount: alipay.MustParseAmount("1"), PayeeInfo: &alipay.Participant{Identity: "2088000000000004", IdentityType: "ALIPAY_USER_ID"}},
+	}
+	if report, err := manager.Run(context.Background(), "B102", items[:1]); err != nil || report.PendingCount != 1 {
+		t.Fatalf("Run with unknown result = %+v, %v", report, err)
+	}
+	if report, err := manager.Run(context.Background(), "B102", items); err != nil || report.SuccessCount != 2 {
+		t.Fatalf("Run with previously attempted payout = %+v, %v", report, err)
+	}
+}
+
+func TestFundAuth(t *testing.T) {
+	c, gateway := newFakeGatewayClient(t)
+	gateway.handle("alipay.fund.auth.order.voucher.create", func(bizContent map[string]interface{}) interface{} {
+		if bizContent["product_code"] != string(alipay.ProductCodePreAuth) {
+			return map[string]string{"code": "40004", "msg": "Business Failed", "sub_code": "PRODUCT_CODE_INVALID"}
+		}
+		return map[string]string{"code": "10000", "msg": "Success", "out_order_no": "F001", "code_type": "qrCode", "code_value": "https://qr.alipay.com/xxx"}
+	})
+	gateway.handle("alipay.trade.pay", func(bizContent map[string]interface{}) interface{} {
+		return map[string]interface{}{"code": "10000", "msg": "Success", "trade_no": "2022081722001", "out_trade_no": bizContent["out_trade_no"],
+			"total_amount": bizContent["total_amount"], "auth_no": bizContent["auth_no"]}
+	})
+	gateway.handle("alipay.fund.auth.order.unfreeze", func(bizContent map[string]interface{}) interface{} {
+		return map[string]interface{}{"code": "10000", "msg": "Success", "auth_no": bizContent["auth_no"], "amount": bizContent["amount"], "status": "SUCCESS"}
+	})
+	gateway.handle("alipay.fund.auth.operation.detail.query", func(bizContent map[string]interface{}) interface{} {
+		return map[string]string{"code": "10000", "msg": "Success", "auth_no": "A001", "order_status": "FINISH", "operation_type": "UNFREEZE",
+			"status": "SUCCESS", "rest_amount": "0.00", "total_pay_amount": "60.00"}
+	})
+
+	voucher, err := c.FundAuthOrderVoucherCreate(alipay.FundAuthOrderVoucherCreateRequestParams{
+		OutOrderNo: "F001", OutRequestNo: "F001-1", OrderTitle: "租赁押金", Amount: alipay.MustParseAmount("100"),
+	})
+	if err != nil || voucher.Data.CodeValue == "" {
+		t.Fatalf("FundAuthOrderVoucherCreate = %+v, %v", voucher, err)
+	}
+	orderStr, err := c.FundAuthOrderAppFreeze(alipay.FundAuthOrderAppFreezeRequestParams{
+		OutOrderNo: "F002", OutRequestNo: "F002-1", OrderTitle: "租赁押金", Amount: alipay.MustParseAmount("100"),
+	})
+	if err != nil || !strings.Contains(orderStr, "alipay.fund.auth.order.app.freeze") || !strings.Contains(orderStr, "PRE_AUTH_ONLINE") {
+		t.Fatalf("FundAuthOrderAppFreeze = %s, %v", orderStr, err)
+	}
+	pay, err := c.FundAuthTradePay(alipay.FundAuthTradePayRequestParams{
+		OutTradeNo: "T001", TotalAmount: alipay.MustParseAmount("60"), Subject: "租金", AuthNo: "A001", AuthConfirmMode: "NOT_COMPLETE",
+	})
+	if err != nil || pay.Data.TradeNo == "" || pay.Data.TotalAmount != "60.00" {
+		t.Fatalf("FundAuthTradePay = %+v, %v", pay, err)
+	}
+	unfreeze, err := c.FundAuthOrderUnfreeze(alipay.FundAuthOrderUnfreezeRequestParams{
+		AuthNo: "A001", OutRequestNo: "F001-2", Amount: alipay.MustParseAmount("40"), Remark: "归还解冻",
+	})
+	if err != nil || !unfreeze.Data.Status.IsSuccess() || unfreeze.Data.Amount != alipay.MustParseAmount("40") {
+		t.Fatalf("FundAuthOrderUnfreeze = %+v, %v", unfreeze, err)
+	}
+	detail, err := c.FundAuthOperationDetailQuery(alipay.FundAuthOperationDetailQueryRequestParams{AuthNo: "A001", OutRequestNo: "F001-2"})
+	if err != nil || !detail.Data.OrderStatus.IsTerminal() || detail.Data.OperationType != alipay.FundAuthOperationUnfreeze {
+		t.Fatalf("FundAuthOperationDetailQuery = %+v, %v", detail, err)
+	}
+	if _, err = c.FundAuthOperationCancel(alipay.FundAuthOperationCancelRequestParams{AuthNo: "A001", Remark: "撤销"}); err == nil {
+		t.Fatal("cancel without operation_id and out_request_no should fail")
+	}
+
+	notify, err := c.AsyncNotifyFundAuth(gateway.signNotify(url.Values{
+		"notify_type":    {"fund_auth_freeze"},
+		"auth_no":        {"A001"},
+		"out_order_no":   {"F001"},
+		"operation_type": {"FREEZE"},
+		"status":         {"SUCCESS"},
+		"amount":         {"100.00"},
+		"rest_amount":    {"100.00"},
+	}))
+	if err != nil || notify.OperationType != alipay.FundAuthOperationFreeze || !notify.Status.IsSuccess() || notify.RestAmount != alipay.MustParseAmount("100") {
+		t.Fatalf("AsyncNotifyFundAuth = %+v, %v", notify, err)
+	}
+}
+
+func TestUserAgreement(t *testing.T) {
+	c, gateway := newFakeGatewayClient(t)
+	gateway.handle("alipay.user.agreement.query", func(bizContent map[string]interface{}) interface{} {
+		return map[string]string{"code": "10000", "msg": "Success", "agreement_no": "20225817000001", "status": "NORMAL", "next_deduct_time": "2022-09-17"}
+	})
+	gateway.handle("alipay.user.agreement.executionplan.modify", func(bizContent map[string]interface{}) interface{} {
+		return map[string]interface{}{"code": "10000", "msg": "Success", "agreement_no": bizContent["agreement_no"], "deduct_time": bizContent["deduct_time"]}
+	})
+	gateway.handle("alipay.trade.pay", func(bizContent map[string]interface{}) interface{} {
+		agreementParams := bizContent["agreement_params"].(map[string]interface{})
+		if bizContent["product_code"] != string(alipay.ProductCodeCyclePayAuth) || agreementParams["agreement_no"] != "20225817000001" {
+			return map[string]string{"code": "40004", "msg": "Business Failed", "sub_code": "ACQ.AGREEMENT_NOT_EXIST"}
+		}
+		return map[string]interface{}{"code": "10000", "msg": "Success", "trade_no": "2022081722002", "out_trade_no": bizContent["out_trade_no"]}
+	})
+
+	_, signUrl, err := c.UserAgreementPageSign("GET", alipay.UserAgreementPageSignRequestParams{
+		PersonalProductCode: alipay.PersonalProductCodeCyclePayAuth,
+		ProductCode:         alipay.ProductCodeCyclePayAuth,
+		SignScene:           "INDUSTRY|MEMBER",
+		AccessParams:        &alipay.AccessParams{Channel: "ALIPAYAPP"},
+		PeriodRuleParams:    &alipay.PeriodRuleParams{PeriodType: "MONTH", Period: 1, ExecuteTime: "2022-08-17", SingleAmount: alipay.MustParseAmount("30")},
+	})
+	if err != nil || signUrl.Query().Get("method") != "alipay.user.agreement.page.sign" {
+		t.Fatalf("UserAgreementPageSign = %v, %v", signUrl, err)
+	}
+	query, err := c.UserAgreementQuery(alipay.UserAgreementQueryRequestParams{AgreementNo: "20225817000001"})
+	if err != nil || !query.Data.Status.IsNormal() || query.Data.NextDeductTime != "2022-09-17" {
+		t.Fatalf("UserAgreementQuery = %+v, %v", query, err)
+	}
+	modify, err := c.UserAgreementExecutionPlanModify(alipay.UserAgreementExecutionPlanModifyRequestParams{AgreementNo: "20225817000001", DeductTime: "2022-09-20"})
+	if err != nil || modify.Data.DeductTime != "2022-09-20" {
+		t.Fatalf("UserAgreementExecutionPlanModify = %+v, %v", modify, err)
+	}
+	pay, err := c.AgreementTradePay(alipay.AgreementTradePayRequestParams{
+		OutTradeNo: "S001", TotalAmount: alipay.MustParseAmount("30"), Subject: "月度会员",
+		AgreementParams: &alipay.AgreementParamsParams{AgreementNo: "20225817000001"},
+	})
+	if err != nil || pay.Data.Code != alipay.SuccessCode {
+		t.Fatalf("AgreementTradePay = %+v, %v", pay, err)
+	}
+
+	notify, err := c.AsyncNotifyUserAgreement(gateway.signNotify(url.Values{
+		"notify_type":  {"dut_user_unsign"},
+		"agreement_no": {"20225817000001"},
+		"status":       {"UNSIGN"},
+		"unsign_time":  {"2022-09-01 10:00:00"},
+	}))
+	if err != nil || !notify.IsUnsign() || notify.Status != alipay.AgreementStatusUnsign {
+		t.Fatalf("AsyncNotifyUserAgreement = %+v, %v", notify, err)
+	}
+}
+
+func TestSubscriptionScheduler(t *testing.T) {
+	c, gateway := newFakeGatewayClient(t)
+	var outTradeNos []string
+	gateway.handle("alipay.trade.pay", func(bizContent map[string]interface{}) interface{} {
+		outTradeNos = append(outTradeNos, bizContent["out_trade_no"].(string))
+		if len(outTradeNos) < 3 {
+			return map[string]string{"code": "40004", "msg": "Business Failed", "sub_code": "ACQ.BUYER_BALANCE_NOT_ENOUGH"}
+		}
+		return map[string]interface{}{"code": "10000", "msg": "Success", "trade_no": "2022081822001", "out_trade_no": bizContent["out_trade_no"]}
+	})
+	var deductTimes []string
+	gateway.handle("alipay.user.agreement.executionplan.modify", func(bizContent map[string]interface{}) interface{} {
+		deductTimes = append(deductTimes, bizContent["deduct_time"].(string))
+		return map[string]interface{}{"code": "10000", "msg": "Success", "agreement_no": bizContent["agreement_no"], "deduct_time": bizContent["deduct_time"]}
+	})
+
+	cst := time.FixedZone("CST", 8*3600)
+	store := alipay.NewMemorySubscriptionStore()
+	scheduler := alipay.NewSubscriptionScheduler(c, store, &alipay.SubscriptionSchedulerOptions{
+		RetryIntervals: []time.Duration{time.Hour, 24 * time.Hour},
+		Location:       cst,
+	})
+	err := scheduler.Add(alipay.Subscription{
+		SubscriptionId: "SUB1",
+		AgreementNo:    "20225817000001",
+		Subject:        "月度会员",
+		PeriodRule:     alipay.PeriodRuleParams{PeriodType: alipay.PeriodTypeDay, Period: 30, ExecuteTime: "2022-08-17", SingleAmount: alipay.MustParseAmount("30")},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	steps := []struct {
+		now    time.Time
+		status alipay.SubscriptionChargeStatus
+	}{
+		{time.Date(2022, 8, 16, 10, 0, 0, 0, cst), ""},
+		{time.Date(2022, 8, 17, 10, 0, 0, 0, cst), alipay.SubscriptionChargeRetry},
+		{time.Date(2022, 8, 17, 11, 0, 0, 0, cst), alipay.SubscriptionChargeRetry}, // 重试顺延到 2022-08-18
+		{time.Date(2022, 8, 17, 12, 0, 0, 0, cst), ""},
+		{time.Date(2022, 8, 18, 11, 0, 0, 0, cst), alipay.SubscriptionChargeSuccess},
+	}
+	for _, step := range steps {
+		charges, err := scheduler.RunDue(ctx, step.now)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if step.status == "" && len(charges) != 0 || step.status != "" && (len(charges) != 1 || charges[0].Status != step.status) {
+			t.Fatalf("RunDue at %s = %+v", step.now, charges)
+		}
+	}
+	// 顺延只影响当期，下一期仍从首期扣款日期计算，并恢复协议的执行计划
+	if strings.Join(outTradeNos, ",") != "SUB1_1,SUB1_1,SUB1_1" || strings.Join(deductTimes, ",") != "2022-08-18,2022-09-16" {
+		t.Fatalf("unexpected out_trade_no %v or deduct_time %v", outTradeNos, deductTimes)
+	}
+	subscription, err := store.Get("SUB1")
+	if err != nil || subscription.PaidCount != 1 || subscription.NextDeductDate != "2022-09-16" || subscription.PeriodIndex != 1 {
+		t.Fatalf("unexpected subscription %+v, %v", subscription, err)
+	}
+
+	if next := alipay.AddPeriod(time.Date(2022, 1, 31, 0, 0, 0, 0, cst), alipay.PeriodTypeMonth, 1); next.Format("2006-01-02") != "2022-02-28" {
+		t.Fatalf("AddPeriod = %s", next)
+	}
+}
+
+func TestSubscriptionSchedulerUnconfirmedCharge(t *testing.T) {
+	c, gateway := newFakeGatewayClient(t)
+	var outTradeNos []string
+	gateway.handle("alipay.trade.pay", func(bizContent map[string]interface{}) interface{} {
+		outTradeNo := bizContent["out_trade_no"].(string)
+		outTradeNos = append(outTradeNos, outTradeNo)
+		switch outTradeNo {
+		case "SUB2_1":
+			// 结果未知，最后一次重试后查询确认
+			return map[string]string{"code": "20000", "msg": "Service Currently Unavailable", "sub_code": alipay.SubCodeSystemError}
+		case "SUB3_1":
+			return map[string]string{"code": "40004", "msg": "Business Failed", "sub_code": alipay.SubCodeTradeHasClose}
+		}
+		return map[string]interface{}{"code": "10000", "msg": "Success", "trade_no": "T" + outTradeNo, "out_trade_no": outTradeNo}
+	})
+	gateway.handle("alipay.trade.query", func(bizContent map[string]interface{}) interface{} {
+		return map[string]interface{}{"code": "10000", "msg": "Success", "trade_no": "TSUB2_1", "out_trade_no": bizContent["out_trade_no"], "trade_status": "TRADE_SUCCESS"}
+	})
+	gateway.handle("alipay.user.agreement.executionplan.modify", func(bizContent map[string]interface{}) interface{} {
+		return map[string]interface{}{"code": "10000", "msg": "Success", "agreement_no": bizContent["agreement_no"], "deduct_time": bizContent["deduct_time"]}
+	})
+
+	cst := time.FixedZone("CST", 8*3600)
+	store := alipay.NewMemorySubscriptionStore()
+	scheduler := alipay.NewSubscriptionScheduler(c, store, &alipay.SubscriptionSchedulerOptions{
+		RetryIntervals: []time.Duration{time.Hour},
+		Location:       cst,
+	})
+	rule := alipay.PeriodRuleParams{PeriodType: alipay.PeriodTypeMonth, Period: 1, ExecuteTime: "2022-08-17", SingleAmount: alipay.MustParseAmount("30")}
+	for _, subscription := range []alipay.Subscription{
+		{SubscriptionId: "SUB2", AgreementNo: "20225817000002", Subject: "月度会员", PeriodRule: rule},
+		{SubscriptionId: "SUB3", AgreementNo: "20225817000003", Subject: "月度会员", PeriodRule: rule},
+		{SubscriptionId: "SUB4", AgreementNo: "20225817000004", PeriodRule: rule}, // 缺少订单标题
+	} {
+		if err := scheduler.Add(subscription); err != nil {
+			t.Fatal(err)
+		}
+	}
+	ctx := context.Background()
+	charges, err := scheduler.RunDue(ctx, time.Date(2022, 8, 17, 10, 0, 0, 0, cst))
+	if err != nil || len(charges) != 3 || charges[0].Status != alipay.SubscriptionChargeRetry ||
+		charges[1].Status != alipay.SubscriptionChargeRetry || charges[2].Status != alipay.SubscriptionChargeStopped {
+		t.Fatalf("RunDue = %+v, %v", charges, err)
+	}
+	charges, err = scheduler.RunDue(ctx, time.Date(2022, 8, 17, 11, 0, 0, 0, cst))
+	if err != nil || len(charges) != 2 || charges[0].Status != alipay.SubscriptionChargeSuccess || charges[0].TradeNo != "TSUB2_1" ||
+		charges[1].Status != alipay.SubscriptionChargeSuccess || charges[1].OutTradeNo != "SUB3_1_2" {
+		t.Fatalf("RunDue retry = %+v, %v", charges, err)
+	}
+	if strings.Join(outTradeNos, ",") != "SUB2_1,SUB3_1,SUB2_1,SUB3_1_2" {
+		t.Fatalf("unexpected out_trade_no %v", outTradeNos)
+	}
+	subscription, err := store.Get("SUB2")
+	if err != nil || subscription.PaidCount != 1 || subscription.PaidAmount != rule.SingleAmount || subscription.NextDeductDate != "2022-09-17" {
+		t.Fatalf("unexpected subscription %+v, %v", subscription, err)
+	}
+	if subscription, err = store.Get("SUB4"); err != nil || subscription.Status != alipay.SubscriptionStopped || subscription.Attempts != 1 {
+		t.Fatalf("subscription with invalid params should stop, got %+v, %v", subscription, err)
+	}
+}
+
+func TestTradeOrderSettle(t *testing.T) {
+	c, gateway := newFakeGatewayClient(t)
+	gateway.handle("alipay.trade.royalty.relation.bind", func(bizContent map[string]interface{}) interface{} {
+		return map[string]string{"code": "10000", "msg": "Success", "result_code": "SUCCESS"}
+	})
+	gateway.handle("alipay.trade.order.settle", func(bizContent map[string]interface{}) interface{} {
+		royalty := bizContent["royalty_parameters"].([]interface{})[0].(map[string]interface{})
+		if royalty["amount"] != "3.00" || royalty["desc"] != "平台服务费" {
+			return map[string]string{"code": "40004", "msg": "Business Failed", "sub_code": "ACQ.INVALID_PARAMETER"}
+		}
+		return map[string]interface{}{"code": "10000", "msg": "Success", "trade_no": bizContent["trade_no"], "settle_no": "S20220817001"}
+	})
+	gateway.handle("alipay.trade.order.settle.query", func(bizContent map[string]interface{}) interface{} {
+		return map[string]interface{}{"code": "10000", "msg": "Success", "out_request_no": "ST001",
+			"royalty_detail_list": []map[string]string{{"operation_type": "transfer", "trans_in": "2088000000000001", "amount": "3.00", "state": "SUCCESS"}}}
+	})
+
+	bind, err := c.TradeRoyaltyRelationBind(alipay.TradeRoyaltyRelationBindRequestParams{
+		OutRequestNo: "RB001",
+		ReceiverList: []*alipay.RoyaltyEntity{{Type: "userId", Account: "2088000000000001"}},
+	})
+	if err != nil || bind.Data.ResultCode != "SUCCESS" {
+		t.Fatalf("TradeRoyaltyRelationBind = %+v, %v", bind, err)
+	}
+	settle, err := c.TradeOrderSettle(alipay.TradeOrderSettleRequestParams{
+		OutRequestNo: "ST001",
+		TradeNo:      "2022081722001",
+		RoyaltyParameters: []*alipay.OpenApiRoyaltyDetailInfoPojoParams{
+			{TransInType: "userId", TransIn: "2088000000000001", Amount: alipay.MustParseAmount("3"), Desc: "平台服务费"},
+		},
+	})
+	if err != nil || settle.Data.SettleNo == "" {
+		t.Fatalf("TradeOrderSettle = %+v, %v", settle, err)
+	}
+	if _, err = c.TradeOrderSettleQuery(alipay.TradeOrderSettleQueryRequestParams{}); err == nil {
+		t.Fatal("settle query without settle_no and out_request_no should fail")
+	}
+	query, err := c.TradeOrderSettleQuery(alipay.TradeOrderSettleQueryRequestParams{SettleNo: settle.Data.SettleNo})
+	if err != nil || len(query.Data.RoyaltyDetailList) != 1 || query.Data.RoyaltyDetailList[0].State != alipay.SettleDetailStateSuccess {
+		t.Fatalf("TradeOrderSettleQuery = %+v, %v", query, err)
+	}
+
+	notify, err := c.AsyncNotifyTradeOrderSettle(gateway.signNotify(url.Values{
+		"msg_method":  {"alipay.trade.order.settle.notify"},
+		"biz_content": {`{"trade_no":"2022081722001","settle_no":"S20220817001","out_request_no":"ST001","royalty_detail_list":[{"amount":"3.00","state":"FAIL","error_code":"PAYEE_NOT_EXIST","error_desc":"收款账号不存在"}]}`},
+	}))
+	if err != nil || len(notify.Content.RoyaltyDetailList) != 1 || notify.Content.RoyaltyDetailList[0].State != alipay.SettleDetailStateFail {
+		t.Fatalf("AsyncNotifyTradeOrderSettle = %+v, %v", notify, err)
+	}
+
+	// 分账描述为字符串
+	bytes, _ := json.Marshal(alipay.RoyaltyInfo{RoyaltyDetailInfos: []*alipay.RoyaltyDetailInfos{{TransIn: "2088000000000001", Desc: "分账"}}})
+	if !strings.Contains(string(bytes), `"desc":"分账"`) {
+		t.Fatalf("unexpected royalty info %s", bytes)
+	}
+}
@@ -0,0 +1,72 @@
+package alipay
+
+import (
+	"bytes"
+	"html/template"
+	"net/url"
+	"sort"
+)
+
+// DefaultFormId 页面支付表单默认的id及name
+const DefaultFormId = "alipaySubmit"
+
+// FormOptions 页面支付表单的渲染选项
+type FormOptions struct {
+	FormId     string // 表单的id及name，默认为 alipaySubmit
+	Target     string // 表单提交的目标窗口，如 _blank、_self 或 iframe 的name，为空时不设置
+	AutoSubmit bool   // 是否在页面加载后自动提交表单
+}
+
+// DefaultFormOptions 默认的表单渲染选项：id为 alipaySubmit 并自动提交
+func DefaultFormOptions() *FormOptions {
+	return &FormOptions{FormId: DefaultFormId, AutoSubmit: true}
+}
+
+type formField struct {
+	Name  string
+	Value string
+}
+
+type formData struct {
+	FormOptions
+	Action string
+	Fields []formField
+}
+
+// requestFormTemplate 使用 html/template 渲染，所有属性值及脚本中的表单id都会按上下文转义
+var requestFormTemplate = template.Must(template.New("alipayForm").Parse(
+	`<form id="{{.FormId}}" name="{{.FormId}}" action="{{.Action}}" method="POST"{{if .Target}} target="{{.Target}}"{{end}}>` +
+		`{{range .Fields}}<input type="hidden" name="{{.Name}}" value="{{.Value}}"/>{{end}}` +
+		`<input type="submit" value="ok" style="display:none;"></form>` +
+		`{{if .AutoSubmit}}<script>document.forms[{{.FormId}}].submit();</script>{{end}}`))
+
+// renderRequestForm 以表单HTML形式构造请求，表单字段按名称排序
+// urlValues: 签名后的请求参数
+func (a *Client) renderRequestForm(urlValues url.Values, opts *FormOptions) (formHtml string, err error) {
+	if opts == nil {
+		opts = DefaultFormOptions()
+	}
+	data := formData{
+		FormOptions: *opts,
+		Action:      a.gatewayUrl + "?charset=" + url.QueryEscape(a.charset),
+	}
+	if data.FormId == "" {
+		data.FormId = DefaultFormId
+	}
+	names := make([]string, 0, len(urlValues))
+	for name, values := range urlValues {
+		if len(values) > 0 {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		data.Fields = append(data.Fields, formField{Name: name, Value: urlValues.Get(name)})
+	}
+	var buf bytes.Buffer
+	if err = requestFormTemplate.Execute(&buf, data); err != nil {
+		return
+	}
+	formHtml = buf.String()
+	return
+}
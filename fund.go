@@ -1,5 +1,13 @@
 package alipay
 
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrInsufficientBalance 账户可用余额不足
+var ErrInsufficientBalance = errors.New("the available balance of the alipay account is insufficient")
+
 // FundTransUniTransfer 单笔转账接口
 func (a *Client) FundTransUniTransfer(requestParam FundTransUniTransferRequestParams) (
 	responseParam FundTransUniTransferResponseParams, err error) {
@@ -8,3 +16,45 @@ func (a *Client) FundTransUniTransfer(requestParam FundTransUniTransferRequestPa
 	}
 	return
 }
+
+// FundTransCommonQuery 转账业务单据查询接口，用于单笔转账结果未知（如网络超时）时按商户订单号或支付宝转账单据号确认转账状态
+func (a *Client) FundTransCommonQuery(requestParam FundTransCommonQueryRequestParams) (
+	responseParam FundTransCommonQueryResponseParams, err error) {
+	if err = a.HandlerRequest("POST", &requestParam, &responseParam); err != nil {
+		return
+	}
+	return
+}
+
+// AsyncNotifyFundTransOrderChanged 处理资金单据状态变更通知（alipay.fund.trans.order.changed）
+// 转账到银行卡等场景在单据进入 SUCCESS、FAIL 或 REFUND（退票）时发送该通知，失败原因见 Content.FailReason
+func (a *Client) AsyncNotifyFundTransOrderChanged(rawBody string) (notifyResult FundTransOrderChangedNotificationParams, err error) {
+	err = a.verifyAndDecodeNotify(rawBody, false, &notifyResult, &notifyResult.Content)
+	return
+}
+
+// FundAccountQuery 支付宝资金账户资产查询接口
+func (a *Client) FundAccountQuery(requestParam FundAccountQueryRequestParams) (
+	responseParam FundAccountQueryResponseParams, err error) {
+	if err = a.HandlerRequest("POST", &requestParam, &responseParam); err != nil {
+		return
+	}
+	return
+}
+
+// CheckFundBalance 转账前检查资金账户可用余额是否足够支付 amount，余额不足时返回 ErrInsufficientBalance 可判断的错误
+func (a *Client) CheckFundBalance(alipayUserId string, accountType FundAccountType, amount Amount) (availableAmount Amount, err error) {
+	response, err := a.FundAccountQuery(FundAccountQueryRequestParams{AlipayUserId: alipayUserId, AccountType: accountType})
+	if err != nil {
+		return
+	}
+	if response.Data.Code != SuccessCode {
+		err = fmt.Errorf("query fund account %s fail: %s %s %s", alipayUserId, response.Data.Code, response.Data.SubCode, response.Data.SubMsg)
+		return
+	}
+	availableAmount = response.Data.AvailableAmount
+	if availableAmount < amount {
+		err = fmt.Errorf("%w: available %s, required %s", ErrInsufficientBalance, availableAmount, amount)
+	}
+	return
+}
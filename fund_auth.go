@@ -0,0 +1,71 @@
+package alipay
+
+// 资金授权（预授权）：冻结 → 转支付/解冻，结果未知时可撤销
+//
+// 线上冻结使用 FundAuthOrderAppFreeze 生成App调用的签名字符串，线下冻结使用 FundAuthOrderVoucherCreate 生成二维码，
+// 冻结、解冻的结果通过 AsyncNotifyFundAuth 处理异步通知或通过 FundAuthOperationDetailQuery 查询确认
+
+// FundAuthOrderAppFreeze 线上资金授权冻结接口，返回调用支付宝App的签名字符串，未指定 ProductCode 时默认为 PRE_AUTH_ONLINE
+func (a *Client) FundAuthOrderAppFreeze(requestParam FundAuthOrderAppFreezeRequestParams) (result string, err error) {
+	if requestParam.ProductCode == "" {
+		requestParam.ProductCode = ProductCodePreAuthOnline
+	}
+	return a.HandlerSDKRequest(&requestParam)
+}
+
+// FundAuthOrderVoucherCreate 资金授权发码接口，未指定 ProductCode 时默认为 PRE_AUTH
+func (a *Client) FundAuthOrderVoucherCreate(requestParam FundAuthOrderVoucherCreateRequestParams) (
+	responseParam FundAuthOrderVoucherCreateResponseParams, err error) {
+	if requestParam.ProductCode == "" {
+		requestParam.ProductCode = ProductCodePreAuth
+	}
+	if err = a.HandlerRequest("POST", &requestParam, &responseParam); err != nil {
+		return
+	}
+	return
+}
+
+// FundAuthOrderUnfreeze 资金授权解冻接口
+func (a *Client) FundAuthOrderUnfreeze(requestParam FundAuthOrderUnfreezeRequestParams) (
+	responseParam FundAuthOrderUnfreezeResponseParams, err error) {
+	if err = a.HandlerRequest("POST", &requestParam, &responseParam); err != nil {
+		return
+	}
+	return
+}
+
+// FundAuthOperationDetailQuery 资金授权操作查询接口
+func (a *Client) FundAuthOperationDetailQuery(requestParam FundAuthOperationDetailQueryRequestParams) (
+	responseParam FundAuthOperationDetailQueryResponseParams, err error) {
+	if err = a.HandlerRequest("POST", &requestParam, &responseParam); err != nil {
+		return
+	}
+	return
+}
+
+// FundAuthOperationCancel 资金授权撤销接口，仅用于冻结、解冻结果未知时撤销，已成功的操作请使用解冻
+func (a *Client) FundAuthOperationCancel(requestParam FundAuthOperationCancelRequestParams) (
+	responseParam FundAuthOperationCancelResponseParams, err error) {
+	if err = a.HandlerRequest("POST", &requestParam, &responseParam); err != nil {
+		return
+	}
+	return
+}
+
+// FundAuthTradePay 预授权转支付，使用 alipay.trade.pay 将冻结的资金转为支付，未指定 ProductCode 时默认为 PRE_AUTH_ONLINE
+func (a *Client) FundAuthTradePay(requestParam FundAuthTradePayRequestParams) (
+	responseParam TradePayResponseParams, err error) {
+	if requestParam.ProductCode == "" {
+		requestParam.ProductCode = ProductCodePreAuthOnline
+	}
+	if err = a.HandlerRequest("POST", &requestParam, &responseParam); err != nil {
+		return
+	}
+	return
+}
+
+// AsyncNotifyFundAuth 处理资金授权冻结、解冻异步通知
+func (a *Client) AsyncNotifyFundAuth(rawBody string) (notifyResult FundAuthNotificationParams, err error) {
+	err = a.verifyAndDecodeNotify(rawBody, false, &notifyResult, nil)
+	return
+}
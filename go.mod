@@ -2,7 +2,10 @@ module alipay
 
 go 1.17
 
-require github.com/gin-gonic/gin v1.7.7
+require (
+	github.com/gin-gonic/gin v1.7.7
+	golang.org/x/text v0.3.7
+)
 
 require (
 	github.com/gin-contrib/sse v0.1.0 // indirect
@@ -0,0 +1,103 @@
+package alipay
+
+import (
+	"alipay/utils"
+	"crypto/rsa"
+	"crypto/x509"
+	"errors"
+	"time"
+)
+
+var (
+	appCertRequiredErr   = errors.New("the app public key certificate is required in cert mode")
+	appCertUnexpectedErr = errors.New("the app public key certificate must be empty in public key mode")
+)
+
+// appSigningKey 应用签名密钥，证书模式下包含对应的应用公钥证书
+type appSigningKey struct {
+	privateKey *rsa.PrivateKey
+	appCertSN  string
+	appCert    *x509.Certificate
+	activateAt time.Time // 切换时间
+}
+
+// signingKey 获取当前用于签名的应用私钥及应用公钥证书序列号，待切换的密钥到达切换时间时先完成切换
+func (a *Client) signingKey() (appPrivateKey *rsa.PrivateKey, appCertSN string) {
+	a.mutex.RLock()
+	appPrivateKey, appCertSN = a.appPrivateKey, a.appCertSN
+	nextAppKey := a.nextAppKey
+	a.mutex.RUnlock()
+	if nextAppKey == nil || time.Now().Before(nextAppKey.activateAt) {
+		return
+	}
+
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+	// 加写锁后再次检查，避免并发请求重复切换
+	if a.nextAppKey == nextAppKey {
+		a.activateAppKeyLocked(nextAppKey)
+	}
+	return a.appPrivateKey, a.appCertSN
+}
+
+// activateAppKeyLocked 切换当前应用私钥，调用方需持有写锁
+func (a *Client) activateAppKeyLocked(key *appSigningKey) {
+	a.appPrivateKey = key.privateKey
+	if key.appCert != nil {
+		a.appCertSN = key.appCertSN
+		a.appCert = key.appCert
+	} else {
+		// 公钥模式下原应用公钥已不再对应当前私钥
+		a.appPublicKey, a.appPublicKeyRaw = nil, ""
+	}
+	a.nextAppKey = nil
+}
+
+// RotateAppKey 轮换应用私钥，不需要重新创建Client，切换过程中的请求不受影响
+// appPrivateKey 新的应用私钥（不包含begin，end）
+// appCertContent 新的应用公钥证书内容（包含begin，end），证书模式下必填，公钥模式下必须为空，与当前模式不一致时返回错误
+// activateAt 切换时间，为零值或早于当前时间时立即切换，否则在该时间之后的第一个请求时切换
+// 在支付宝开放平台更新应用公钥（证书）后，将 activateAt 设置为开放平台生效的时间即可实现无缝切换
+func (a *Client) RotateAppKey(appPrivateKey, appCertContent string, activateAt time.Time) (err error) {
+	key := &appSigningKey{activateAt: activateAt}
+	if key.privateKey, err = utils.ParsePrivateKey(utils.GetPemPrivate(appPrivateKey)); err != nil {
+		return
+	}
+
+	a.mutex.RLock()
+	isCertMode := a.appCertSN != ""
+	a.mutex.RUnlock()
+	// 新密钥的加签模式需与当前模式一致，避免切换后公钥模式的Client变为证书模式
+	if isCertMode && appCertContent == "" {
+		return appCertRequiredErr
+	}
+	if !isCertMode && appCertContent != "" {
+		return appCertUnexpectedErr
+	}
+	if appCertContent != "" {
+		var publicKey *rsa.PublicKey
+		if publicKey, key.appCert, err = utils.GetPublicKeyFromCertContent(appCertContent); err != nil {
+			return
+		}
+		if publicKey == nil || !isKeyPairMatched(key.privateKey, publicKey) {
+			return errors.New("the app private key does not match the app public key certificate")
+		}
+		key.appCertSN = getCertSN(key.appCert)
+	}
+
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+	if activateAt.IsZero() || !time.Now().Before(activateAt) {
+		a.activateAppKeyLocked(key)
+		return
+	}
+	a.nextAppKey = key
+	return
+}
+
+// CancelAppKeyRotation 取消尚未切换的应用私钥轮换
+func (a *Client) CancelAppKeyRotation() {
+	a.mutex.Lock()
+	a.nextAppKey = nil
+	a.mutex.Unlock()
+}
@@ -127,25 +127,25 @@ type AuthTokenAppResponseParams struct {
 type TradeCreateRequestParams struct {
 	OtherRequestParams
 
-	OutTradeNo           string                     `json:"out_trade_no"`                    // 商户订单号。由商家自定义，64个字符以内，仅支持字母、数字、下划线且需保证在商户端不重复。
-	TotalAmount          float64                    `json:"total_amount"`                    // 订单总金额，单位为元，精确到小数点后两位，取值范围为 [0.01,100000000]，金额不能为 0。如果同时传入了【可打折金额】，【不可打折金额】，【订单总金额】三者，则必须满足如下条件：【订单总金额】=【可打折金额】+【不可打折金额】
-	Subject              string                     `json:"subject"`                         // 订单标题。 注意：不可使用特殊字符，如 /，=，& 等。
-	ProductCode          string                     `json:"product_code"`                    // 销售产品码。如果签约的是当面付快捷版，则传 OFFLINE_PAYMENT；其它支付宝当面付产品传 FACE_TO_FACE_PAYMENT；不传则默认使用 FACE_TO_FACE_PAYMENT。
-	SellerId             string                     `json:"seller_id,omitempty"`             // 卖家支付宝用户 ID。 当需要指定收款账号时，通过该参数传入，如果该值为空，则默认为商户签约账号对应的支付宝用户ID。 收款账号优先级规则：门店绑定的收款账户>请求传入的seller_id>商户签约账号对应的支付宝用户ID； 注：直付通和机构间联场景下seller_id无需传入或者保持跟pid一致；如果传入的seller_id与pid不一致，需要联系支付宝小二配置收款关系；
-	BuyerId              string                     `json:"buyer_id,omitempty"`              // 买家支付宝用户ID。 2088开头的16位纯数字，小程序场景下获取用户ID请参考：用户授权; 其它场景下获取用户ID请参考：网页授权获取用户信息; 注：交易的买家与卖家不能相同。
-	Body                 string                     `json:"body,omitempty"`                  // 订单附加信息。	如果请求时传递了该参数，将在异步通知、对账单中原样返回，同时会在商户和用户的pc账单详情中作为交易描述展示
-	GoodsDetail          []*GoodsDetailParams       `json:"goods_detail,omitempty"`          // 订单包含的商品列表信息，为 JSON 格式，其它说明详见商品明细说明
-	TimeExpire           string                     `json:"time_expire,omitempty"`           // 订单绝对超时时间。 格式为yyyy-MM-dd HH:mm:ss。注：time_expire和timeout_express两者只需传入一个或者都不传，如果两者都传，优先使用time_expire。
-	TimeoutExpress       string                     `json:"timeout_express,omitempty"`       // 订单相对超时时间。从交易创建时间开始计算。 该笔订单允许的最晚付款时间，逾期将关闭交易。取值范围：1m～15d。m-分钟，h-小时，d-天，1c-当天（1c-当天的情况下，无论交易何时创建，都在0点关闭）。 该参数数值不接受小数点， 如 1.5h，可转换为 90m。 当面付场景默认值为3h。注：time_expire和timeout_express两者只需传入一个或者都不传，如果两者都传，优先使用time_expire。
-	SettleInfo           *SettleInfoParams          `json:"settle_info,omitempty"`           // 描述结算信息，json格式
-	ExtendParams         *ExtendParamsParams        `json:"extend_params,omitempty"`         // 业务扩展参数
-	BusinessParams       *BusinessParamsParams      `json:"business_params,omitempty"`       // 商户传入业务信息，具体值要和支付宝约定，应用于安全，营销等参数直传场景，格式为json格式
-	DiscountableAmount   float64                    `json:"discountable_amount,omitempty"`   // 可打折金额。参与优惠计算的金额，单位为元，精确到小数点后两位，取值范围为 [0.01,100000000]。如果该值未传入，但传入了【订单总金额】和【不可打折金额】，则该值默认为【订单总金额】-【不可打折金额】
-	UndiscountableAmount float64                    `json:"undiscountable_amount,omitempty"` // 不可打折金额。不参与优惠计算的金额，单位为元，精确到小数点后两位，取值范围[0.01,100000000]。 如果同时传入了【可打折金额】、【不可打折金额】和【订单总金额】，则必须满足如下条件：【订单总金额】=【可打折金额】+【不可打折金额】。如果订单金额全部参与优惠计算，则【可打折金额】和【不可打折金额】都无需传入。
-	StoreId              string                     `json:"store_id,omitempty"`              // 商户门店编号。 指商户创建门店时输入的门店编号。
-	OperatorId           string                     `json:"operator_id,omitempty"`           // 商户操作员编号。
-	TerminalId           string                     `json:"terminal_id,omitempty"`           // 商户机具终端编号。
-	ReceiverAddressInfo  *ReceiverAddressInfoParams `json:"receiver_address_info,omitempty"` // 收货人及地址信息
+	OutTradeNo           string                     `json:"out_trade_no" alipay:"required,max=64,pattern=out_trade_no"` // 商户订单号。由商家自定义，64个字符以内，仅支持字母、数字、下划线且需保证在商户端不重复。
+	TotalAmount          Amount                     `json:"total_amount" alipay:"required,amount"`                      // 订单总金额，单位为元，精确到小数点后两位，取值范围为 [0.01,100000000]，金额不能为 0。如果同时传入了【可打折金额】，【不可打折金额】，【订单总金额】三者，则必须满足如下条件：【订单总金额】=【可打折金额】+【不可打折金额】
+	Subject              string                     `json:"subject" alipay:"required,max=256"`                          // 订单标题。 注意：不可使用特殊字符，如 /，=，& 等。
+	ProductCode          ProductCode                `json:"product_code"`                                               // 销售产品码。如果签约的是当面付快捷版，则传 OFFLINE_PAYMENT；其它支付宝当面付产品传 FACE_TO_FACE_PAYMENT；不传则默认使用 FACE_TO_FACE_PAYMENT。
+	SellerId             string                     `json:"seller_id,omitempty"`                                        // 卖家支付宝用户 ID。 当需要指定收款账号时，通过该参数传入，如果该值为空，则默认为商户签约账号对应的支付宝用户ID。 收款账号优先级规则：门店绑定的收款账户>请求传入的seller_id>商户签约账号对应的支付宝用户ID； 注：直付通和机构间联场景下seller_id无需传入或者保持跟pid一致；如果传入的seller_id与pid不一致，需要联系支付宝小二配置收款关系；
+	BuyerId              string                     `json:"buyer_id,omitempty"`                                         // 买家支付宝用户ID。 2088开头的16位纯数字，小程序场景下获取用户ID请参考：用户授权; 其它场景下获取用户ID请参考：网页授权获取用户信息; 注：交易的买家与卖家不能相同。
+	Body                 string                     `json:"body,omitempty"`                                             // 订单附加信息。	如果请求时传递了该参数，将在异步通知、对账单中原样返回，同时会在商户和用户的pc账单详情中作为交易描述展示
+	GoodsDetail          []*GoodsDetailParams       `json:"goods_detail,omitempty"`                                     // 订单包含的商品列表信息，为 JSON 格式，其它说明详见商品明细说明
+	TimeExpire           string                     `json:"time_expire,omitempty"`                                      // 订单绝对超时时间。 格式为yyyy-MM-dd HH:mm:ss。注：time_expire和timeout_express两者只需传入一个或者都不传，如果两者都传，优先使用time_expire。
+	TimeoutExpress       string                     `json:"timeout_express,omitempty"`                                  // 订单相对超时时间。从交易创建时间开始计算。 该笔订单允许的最晚付款时间，逾期将关闭交易。取值范围：1m～15d。m-分钟，h-小时，d-天，1c-当天（1c-当天的情况下，无论交易何时创建，都在0点关闭）。 该参数数值不接受小数点， 如 1.5h，可转换为 90m。 当面付场景默认值为3h。注：time_expire和timeout_express两者只需传入一个或者都不传，如果两者都传，优先使用time_expire。
+	SettleInfo           *SettleInfoParams          `json:"settle_info,omitempty"`                                      // 描述结算信息，json格式
+	ExtendParams         *ExtendParamsParams        `json:"extend_params,omitempty"`                                    // 业务扩展参数
+	BusinessParams       *BusinessParamsParams      `json:"business_params,omitempty"`                                  // 商户传入业务信息，具体值要和支付宝约定，应用于安全，营销等参数直传场景，格式为json格式
+	DiscountableAmount   Amount                     `json:"discountable_amount,omitempty" alipay:"amount"`              // 可打折金额。参与优惠计算的金额，单位为元，精确到小数点后两位，取值范围为 [0.01,100000000]。如果该值未传入，但传入了【订单总金额】和【不可打折金额】，则该值默认为【订单总金额】-【不可打折金额】
+	UndiscountableAmount Amount                     `json:"undiscountable_amount,omitempty" alipay:"amount"`            // 不可打折金额。不参与优惠计算的金额，单位为元，精确到小数点后两位，取值范围[0.01,100000000]。 如果同时传入了【可打折金额】、【不可打折金额】和【订单总金额】，则必须满足如下条件：【订单总金额】=【可打折金额】+【不可打折金额】。如果订单金额全部参与优惠计算，则【可打折金额】和【不可打折金额】都无需传入。
+	StoreId              string                     `json:"store_id,omitempty"`                                         // 商户门店编号。 指商户创建门店时输入的门店编号。
+	OperatorId           string                     `json:"operator_id,omitempty"`                                      // 商户操作员编号。
+	TerminalId           string                     `json:"terminal_id,omitempty"`                                      // 商户机具终端编号。
+	ReceiverAddressInfo  *ReceiverAddressInfoParams `json:"receiver_address_info,omitempty"`                            // 收货人及地址信息
 }
 
 func (t *TradeCreateRequestParams) GetOtherParams() url.Values {
@@ -185,7 +185,7 @@ type SettleDetailInfoParams struct {
 	SummaryDimension string `json:"summary_dimension,omitempty"`  // 结算汇总维度，按照这个维度汇总成批次结算，由商户指定。 目前需要和结算收款方账户类型为cardAliasNo配合使用
 	SettleEntityId   string `json:"settle_entity_id,omitempty"`   // 结算主体标识。当结算主体类型为SecondMerchant时，为二级商户的SecondMerchantID；当结算主体类型为Store时，为门店的外标。
 	SettleEntityType string `json:"settle_entity_type,omitempty"` // 结算主体类型。 二级商户:SecondMerchant;商户或者直连商户门店:Store
-	Amount           string `json:"amount"`                       // 结算的金额，单位为元。在创建订单和支付接口时必须和交易金额相同。在结算确认接口时必须等于交易金额减去已退款金额。
+	Amount           Amount `json:"amount"`                       // 结算的金额，单位为元。在创建订单和支付接口时必须和交易金额相同。在结算确认接口时必须等于交易金额减去已退款金额。
 }
 
 // BusinessParamsParams 商户传入业务信息，具体值要和支付宝约定，应用于安全，营销等参数直传场景
@@ -217,8 +217,8 @@ type ReceiverAddressInfoParams struct {
 type TradeCancelRequestParams struct {
 	OtherRequestParams
 
-	OutTradeNo string `json:"out_trade_no,omitempty"` // 原支付请求的商户订单号,和支付宝交易号不能同时为空
-	TradeNo    string `json:"trade_no,omitempty"`     // 支付宝交易号，和商户订单号不能同时为空
+	OutTradeNo string `json:"out_trade_no,omitempty" alipay:"oneof=trade,max=64"` // 原支付请求的商户订单号,和支付宝交易号不能同时为空
+	TradeNo    string `json:"trade_no,omitempty" alipay:"oneof=trade,max=64"`     // 支付宝交易号，和商户订单号不能同时为空
 }
 
 func (t *TradeCancelRequestParams) GetOtherParams() url.Values {
@@ -253,9 +253,9 @@ type TradeCancelResponseParams struct {
 type TradeCloseRequestParams struct {
 	OtherRequestParams
 
-	TradeNo    string `json:"trade_no,omitempty"`     // 该交易在支付宝系统中的交易流水号。最短 16 位，最长 64 位。和out_trade_no不能同时为空，如果同时传了 out_trade_no和 trade_no，则以 trade_no为准。
-	OutTradeNo string `json:"out_trade_no,omitempty"` // 订单支付时传入的商户订单号,和支付宝交易号不能同时为空。 trade_no,out_trade_no如果同时存在优先取trade_n
-	OperatorId string `json:"operator_id,omitempty"`  // 商家操作员编号 id，由商家自定义。
+	TradeNo    string `json:"trade_no,omitempty" alipay:"oneof=trade,max=64"`     // 该交易在支付宝系统中的交易流水号。最短 16 位，最长 64 位。和out_trade_no不能同时为空，如果同时传了 out_trade_no和 trade_no，则以 trade_no为准。
+	OutTradeNo string `json:"out_trade_no,omitempty" alipay:"oneof=trade,max=64"` // 订单支付时传入的商户订单号,和支付宝交易号不能同时为空。 trade_no,out_trade_no如果同时存在优先取trade_n
+	OperatorId string `json:"operator_id,omitempty"`                              // 商家操作员编号 id，由商家自定义。
 }
 
 func (t *TradeCloseRequestParams) GetOtherParams() url.Values {
@@ -289,10 +289,10 @@ type TradeCloseResponseParams struct {
 type TradeQueryRequestParams struct {
 	OtherRequestParams
 
-	OutTradeNo   string    `json:"out_trade_no,omitempty"`   // 订单支付时传入的商户订单号,和支付宝交易号不能同时为空。 trade_no,out_trade_no如果同时存在优先取trade_no
-	TradeNo      string    `json:"trade_no,omitempty"`       // 支付宝交易号，和商户订单号不能同时为空
-	OrgPid       string    `json:"org_pid,omitempty"`        // 银行间联模式下有用，其它场景请不要使用； 双联通过该参数指定需要查询的交易所属收单机构的pid;
-	QueryOptIons []*string `json:"query_opt_ions,omitempty"` // 查询选项，商户传入该参数可定制本接口同步响应额外返回的信息字段，数组格式。支持枚举如下：trade_settle_info：返回的交易结算信息，包含分账、补差等信息。 fund_bill_list：交易支付使用的资金渠道。
+	OutTradeNo   string    `json:"out_trade_no,omitempty" alipay:"oneof=trade,max=64"` // 订单支付时传入的商户订单号,和支付宝交易号不能同时为空。 trade_no,out_trade_no如果同时存在优先取trade_no
+	TradeNo      string    `json:"trade_no,omitempty" alipay:"oneof=trade,max=64"`     // 支付宝交易号，和商户订单号不能同时为空
+	OrgPid       string    `json:"org_pid,omitempty"`                                  // 银行间联模式下有用，其它场景请不要使用； 双联通过该参数指定需要查询的交易所属收单机构的pid;
+	QueryOptIons []*string `json:"query_opt_ions,omitempty"`                           // 查询选项，商户传入该参数可定制本接口同步响应额外返回的信息字段，数组格式。支持枚举如下：trade_settle_info：返回的交易结算信息，包含分账、补差等信息。 fund_bill_list：交易支付使用的资金渠道。
 }
 
 func (t *TradeQueryRequestParams) GetOtherParams() url.Values {
@@ -315,20 +315,21 @@ type TradeQueryResponseParams struct {
 		TradeNo               string                  `json:"trade_no"`                 // 支付宝交易号
 		OutTradeNo            string                  `json:"out_trade_no"`             // 商家订单号
 		BuyerLogonId          string                  `json:"buyer_logon_id"`           // 买家支付宝账号
-		TradeStatus           string                  `json:"trade_status"`             // 交易状态：WAIT_BUYER_PAY（交易创建，等待买家付款）、TRADE_CLOSED（未付款交易超时关闭，或支付完成后全额退款）、TRADE_SUCCESS（交易支付成功）、TRADE_FINISHED（交易结束，不可退款）
-		TotalAmount           float64                 `json:"total_amount"`             // 交易的订单金额，单位为元，两位小数。该参数的值为支付时传入的total_amount
-		TransCurrency         string                  `json:"trans_currency"`           // 标价币种，该参数的值为支付时传入的
-		SettleCurrency        string                  `json:"settle_currency"`          // 订单结算币种，对应支付接口传入的
-		SettleAmount          float64                 `json:"settle_amount"`            // 结算币种订单金额
-		PayCurrency           float64                 `json:"pay_currency"`             // 订单支付币种
-		PayAmount             string                  `json:"pay_amount"`               // 订单币种订单金额
+		TradeStatus           TradeStatus             `json:"trade_status"`             // 交易状态：WAIT_BUYER_PAY（交易创建，等待买家付款）、TRADE_CLOSED（未付款交易超时关闭，或支付完成后全额退款）、TRADE_SUCCESS（交易支付成功）、TRADE_FINISHED（交易结束，不可退款）
+		TotalAmount           CurrencyAmount          `json:"total_amount"`             // 交易的订单金额，标价币种（trans_currency，为空时为人民币）的金额，精度为该币种的小数位数。该参数的值为支付时传入的total_amount
+		TransCurrency         Currency                `json:"trans_currency"`           // 标价币种，该参数的值为支付时传入的
+		SettleCurrency        Currency                `json:"settle_currency"`          // 订单结算币种，对应支付接口传入的
+		SettleAmount          CurrencyAmount          `json:"settle_amount"`            // 结算币种订单金额
+		PayCurrency           Currency                `json:"pay_currency"`             // 订单支付币种
+		PayAmount             CurrencyAmount          `json:"pay_amount"`               // 订单币种订单金额
 		SettleTransRate       string                  `json:"settle_trans_rate"`        // 结算币种兑换标价币种汇率
 		TransPayRate          string                  `json:"trans_pay_rate"`           // 标价币种兑换支付币种汇率
-		BuyerPayAmount        float64                 `json:"buyer_pay_amount"`         // 买家实付金额，单位为元，两位小数。该金额代表该笔交易买家实际支付的金额，不包含商户折扣等金额
-		PointAmount           float64                 `json:"point_amount"`             // 积分支付的金额，单位为元，两位小数。该金额代表该笔交易中用户使用积分支付的金额，比如集分宝或者支付宝实时优惠等
-		InvoiceAmount         float64                 `json:"invoice_amount"`           // 交易中用户支付的可开具发票的金额，单位为元，两位小数。该金额代表该笔交易中可以给用户开具发票的金额
+		ForexRate             string                  `json:"forex_rate"`               // 汇率，跨境交易时返回
+		BuyerPayAmount        Amount                  `json:"buyer_pay_amount"`         // 买家实付金额，单位为元，两位小数。该金额代表该笔交易买家实际支付的金额，不包含商户折扣等金额
+		PointAmount           Amount                  `json:"point_amount"`             // 积分支付的金额，单位为元，两位小数。该金额代表该笔交易中用户使用积分支付的金额，比如集分宝或者支付宝实时优惠等
+		InvoiceAmount         Amount                  `json:"invoice_amount"`           // 交易中用户支付的可开具发票的金额，单位为元，两位小数。该金额代表该笔交易中可以给用户开具发票的金额
 		SendPayDate           string                  `json:"send_pay_date"`            // 本次交易打款给卖家的时间
-		ReceiptAmount         string                  `json:"receipt_amount"`           // 实收金额，单位为元，两位小数。该金额为本笔交易，商户账户能够实际收到的金额
+		ReceiptAmount         Amount                  `json:"receipt_amount"`           // 实收金额，单位为元，两位小数。该金额为本笔交易，商户账户能够实际收到的金额
 		StoreId               string                  `json:"store_id"`                 // 商户门店编号
 		TerminalId            string                  `json:"terminal_id"`              // 商户机具终端编号
 		FundBillList          FundBillListParams      `json:"fund_bill_list"`           // 交易支付使用的资金渠道。 只有在签约中指定需要返回资金明细，或者入参的query_options中指定时才返回该字段信息。
@@ -336,14 +337,14 @@ type TradeQueryResponseParams struct {
 		BuyerUserId           string                  `json:"buyer_user_id"`            // 买家在支付宝的用户id
 		IndustrySepcDetailGov string                  `json:"industry_sepc_detail_gov"` // 行业特殊信息-统筹相关
 		IndustrySepcDetailAcc string                  `json:"industry_sepc_detail_acc"` // 行业特殊信息-个账相关
-		ChargeAmount          string                  `json:"charge_amount"`            // 该笔交易针对收款方的收费金额； 只在银行间联交易场景下返回该信息；
+		ChargeAmount          Amount                  `json:"charge_amount"`            // 该笔交易针对收款方的收费金额； 只在银行间联交易场景下返回该信息；
 		ChargeFlags           string                  `json:"charge_flags"`             // 费率活动标识
 		SettlementId          string                  `json:"settlement_id"`            // 支付清算编号，用于清算对账使用； 只在银行间联交易场景下返回该信息；
 		TradeSettleInfo       TradeSettleInfoParams   `json:"trade_settle_info"`        // 返回的交易结算信息，包含分账、补差等信息。 只有在query_options中指定时才返回该字段信息。
 		AuthTradePayMode      string                  `json:"auth_trade_pay_mode"`      // 预授权支付模式，该参数仅在信用预授权支付场景下返回。信用预授权支付：CREDIT_PREAUTH_PAY
 		BuyerUserType         string                  `json:"buyer_user_type"`          // 买家用户类型。CORPORATE:企业用户；PRIVATE:个人用户。
-		MdiscountAmount       string                  `json:"mdiscount_amount"`         // 商家优惠金额
-		DiscountAmount        string                  `json:"discount_amount"`          // 平台优惠金额
+		MdiscountAmount       Amount                  `json:"mdiscount_amount"`         // 商家优惠金额
+		DiscountAmount        Amount                  `json:"discount_amount"`          // 平台优惠金额
 		Subject               string                  `json:"subject"`                  // 订单标题；只在银行间联交易场景下返回该信息；
 		SubMerchantId         string                  `json:"alipay_sub_merchant_id"`   // 间连商户在支付宝端的商户编号； 只在银行间联交易场景下返回该信息；
 		ExtInfos              string                  `json:"ext_infos"`                // 交易额外信息，特殊场景下与支付宝约定返回。 json格式。
@@ -358,9 +359,9 @@ type TradeQueryResponseParams struct {
 
 // FundBillListParams 交易支付使用的资金渠道
 type FundBillListParams struct {
-	FundChannel string  `json:"fund_channel"` // 交易使用的资金渠道
-	Amount      float64 `json:"amount"`       // 该支付工具类型所使用的金额
-	RealAmount  float64 `json:"real_amount"`  // 渠道实际付款金额
+	FundChannel string `json:"fund_channel"` // 交易使用的资金渠道
+	Amount      Amount `json:"amount"`       // 该支付工具类型所使用的金额
+	RealAmount  Amount `json:"real_amount"`  // 渠道实际付款金额
 }
 
 // TradeSettleInfoParams 交易结算明细信息列表
@@ -370,14 +371,14 @@ type TradeSettleInfoParams struct {
 
 // TradeSettleDetailParams 交易结算明细信息
 type TradeSettleDetailParams struct {
-	OperationType     string  `json:"operation_type"`      // 结算操作类型。有以下几种类型：replenish(补差)、replenish_refund(退补差)、transfer(分账)、transfer_refund(退分账)、settle(结算)、settle_refund(退结算)、on_settle(待结算)。
-	OperationSerialNo string  `json:"operation_serial_no"` // 商户操作序列号。商户发起请求的外部请求号。
-	OperationDt       string  `json:"operation_dt"`        // 操作日期
-	TransOut          string  `json:"trans_out"`           // 转出账号
-	TransIn           string  `json:"trans_in"`            // 转入账号
-	Amount            float64 `json:"amount"`              // 实际操作金额，单位为元，两位小数。该参数的值为分账或补差或结算时传入
-	OriTransOut       string  `json:"ori_trans_out"`       // 商户请求的转出账号
-	OriTransIn        string  `json:"ori_trans_in"`        // 商户请求的转入账号
+	OperationType     string `json:"operation_type"`      // 结算操作类型。有以下几种类型：replenish(补差)、replenish_refund(退补差)、transfer(分账)、transfer_refund(退分账)、settle(结算)、settle_refund(退结算)、on_settle(待结算)。
+	OperationSerialNo string `json:"operation_serial_no"` // 商户操作序列号。商户发起请求的外部请求号。
+	OperationDt       string `json:"operation_dt"`        // 操作日期
+	TransOut          string `json:"trans_out"`           // 转出账号
+	TransIn           string `json:"trans_in"`            // 转入账号
+	Amount            Amount `json:"amount"`              // 实际操作金额，单位为元，两位小数。该参数的值为分账或补差或结算时传入
+	OriTransOut       string `json:"ori_trans_out"`       // 商户请求的转出账号
+	OriTransIn        string `json:"ori_trans_in"`        // 商户请求的转入账号
 }
 
 // HbFqPayInfoParams 用户使用花呗分期支付信息
@@ -387,7 +388,7 @@ type HbFqPayInfoParams struct {
 
 // EnterprisePayInfoParams 因公付支付信息
 type EnterprisePayInfoParams struct {
-	InvoiceAmount float64 `json:"invoice_amount"` // 开票金额
+	InvoiceAmount Amount `json:"invoice_amount"` // 开票金额
 }
 
 ///////////////////////////////////////////////////////////////////////////////////////
@@ -397,19 +398,21 @@ type EnterprisePayInfoParams struct {
 type TradePreCreateRequestParams struct {
 	OtherRequestParams
 
-	OutTradeNo         string               `json:"out_trade_no"`                  // 商户订单号。由商家自定义，64个字符以内，仅支持字母、数字、下划线且需保证在商户端不重复。
-	TotalAmount        float64              `json:"total_amount"`                  // 订单总金额，单位为元，精确到小数点后两位，取值范围为 [0.01,100000000]，金额不能为 0。如果同时传入了【可打折金额】，【不可打折金额】，【订单总金额】三者，则必须满足如下条件：【订单总金额】=【可打折金额】+【不可打折金额】
-	Subject            string               `json:"subject"`                       // 订单标题。 注意：不可使用特殊字符，如 /，=，& 等。
-	ProductCode        string               `json:"product_code"`                  // 销售产品码。如果签约的是当面付快捷版，则传 OFFLINE_PAYMENT；其它支付宝当面付产品传 FACE_TO_FACE_PAYMENT；不传则默认使用 FACE_TO_FACE_PAYMENT。
-	SellerId           string               `json:"seller_id,omitempty"`           // 卖家支付宝用户 ID。 如果该值为空，则默认为商户签约账号对应的支付宝用户 ID。不允许收款账号与付款方账号相同
-	Body               string               `json:"body,omitempty"`                // 订单附加信息。	如果请求时传递了该参数，将在异步通知、对账单中原样返回，同时会在商户和用户的pc账单详情中作为交易描述展示
-	GoodsDetail        []*GoodsDetailParams `json:"goods_detail,omitempty"`        // 订单包含的商品列表信息，为 JSON 格式，其它说明详见商品明细说明
-	ExtendParams       *ExtendParamsParams  `json:"extend_params,omitempty"`       // 业务扩展参数
-	DiscountableAmount float64              `json:"discountable_amount,omitempty"` // 可打折金额。参与优惠计算的金额，单位为元，精确到小数点后两位，取值范围为 [0.01,100000000]。如果该值未传入，但传入了【订单总金额】和【不可打折金额】，则该值默认为【订单总金额】-【不可打折金额】
-	StoreId            string               `json:"store_id,omitempty"`            // 商户门店编号。 指商户创建门店时输入的门店编号。
-	OperatorId         string               `json:"operator_id,omitempty"`         // 商户操作员编号。
-	TerminalId         string               `json:"terminal_id,omitempty"`         // 商户机具终端编号。
-	MerchantOrderNo    string               `json:"merchant_order_no,omitempty"`   // 商户原始订单号，最大长度限制 32 位
+	OutTradeNo         string               `json:"out_trade_no" alipay:"required,max=64,pattern=out_trade_no"` // 商户订单号。由商家自定义，64个字符以内，仅支持字母、数字、下划线且需保证在商户端不重复。
+	TotalAmount        Amount               `json:"total_amount" alipay:"required,amount"`                      // 订单总金额，单位为元，精确到小数点后两位，取值范围为 [0.01,100000000]，金额不能为 0。如果同时传入了【可打折金额】，【不可打折金额】，【订单总金额】三者，则必须满足如下条件：【订单总金额】=【可打折金额】+【不可打折金额】
+	Subject            string               `json:"subject" alipay:"required,max=256"`                          // 订单标题。 注意：不可使用特殊字符，如 /，=，& 等。
+	ProductCode        ProductCode          `json:"product_code"`                                               // 销售产品码。如果签约的是当面付快捷版，则传 OFFLINE_PAYMENT；其它支付宝当面付产品传 FACE_TO_FACE_PAYMENT；不传则默认使用 FACE_TO_FACE_PAYMENT。
+	SellerId           string               `json:"seller_id,omitempty"`                                        // 卖家支付宝用户 ID。 如果该值为空，则默认为商户签约账号对应的支付宝用户 ID。不允许收款账号与付款方账号相同
+	Body               string               `json:"body,omitempty"`                                             // 订单附加信息。	如果请求时传递了该参数，将在异步通知、对账单中原样返回，同时会在商户和用户的pc账单详情中作为交易描述展示
+	GoodsDetail        []*GoodsDetailParams `json:"goods_detail,omitempty"`                                     // 订单包含的商品列表信息，为 JSON 格式，其它说明详见商品明细说明
+	ExtendParams       *ExtendParamsParams  `json:"extend_params,omitempty"`                                    // 业务扩展参数
+	DiscountableAmount Amount               `json:"discountable_amount,omitempty" alipay:"amount"`              // 可打折金额。参与优惠计算的金额，单位为元，精确到小数点后两位，取值范围为 [0.01,100000000]。如果该值未传入，但传入了【订单总金额】和【不可打折金额】，则该值默认为【订单总金额】-【不可打折金额】
+	StoreId            string               `json:"store_id,omitempty"`                                         // 商户门店编号。 指商户创建门店时输入的门店编号。
+	OperatorId         string               `json:"operator_id,omitempty"`                                      // 商户操作员编号。
+	TerminalId         string               `json:"terminal_id,omitempty"`                                      // 商户机具终端编号。
+	MerchantOrderNo    string               `json:"merchant_order_no,omitempty"`                                // 商户原始订单号，最大长度限制 32 位
+	TransCurrency      Currency             `json:"trans_currency,omitempty"`                                   // 标价币种，跨境支付时使用。total_amount 对应的币种单位，支持英镑：GBP、港币：HKD、美元：USD、新加坡元：SGD、日元：JPY、加拿大元：CAD、澳元：AUD、欧元：EUR、新西兰元：NZD、韩元：KRW、泰铢：THB、瑞士法郎：CHF、瑞典克朗：SEK、丹麦克朗：DKK、挪威克朗：NOK、马来西亚林吉特：MYR、印尼卢比：IDR、菲律宾比索：PHP、毛里求斯卢比：MUR、以色列新谢克尔：ILS、斯里兰卡卢比：LKR、俄罗斯卢布：RUB、阿联酋迪拉姆：AED、捷克克朗：CZK、南非兰特：ZAR、人民币：CNY。指定时 total_amount 以该币种的最小单位保存（可使用 ParseAmountIn 解析），按该币种的小数位数序列化
+	SettleCurrency     Currency             `json:"settle_currency,omitempty"`                                  // 商户指定的结算币种，跨境支付时使用。支持的币种同 trans_currency
 }
 
 func (t *TradePreCreateRequestParams) GetOtherParams() url.Values {
@@ -426,6 +429,16 @@ func (t *TradePreCreateRequestParams) GetNeedEncrypt() bool {
 	return t.NeedEncrypt == true
 }
 
+// MarshalJSON 指定标价币种时按币种的小数位数序列化金额，如JPY为"1000"，KWD为"1.234"
+func (t TradePreCreateRequestParams) MarshalJSON() ([]byte, error) {
+	type params TradePreCreateRequestParams
+	return json.Marshal(struct {
+		params
+		TotalAmount        CurrencyAmount `json:"total_amount"`
+		DiscountableAmount CurrencyAmount `json:"discountable_amount,omitempty"`
+	}{params(t), t.TotalAmount.CurrencyAmount(t.TransCurrency), optionalCurrencyAmount(t.DiscountableAmount, t.TransCurrency)})
+}
+
 // TradePreCreateResponseParams 统一收单线下交易预创建响应参数
 type TradePreCreateResponseParams struct {
 	Data struct {
@@ -438,13 +451,13 @@ type TradePreCreateResponseParams struct {
 
 // GoodsDetailParams 订单包含的商品列表信息
 type GoodsDetailParams struct {
-	GoodsId        string  `json:"goods_id"`                  // 商品的编号
-	GoodsName      string  `json:"goods_name"`                // 商品名称
-	Quantity       int     `json:"quantity"`                  // 商品数量
-	Price          float64 `json:"price"`                     // 商品单价，单位为元
-	GoodsCategory  string  `json:"goods_category,omitempty"`  // 商品类目
-	CategoriesTree string  `json:"categories_tree,omitempty"` // 商品类目树，从商品类目根节点到叶子节点的类目id组成，类目id值使用|分割
-	ShowUrl        string  `json:"show_url,omitempty"`        // 商品的展示地址
+	GoodsId        string `json:"goods_id" alipay:"required,max=64"`    // 商品的编号
+	GoodsName      string `json:"goods_name" alipay:"required,max=256"` // 商品名称
+	Quantity       int    `json:"quantity" alipay:"required"`           // 商品数量
+	Price          Amount `json:"price"`                                // 商品单价，单位为元
+	GoodsCategory  string `json:"goods_category,omitempty"`             // 商品类目
+	CategoriesTree string `json:"categories_tree,omitempty"`            // 商品类目树，从商品类目根节点到叶子节点的类目id组成，类目id值使用|分割
+	ShowUrl        string `json:"show_url,omitempty"`                   // 商品的展示地址
 }
 
 // ExtendParamsParams 业务扩展参数
@@ -456,18 +469,114 @@ type ExtendParamsParams struct {
 
 ///////////////////////////////////////////////////////////////////////////////////////
 
+// TradePayRequestParams 统一收单交易支付接口（当面付条码支付）请求参数
+// 文档地址：https://opendocs.alipay.com/apis/api_1/alipay.trade.pay
+type TradePayRequestParams struct {
+	OtherRequestParams
+
+	OutTradeNo         string               `json:"out_trade_no" alipay:"required,max=64,pattern=out_trade_no"` // 商户订单号。由商家自定义，64个字符以内，仅支持字母、数字、下划线且需保证在商户端不重复。
+	TotalAmount        Amount               `json:"total_amount" alipay:"required,amount"`                      // 订单总金额。单位为元，精确到小数点后两位，取值范围：[0.01,100000000]
+	Subject            string               `json:"subject" alipay:"required,max=256"`                          // 订单标题。注意：不可使用特殊字符，如 /，=，& 等。
+	AuthCode           string               `json:"auth_code" alipay:"required,max=64"`                         // 支付授权码。当面付场景传买家的付款码（25~30开头的长度为16~24位的数字，实际字符串长度以开发者获取的付款码长度为准）或者刷脸标识串（fp开头的35位字符串）。
+	Scene              string               `json:"scene" alipay:"required"`                                    // 支付场景。枚举值：bar_code：当面付条码支付场景；security_code：当面付刷脸支付场景，对应的auth_code为fp开头的刷脸标识串；默认值为bar_code。
+	ProductCode        ProductCode          `json:"product_code,omitempty"`                                     // 产品码。商家和支付宝签约的产品码。当面付场景下，如果签约的是当面付快捷版，则传 OFFLINE_PAYMENT；其它支付宝当面付产品传 FACE_TO_FACE_PAYMENT；不传则默认使用FACE_TO_FACE_PAYMENT。
+	SellerId           string               `json:"seller_id,omitempty"`                                        // 卖家支付宝用户ID。当需要指定收款账号时，通过该参数传入，如果该值为空，则默认为商户签约账号对应的支付宝用户ID。
+	BuyerId            string               `json:"buyer_id,omitempty"`                                         // 买家支付宝用户ID。
+	Body               string               `json:"body,omitempty"`                                             // 订单附加信息。如果请求时传递了该参数，将在异步通知、对账单中原样返回，同时会在商户和用户的pc账单详情中作为交易描述展示
+	GoodsDetail        []*GoodsDetailParams `json:"goods_detail,omitempty"`                                     // 订单包含的商品列表信息，为 JSON 格式，其它说明详见商品明细说明
+	DiscountableAmount Amount               `json:"discountable_amount,omitempty" alipay:"amount"`              // 可打折金额。参与优惠计算的金额，单位为元，精确到小数点后两位，取值范围为 [0.01,100000000]。
+	TransCurrency      Currency             `json:"trans_currency,omitempty"`                                   // 标价币种，跨境支付时使用。total_amount 对应的币种单位。指定时 total_amount 以该币种的最小单位保存（可使用 ParseAmountIn 解析），按该币种的小数位数序列化
+	SettleCurrency     Currency             `json:"settle_currency,omitempty"`                                  // 商户指定的结算币种，跨境支付时使用。
+	ExtendParams       *ExtendParamsParams  `json:"extend_params,omitempty"`                                    // 业务扩展参数
+	StoreId            string               `json:"store_id,omitempty"`                                         // 商户门店编号。指商户创建门店时输入的门店编号。
+	OperatorId         string               `json:"operator_id,omitempty"`                                      // 商户操作员编号。
+	TerminalId         string               `json:"terminal_id,omitempty"`                                      // 商户机具终端编号。
+	TimeoutExpress     string               `json:"timeout_express,omitempty"`                                  // 订单相对超时时间。从交易创建时间开始计算。取值范围：1m～15d。当面付场景默认值为3h。
+	QueryOptions       []string             `json:"query_options,omitempty"`                                    // 返回参数选项。商户通过传递该参数来定制同步需要额外返回的信息字段，数组格式。包括但不限于：["fund_bill_list","voucher_detail_list","enterprise_pay_info","discount_goods_detail","discount_amount","mdiscount_amount"]
+}
+
+func (t *TradePayRequestParams) GetOtherParams() url.Values {
+	urlValue := url.Values{}
+	urlValue.Add(NotifyUrlFiled, t.NotifyUrl)
+	urlValue.Add(AppAuthTokenFiled, t.AppAuthToken)
+	urlValue.Add(ApiMethodNameFiled, "alipay.trade.pay")
+	bytes, _ := json.Marshal(t)
+	urlValue.Add(BizContentFiled, string(bytes))
+	return urlValue
+}
+
+func (t *TradePayRequestParams) GetNeedEncrypt() bool {
+	return t.NeedEncrypt == true
+}
+
+// MarshalJSON 指定标价币种时按币种的小数位数序列化金额，如JPY为"1000"，KWD为"1.234"
+func (t TradePayRequestParams) MarshalJSON() ([]byte, error) {
+	type params TradePayRequestParams
+	return json.Marshal(struct {
+		params
+		TotalAmount        CurrencyAmount `json:"total_amount"`
+		DiscountableAmount CurrencyAmount `json:"discountable_amount,omitempty"`
+	}{params(t), t.TotalAmount.CurrencyAmount(t.TransCurrency), optionalCurrencyAmount(t.DiscountableAmount, t.TransCurrency)})
+}
+
+// TradePayResponseParams 统一收单交易支付接口响应参数
+type TradePayResponseParams struct {
+	Data struct {
+		CommonResParams
+		TradeNo             string                `json:"trade_no"`              // 支付宝交易号
+		OutTradeNo          string                `json:"out_trade_no"`          // 商户订单号
+		BuyerLogonId        string                `json:"buyer_logon_id"`        // 买家支付宝账号
+		TotalAmount         CurrencyAmount        `json:"total_amount"`          // 交易金额，标价币种的金额，精度为该币种的小数位数
+		ReceiptAmount       Amount                `json:"receipt_amount"`        // 实收金额
+		BuyerPayAmount      Amount                `json:"buyer_pay_amount"`      // 买家付款的金额
+		PointAmount         Amount                `json:"point_amount"`          // 使用集分宝付款的金额
+		InvoiceAmount       Amount                `json:"invoice_amount"`        // 交易中可给用户开具发票的金额
+		GmtPayment          string                `json:"gmt_payment"`           // 交易支付时间
+		FundBillList        []TradeFundBillParams `json:"fund_bill_list"`        // 交易支付使用的资金渠道
+		StoreName           string                `json:"store_name"`            // 发生支付交易的商户门店名称
+		BuyerUserId         string                `json:"buyer_user_id"`         // 买家在支付宝的用户id
+		DiscountGoodsDetail string                `json:"discount_goods_detail"` // 本次交易支付所使用的单品券优惠的商品优惠信息
+		VoucherDetailList   []VoucherDetailParams `json:"voucher_detail_list"`   // 本交易支付时使用的所有优惠券信息
+		MdiscountAmount     Amount                `json:"mdiscount_amount"`      // 商家优惠金额
+		DiscountAmount      Amount                `json:"discount_amount"`       // 平台优惠金额
+		SettleAmount        CurrencyAmount        `json:"settle_amount"`         // 结算币种订单金额
+		PayCurrency         Currency              `json:"pay_currency"`          // 订单支付币种
+		PayAmount           CurrencyAmount        `json:"pay_amount"`            // 支付币种订单金额
+		SettleTransRate     string                `json:"settle_trans_rate"`     // 结算币种兑换标价币种汇率
+		TransPayRate        string                `json:"trans_pay_rate"`        // 标价币种兑换支付币种汇率
+	} `json:"alipay_trade_pay_response"`
+	Sign string `json:"sign"` // 签名
+}
+
+// VoucherDetailParams 优惠券信息
+type VoucherDetailParams struct {
+	Id                         string `json:"id"`                           // 券id
+	Name                       string `json:"name"`                         // 券名称
+	Type                       string `json:"type"`                         // 券类型，如：ALIPAY_FIX_VOUCHER - 全场代金券；ALIPAY_DISCOUNT_VOUCHER - 折扣券；ALIPAY_ITEM_VOUCHER - 单品优惠券
+	Amount                     Amount `json:"amount"`                       // 优惠券面额，它应该会等于商家出资加上其他出资方出资
+	MerchantContribute         Amount `json:"merchant_contribute"`          // 商家出资（特指发起交易的商家出资金额）
+	OtherContribute            Amount `json:"other_contribute"`             // 其他出资方出资金额，可能是支付宝，可能是品牌商，或者其他方，也可能是他们的一起出资
+	Memo                       string `json:"memo"`                         // 优惠券备注信息
+	TemplateId                 string `json:"template_id"`                  // 券模板id
+	PurchaseBuyerContribute    Amount `json:"purchase_buyer_contribute"`    // 如果使用的这张券是用户购买的，则该字段代表用户在购买这张券时用户实际付款的金额
+	PurchaseMerchantContribute Amount `json:"purchase_merchant_contribute"` // 如果使用的这张券是用户购买的，则该字段代表用户在购买这张券时商户优惠的金额
+	PurchaseAntContribute      Amount `json:"purchase_ant_contribute"`      // 如果使用的这张券是用户购买的，则该字段代表用户在购买这张券时平台优惠的金额
+}
+
+///////////////////////////////////////////////////////////////////////////////////////
+
 // TradeRefundRequestParams 统一收单交易退款接口请求参数
 // 文档地址：https://opendocs.alipay.com/apis/0287wa
 type TradeRefundRequestParams struct {
 	OtherRequestParams
 
-	OutTradeNo              string                                `json:"out_trade_no,omitempty"`              //  商户订单号。订单支付时传入的商户订单号，商家自定义且保证商家系统中唯一。与支付宝交易号 trade_no 不能同时为空。
-	TradeNo                 string                                `json:"trade_no,omitempty"`                  // 支付宝交易号。和商户订单号 out_trade_no 不能同时为空。
-	RefundAmount            float64                               `json:"refund_amount"`                       // 退款金额。 需要退款的金额，该金额不能大于订单金额，单位为元，支持两位小数。
-	RefundReason            string                                `json:"refund_reason,omitempty"`             // 退款原因说明。商家自定义，将在对账单的退款明细中作为备注返回，同时会在商户和用户的pc退款账单详情中展示
-	OutRequestNo            string                                `json:"out_request_no,omitempty"`            // 退款请求号。标识一次退款请求，需要保证在交易号下唯一，如需部分退款，则此参数必传。
-	RefundRoyaltyParameters []*OpenApiRoyaltyDetailInfoPojoParams `json:"refund_royalty_parameters,omitempty"` // 退分账明细信息。
-	QueryOptions            []*string                             `json:"query_options,omitempty"`             // 查询选项
+	OutTradeNo              string                                `json:"out_trade_no,omitempty" alipay:"oneof=trade,max=64"` //  商户订单号。订单支付时传入的商户订单号，商家自定义且保证商家系统中唯一。与支付宝交易号 trade_no 不能同时为空。
+	TradeNo                 string                                `json:"trade_no,omitempty" alipay:"oneof=trade,max=64"`     // 支付宝交易号。和商户订单号 out_trade_no 不能同时为空。
+	RefundAmount            Amount                                `json:"refund_amount" alipay:"required,amount"`             // 退款金额。 需要退款的金额，该金额不能大于订单金额，单位为元，支持两位小数。
+	RefundReason            string                                `json:"refund_reason,omitempty"`                            // 退款原因说明。商家自定义，将在对账单的退款明细中作为备注返回，同时会在商户和用户的pc退款账单详情中展示
+	OutRequestNo            string                                `json:"out_request_no,omitempty" alipay:"max=64"`           // 退款请求号。标识一次退款请求，需要保证在交易号下唯一，如需部分退款，则此参数必传。
+	RefundRoyaltyParameters []*OpenApiRoyaltyDetailInfoPojoParams `json:"refund_royalty_parameters,omitempty"`                // 退分账明细信息。
+	QueryOptions            []*string                             `json:"query_options,omitempty"`                            // 查询选项
 }
 
 func (t *TradeRefundRequestParams) GetOtherParams() url.Values {
@@ -491,34 +600,63 @@ type TradeRefundResponseParams struct {
 		OutTradeNo           string                `json:"out_trade_no"`            // 商家订单号
 		BuyerLogonId         string                `json:"buyer_logon_id"`          // 用户的登录id
 		FundChange           string                `json:"fund_change"`             // 本次退款是否发生了资金变化
-		RefundFee            float64               `json:"refund_fee"`              // 退款总金额。指该笔交易累计已经退款成功的金额。
+		RefundFee            Amount                `json:"refund_fee"`              // 退款总金额。指该笔交易累计已经退款成功的金额。
 		RefundDetailItemList []TradeFundBillParams `json:"refund_detail_item_list"` // 退款使用的资金渠道。只有在签约中指定需要返回资金明细，或者入参的query_options中指定时才返回该字段信息。
 		StoreName            string                `json:"store_name"`              // 交易在支付时候的门店名称
 		BuyerUserId          string                `json:"buyer_user_id"`           // 买家在支付宝的用户id
-		SendBackFee          string                `json:"send_back_fee"`           // 本次商户实际退回金额。说明：如需获取该值，需在入参query_options中传入 refund_detail_item_list。
+		SendBackFee          Amount                `json:"send_back_fee"`           // 本次商户实际退回金额。说明：如需获取该值，需在入参query_options中传入 refund_detail_item_list。
 	} `json:"alipay_trade_refund_response"`
 	Sign string `json:"sign"` // 签名
 }
 
 // OpenApiRoyaltyDetailInfoPojoParams 退分账明细信息。
 type OpenApiRoyaltyDetailInfoPojoParams struct {
-	RoyaltyType  string  `json:"royalty_type,omitempty"`   // 分账类型. 普通分账为：transfer; 补差为：replenish; 为空默认为分账transfer;
-	TransOut     string  `json:"trans_out,omitempty"`      // 支出方账户。如果支出方账户类型为userId，本参数为支出方的支付宝账号对应的支付宝唯一用户号，以2088开头的纯16位数字；如果支出方类型为loginName，本参数为支出方的支付宝登录号。 泛金融类商户分账时，该字段不要上送。
-	TransOutType string  `json:"trans_out_type,omitempty"` // 支出方账户类型。userId表示是支付宝账号对应的支付宝唯一用户号;loginName表示是支付宝登录号； 泛金融类商户分账时，该字段不要上送。
-	TransInType  string  `json:"trans_in_type,omitempty"`  // 收入方账户类型。userId表示是支付宝账号对应的支付宝唯一用户号;cardAliasNo表示是卡编号;loginName表示是支付宝登录号；
-	TransIn      string  `json:"trans_in"`                 // 收入方账户。如果收入方账户类型为userId，本参数为收入方的支付宝账号对应的支付宝唯一用户号，以2088开头的纯16位数字；如果收入方类型为cardAliasNo，本参数为收入方在支付宝绑定的卡编号；如果收入方类型为loginName，本参数为收入方的支付宝登录号；
-	Amount       float64 `json:"amount,omitempty"`         // 分账的金额，单位为元
-	Desc         string  `json:"desc,omitempty"`           // 分账描述
-	RoyaltyScene string  `json:"royalty_scene,omitempty"`  // 可选值：达人佣金、平台服务费、技术服务费、其他
-	TransInName  string  `json:"trans_in_name,omitempty"`  // 分账收款方姓名，上送则进行姓名与支付宝账号的一致性校验，校验不一致则分账失败。不上送则不进行姓名校验
+	RoyaltyType  string `json:"royalty_type,omitempty"`   // 分账类型. 普通分账为：transfer; 补差为：replenish; 为空默认为分账transfer;
+	TransOut     string `json:"trans_out,omitempty"`      // 支出方账户。如果支出方账户类型为userId，本参数为支出方的支付宝账号对应的支付宝唯一用户号，以2088开头的纯16位数字；如果支出方类型为loginName，本参数为支出方的支付宝登录号。 泛金融类商户分账时，该字段不要上送。
+	TransOutType string `json:"trans_out_type,omitempty"` // 支出方账户类型。userId表示是支付宝账号对应的支付宝唯一用户号;loginName表示是支付宝登录号； 泛金融类商户分账时，该字段不要上送。
+	TransInType  string `json:"trans_in_type,omitempty"`  // 收入方账户类型。userId表示是支付宝账号对应的支付宝唯一用户号;cardAliasNo表示是卡编号;loginName表示是支付宝登录号；
+	TransIn      string `json:"trans_in"`                 // 收入方账户。如果收入方账户类型为userId，本参数为收入方的支付宝账号对应的支付宝唯一用户号，以2088开头的纯16位数字；如果收入方类型为cardAliasNo，本参数为收入方在支付宝绑定的卡编号；如果收入方类型为loginName，本参数为收入方的支付宝登录号；
+	Amount       Amount `json:"amount,omitempty"`         // 分账的金额，单位为元
+	Desc         string `json:"desc,omitempty"`           // 分账描述
+	RoyaltyScene string `json:"royalty_scene,omitempty"`  // 可选值：达人佣金、平台服务费、技术服务费、其他
+	TransInName  string `json:"trans_in_name,omitempty"`  // 分账收款方姓名，上送则进行姓名与支付宝账号的一致性校验，校验不一致则分账失败。不上送则不进行姓名校验
 }
 
 // TradeFundBillParams 退款使用的资金渠道。
 type TradeFundBillParams struct {
-	FundChannel string  `json:"fund_channel"` // 交易使用的资金渠道
-	Amount      float64 `json:"amount"`       // 该支付工具类型所使用的金额
-	RealAmount  float64 `json:"real_amount"`  // 渠道实际付款金额
-	FundType    string  `json:"fund_type"`    // 渠道所使用的资金类型,目前只在资金渠道(fund_channel)是银行卡渠道(BANKCARD)的情况下才返回该信息(DEBIT_CARD:借记卡,CREDIT_CARD:信用卡,MIXED_CARD:借贷合一卡)
+	FundChannel string `json:"fund_channel"` // 交易使用的资金渠道
+	Amount      Amount `json:"amount"`       // 该支付工具类型所使用的金额
+	RealAmount  Amount `json:"real_amount"`  // 渠道实际付款金额
+	FundType    string `json:"fund_type"`    // 渠道所使用的资金类型,目前只在资金渠道(fund_channel)是银行卡渠道(BANKCARD)的情况下才返回该信息(DEBIT_CARD:借记卡,CREDIT_CARD:信用卡,MIXED_CARD:借贷合一卡)
+}
+
+///////////////////////////////////////////////////////////////////////////////////////
+
+// TradePageRefundRequestParams 统一收单退款页面接口请求参数
+// 文档地址：https://opendocs.alipay.com/apis/api_1/alipay.trade.page.refund
+type TradePageRefundRequestParams struct {
+	OtherRequestParams
+
+	OutTradeNo   string `json:"out_trade_no,omitempty" alipay:"oneof=trade,max=64"` // 商户订单号。订单支付时传入的商户订单号，和支付宝交易号不能同时为空。
+	TradeNo      string `json:"trade_no,omitempty" alipay:"oneof=trade,max=64"`     // 支付宝交易号。和商户订单号不能同时为空。
+	OutRequestNo string `json:"out_request_no" alipay:"required,max=64"`            // 退款请求号。标识一次退款请求，同一笔交易多次退款需要保证唯一，如需部分退款，则此参数必传。
+	RefundAmount Amount `json:"refund_amount" alipay:"required,amount"`             // 退款金额。需要退款的金额，该金额不能大于订单金额，单位为元，支持两位小数。
+	RefundReason string `json:"refund_reason,omitempty" alipay:"max=256"`           // 退款原因说明。商家自定义。
+}
+
+func (t *TradePageRefundRequestParams) GetOtherParams() url.Values {
+	urlValue := url.Values{}
+	urlValue.Add(ReturnUrlFiled, t.ReturnUrl)
+	urlValue.Add(NotifyUrlFiled, t.NotifyUrl)
+	urlValue.Add(AppAuthTokenFiled, t.AppAuthToken)
+	urlValue.Add(ApiMethodNameFiled, "alipay.trade.page.refund")
+	bytes, _ := json.Marshal(t)
+	urlValue.Add(BizContentFiled, string(bytes))
+	return urlValue
+}
+
+func (t *TradePageRefundRequestParams) GetNeedEncrypt() bool {
+	return t.NeedEncrypt == true
 }
 
 ///////////////////////////////////////////////////////////////////////////////////////
@@ -528,24 +666,24 @@ type TradeFundBillParams struct {
 type TradeAppPayRequestParams struct {
 	OtherRequestParams
 
-	OutTradeNo          string                `json:"out_trade_no"`                    // 商户订单号。由商家自定义，64个字符以内，仅支持字母、数字、下划线且需保证在商户端不重复。
-	TotalAmount         string                `json:"total_amount"`                    // 订单总金额。单位为元，精确到小数点后两位，取值范围：[0.01,100000000] 。
-	Subject             string                `json:"subject"`                         // 订单标题。 注意：不可使用特殊字符，如 /，=，& 等。
-	ProductCode         string                `json:"product_code"`                    // 产品码。 商家和支付宝签约的产品码。 枚举值（点击查看签约情况）：QUICK_MSECURITY_PAY：无线快捷支付产品；CYCLE_PAY_AUTH：周期扣款产品。默认值为QUICK_MSECURITY_PAY。
-	Body                string                `json:"body,omitempty"`                  // 订单附加信息。如果请求时传递了该参数，将在异步通知、对账单中原样返回，同时会在商户和用户的pc账单详情中作为交易描述展示
-	GoodsDetail         []*GoodsDetailParams  `json:"goods_detail,omitempty"`          // 订单包含的商品列表信息，json格式，其它说明详见商品明细说明
-	TimeExpire          string                `json:"time_expire,omitempty"`           // 订单绝对超时时间。格式为yyyy-MM-dd HH:mm:ss。注：time_expire和timeout_express两者只需传入一个或者都不传，如果两者都传，优先使用time_expire。
-	TimeExpress         string                `json:"time_express,omitempty"`          // 建议使用time_expire字段。 订单相对超时时间。从买家确认支付时间开始计算。该笔订单允许的最晚付款时间，逾期将关闭交易。取值范围：5m～15d。m-分钟，h-小时，d-天，1c-当天（1c-当天的情况下，无论交易何时创建，都在0点关闭）。 该参数数值不接受小数点， 如 1.5h，可转换为 90m。默认值为15d。注：1. 无线支付场景最小值为5m，低于5m支付超时时间按5m计算。2. time_expire和timeout_express两者只需传入一个或者都不传，如果两者都传，优先使用time_expire。
-	ExtendParams        []*ExtendParamsParams `json:"extend_params,omitempty"`         // 业务扩展参数
-	PromoParams         string                `json:"promo_params,omitempty"`          // 优惠参数 注：仅与支付宝协商后可用
-	PassbackParams      string                `json:"passback_params,omitempty"`       // 公用回传参数。 如果请求时传递了该参数，支付宝会在异步通知时将该参数原样返回。本参数必须进行UrlEncode之后才可以发送给支付宝。
-	AgreementSignParams *SignParamsParams     `json:"agreement_sign_params,omitempty"` // 签约参数。如果希望在sdk中支付并签约，需要在这里传入签约信息。 周期扣款场景 product_code 为 CYCLE_PAY_AUTH 时必填。
-	StoreId             string                `json:"store_id,omitempty"`              // 商户门店编号。 指商户创建门店时输入的门店编号。
-	EnablePayChannels   string                `json:"enable_pay_channels,omitempty"`   // 指定支付渠道，多个渠道以逗号分割。用户只能使用此处指定渠道进行支付。 与disable_pay_channels互斥，支持传入的值：渠道列表。注意：如果传入了指定支付渠道，则用户只能用指定内的渠道支付，包括营销渠道也要指定才能使用。若所有指定渠道用户都不可使用，将导致用户无法支付，慎用。
-	SpecifiedChannel    string                `json:"specified_channel,omitempty"`     // 指定单通道，仅支持传入一个渠道。 注意：目前仅支持传入 pcredit，若由于用户原因指定渠道不可用（不能支付），允许用户选择其他渠道支付。该参数不可与花呗分期参数同时传入。
-	DisablePayChannels  string                `json:"disable_pay_channels,omitempty"`  // 禁用渠道,用户不可用指定渠道支付，多个渠道以逗号分割 注，与enable_pay_channels互斥
-	MerchantOrderNo     string                `json:"merchant_order_no,omitempty"`     // 商户的原始订单号
-	ExtUserInfo         *ExtUserInfo          `json:"ext_user_info,omitempty"`         // 外部指定买家
+	OutTradeNo          string                `json:"out_trade_no" alipay:"required,max=64,pattern=out_trade_no"` // 商户订单号。由商家自定义，64个字符以内，仅支持字母、数字、下划线且需保证在商户端不重复。
+	TotalAmount         Amount                `json:"total_amount" alipay:"required,amount"`                      // 订单总金额。单位为元，精确到小数点后两位，取值范围：[0.01,100000000] 。
+	Subject             string                `json:"subject" alipay:"required,max=256"`                          // 订单标题。 注意：不可使用特殊字符，如 /，=，& 等。
+	ProductCode         ProductCode           `json:"product_code"`                                               // 产品码。 商家和支付宝签约的产品码。 枚举值（点击查看签约情况）：QUICK_MSECURITY_PAY：无线快捷支付产品；CYCLE_PAY_AUTH：周期扣款产品。默认值为QUICK_MSECURITY_PAY。
+	Body                string                `json:"body,omitempty"`                                             // 订单附加信息。如果请求时传递了该参数，将在异步通知、对账单中原样返回，同时会在商户和用户的pc账单详情中作为交易描述展示
+	GoodsDetail         []*GoodsDetailParams  `json:"goods_detail,omitempty"`                                     // 订单包含的商品列表信息，json格式，其它说明详见商品明细说明
+	TimeExpire          string                `json:"time_expire,omitempty"`                                      // 订单绝对超时时间。格式为yyyy-MM-dd HH:mm:ss。注：time_expire和timeout_express两者只需传入一个或者都不传，如果两者都传，优先使用time_expire。
+	TimeExpress         string                `json:"time_express,omitempty"`                                     // 建议使用time_expire字段。 订单相对超时时间。从买家确认支付时间开始计算。该笔订单允许的最晚付款时间，逾期将关闭交易。取值范围：5m～15d。m-分钟，h-小时，d-天，1c-当天（1c-当天的情况下，无论交易何时创建，都在0点关闭）。 该参数数值不接受小数点， 如 1.5h，可转换为 90m。默认值为15d。注：1. 无线支付场景最小值为5m，低于5m支付超时时间按5m计算。2. time_expire和timeout_express两者只需传入一个或者都不传，如果两者都传，优先使用time_expire。
+	ExtendParams        []*ExtendParamsParams `json:"extend_params,omitempty"`                                    // 业务扩展参数
+	PromoParams         string                `json:"promo_params,omitempty"`                                     // 优惠参数 注：仅与支付宝协商后可用
+	PassbackParams      string                `json:"passback_params,omitempty"`                                  // 公用回传参数。 如果请求时传递了该参数，支付宝会在异步通知时将该参数原样返回。本参数必须进行UrlEncode之后才可以发送给支付宝。
+	AgreementSignParams *SignParamsParams     `json:"agreement_sign_params,omitempty"`                            // 签约参数。如果希望在sdk中支付并签约，需要在这里传入签约信息。 周期扣款场景 product_code 为 CYCLE_PAY_AUTH 时必填。
+	StoreId             string                `json:"store_id,omitempty"`                                         // 商户门店编号。 指商户创建门店时输入的门店编号。
+	EnablePayChannels   string                `json:"enable_pay_channels,omitempty" alipay:"exclusive=channels"`  // 指定支付渠道，多个渠道以逗号分割。用户只能使用此处指定渠道进行支付。 与disable_pay_channels互斥，支持传入的值：渠道列表。注意：如果传入了指定支付渠道，则用户只能用指定内的渠道支付，包括营销渠道也要指定才能使用。若所有指定渠道用户都不可使用，将导致用户无法支付，慎用。
+	SpecifiedChannel    string                `json:"specified_channel,omitempty"`                                // 指定单通道，仅支持传入一个渠道。 注意：目前仅支持传入 pcredit，若由于用户原因指定渠道不可用（不能支付），允许用户选择其他渠道支付。该参数不可与花呗分期参数同时传入。
+	DisablePayChannels  string                `json:"disable_pay_channels,omitempty" alipay:"exclusive=channels"` // 禁用渠道,用户不可用指定渠道支付，多个渠道以逗号分割 注，与enable_pay_channels互斥
+	MerchantOrderNo     string                `json:"merchant_order_no,omitempty"`                                // 商户的原始订单号
+	ExtUserInfo         *ExtUserInfo          `json:"ext_user_info,omitempty"`                                    // 外部指定买家
 }
 
 func (t *TradeAppPayRequestParams) GetOtherParams() url.Values {
@@ -586,12 +724,12 @@ type SignMerchantParams struct {
 }
 
 type PeriodRuleParams struct {
-	PeriodType    string  `json:"period_type"`              // 周期类型period_type是周期扣款产品必填，枚举值为DAY和MONTH。 DAY即扣款周期按天计，MONTH代表扣款周期按自然月。与另一参数period组合使用确定扣款周期，例如period_type为DAY，period=30，则扣款周期为30天；period_type为MONTH，period=3，则扣款周期为3个自然月。自然月是指，不论这个月有多少天，周期都计算到月份中的同一日期。例如1月3日到2月3日为一个自然月，1月3日到4月3日为三个自然月。注意周期类型使用MONTH的时候，计划扣款时间execute_time不允许传28日之后的日期（可以传28日），以此避免有些月份可能不存在对应日期的情况。
-	Period        int     `json:"period"`                   // 周期数period是周期扣款产品必填。与另一参数period_type组合使用确定扣款周期，例如period_type为DAY，period=90，则扣款周期为90天。
-	ExecuteTime   string  `json:"execute_time"`             // 首次执行时间execute_time是周期扣款产品必填，即商户发起首次扣款的时间。精确到日，格式为yyyy-MM-dd 结合其他必填的扣款周期参数，会确定商户以后的扣款计划。发起扣款的时间需符合这里的扣款计划。
-	SingleAmount  float64 `json:"single_amount"`            // 单次扣款最大金额single_amount是周期扣款产品必填，即每次发起扣款时限制的最大金额，单位为元。商户每次发起扣款都不允许大于此金额。
-	TotalAmount   float64 `json:"total_amount,omitempty"`   // 总金额限制，单位为元。如果传入此参数，商户多次扣款的累计金额不允许超过此金额。
-	TotalPayments int     `json:"total_payments,omitempty"` // 总扣款次数。如果传入此参数，则商户成功扣款的次数不能超过此次数限制（扣款失败不计入）。
+	PeriodType    string `json:"period_type"`              // 周期类型period_type是周期扣款产品必填，枚举值为DAY和MONTH。 DAY即扣款周期按天计，MONTH代表扣款周期按自然月。与另一参数period组合使用确定扣款周期，例如period_type为DAY，period=30，则扣款周期为30天；period_type为MONTH，period=3，则扣款周期为3个自然月。自然月是指，不论这个月有多少天，周期都计算到月份中的同一日期。例如1月3日到2月3日为一个自然月，1月3日到4月3日为三个自然月。注意周期类型使用MONTH的时候，计划扣款时间execute_time不允许传28日之后的日期（可以传28日），以此避免有些月份可能不存在对应日期的情况。
+	Period        int    `json:"period"`                   // 周期数period是周期扣款产品必填。与另一参数period_type组合使用确定扣款周期，例如period_type为DAY，period=90，则扣款周期为90天。
+	ExecuteTime   string `json:"execute_time"`             // 首次执行时间execute_time是周期扣款产品必填，即商户发起首次扣款的时间。精确到日，格式为yyyy-MM-dd 结合其他必填的扣款周期参数，会确定商户以后的扣款计划。发起扣款的时间需符合这里的扣款计划。
+	SingleAmount  Amount `json:"single_amount"`            // 单次扣款最大金额single_amount是周期扣款产品必填，即每次发起扣款时限制的最大金额，单位为元。商户每次发起扣款都不允许大于此金额。
+	TotalAmount   Amount `json:"total_amount,omitempty"`   // 总金额限制，单位为元。如果传入此参数，商户多次扣款的累计金额不允许超过此金额。
+	TotalPayments int    `json:"total_payments,omitempty"` // 总扣款次数。如果传入此参数，则商户成功扣款的次数不能超过此次数限制（扣款失败不计入）。
 }
 
 type ExtUserInfo struct {
@@ -611,31 +749,34 @@ type ExtUserInfo struct {
 type TradePagePayRequestParams struct {
 	OtherRequestParams
 
-	OutTradeNo          string               `json:"out_trade_no"`                    // 商户订单号。由商家自定义，64个字符以内，仅支持字母、数字、下划线且需保证在商户端不重复。
-	TotalAmount         string               `json:"total_amount"`                    // 订单总金额。单位为元，精确到小数点后两位，取值范围：[0.01,100000000] 。
-	Subject             string               `json:"subject"`                         // 订单标题。 注意：不可使用特殊字符，如 /，=，& 等。
-	ProductCode         string               `json:"product_code"`                    // 产品码。 商家和支付宝签约的产品码。 枚举值（点击查看签约情况）：QUICK_MSECURITY_PAY：无线快捷支付产品；CYCLE_PAY_AUTH：周期扣款产品。默认值为QUICK_MSECURITY_PAY。
-	Body                string               `json:"body,omitempty"`                  // 订单附加信息。如果请求时传递了该参数，将在异步通知、对账单中原样返回，同时会在商户和用户的pc账单详情中作为交易描述展示
-	QrPayMode           string               `json:"qr_pay_mode,omitempty"`           // PC扫码支付的方式。 支持前置模式和跳转模式。前置模式是将二维码前置到商户的订单确认页的模式。需要商户在自己的页面中以 iframe 方式请求支付宝页面。具体支持的枚举值请查看文档
-	QrcodeWidth         string               `json:"qrcode_width,omitempty"`          // 商户自定义二维码宽度。 注：qr_pay_mode=4时该参数有效
-	GoodsDetail         []*GoodsDetailParams `json:"goods_detail,omitempty"`          // 订单包含的商品列表信息，json格式，其它说明详见商品明细说明
-	TimeExpire          string               `json:"time_expire,omitempty"`           // 订单绝对超时时间。格式为yyyy-MM-dd HH:mm:ss。注：time_expire和timeout_express两者只需传入一个或者都不传，如果两者都传，优先使用time_expire。
-	TimeExpress         string               `json:"time_express,omitempty"`          // 建议使用time_expire字段。 订单相对超时时间。从买家确认支付时间开始计算。该笔订单允许的最晚付款时间，逾期将关闭交易。取值范围：5m～15d。m-分钟，h-小时，d-天，1c-当天（1c-当天的情况下，无论交易何时创建，都在0点关闭）。 该参数数值不接受小数点， 如 1.5h，可转换为 90m。默认值为15d。注：1. 无线支付场景最小值为5m，低于5m支付超时时间按5m计算。2. time_expire和timeout_express两者只需传入一个或者都不传，如果两者都传，优先使用time_expire。
-	RoyaltyInfo         *RoyaltyInfo         `json:"royalty_info,omitempty"`          // 描述分账信息，json格式。
-	SubMerchant         *SubMerchant         `json:"sub_merchant,omitempty"`          // 二级商户信息。 直付通模式和机构间连模式下必传，其它场景下不需要传入。
-	SettleInfo          *SettleInfoParams    `json:"settle_info,omitempty"`           // 描述结算信息，json格式。
-	ExtendParams        *ExtendParamsParams  `json:"extend_params,omitempty"`         // 业务扩展参数
-	BusinessParams      string               `json:"business_params,omitempty"`       // 商户传入业务信息，具体值要和支付宝约定，应用于安全，营销等参数直传场景，格式为json格式
-	PromoParams         string               `json:"promo_params,omitempty"`          // 优惠参数 注：仅与支付宝协商后可用
-	IntegrationType     string               `json:"integration_type,omitempty"`      // 请求后页面的集成方式。 枚举值：ALIAPP：支付宝钱包内PCWEB：PC端访问默认值为PCWEB。
-	RequestFromUrl      string               `json:"request_from_url,omitempty"`      // 请求来源地址。如果使用ALIAPP的集成方式，用户中途取消支付会返回该地址。
-	AgreementSignParams *AgreementSignParams `json:"agreement_sign_params,omitempty"` // 签约参数，支付后签约场景使用
-	StoreId             string               `json:"store_id,omitempty"`              // 商户门店编号。 指商户创建门店时输入的门店编号。
-	EnablePayChannels   string               `json:"enable_pay_channels,omitempty"`   // 指定支付渠道，多个渠道以逗号分割。用户只能使用此处指定渠道进行支付。 与disable_pay_channels互斥，支持传入的值：渠道列表。注意：如果传入了指定支付渠道，则用户只能用指定内的渠道支付，包括营销渠道也要指定才能使用。若所有指定渠道用户都不可使用，将导致用户无法支付，慎用。
-	DisablePayChannels  string               `json:"disable_pay_channels,omitempty"`  // 禁用渠道,用户不可用指定渠道支付，多个渠道以逗号分割 注，与enable_pay_channels互斥
-	MerchantOrderNo     string               `json:"merchant_order_no,omitempty"`     // 商户的原始订单号
-	ExtUserInfo         *ExtUserInfo         `json:"ext_user_info,omitempty"`         // 外部指定买家
-	InvoiceInfo         *InvoiceInfo         `json:"invoice_info,omitempty"`          // 开票信息
+	OutTradeNo          string               `json:"out_trade_no" alipay:"required,max=64,pattern=out_trade_no"` // 商户订单号。由商家自定义，64个字符以内，仅支持字母、数字、下划线且需保证在商户端不重复。
+	TotalAmount         Amount               `json:"total_amount" alipay:"required,amount"`                      // 订单总金额。单位为元，精确到小数点后两位，取值范围：[0.01,100000000] 。
+	Subject             string               `json:"subject" alipay:"required,max=256"`                          // 订单标题。 注意：不可使用特殊字符，如 /，=，& 等。
+	ProductCode         ProductCode          `json:"product_code"`                                               // 产品码。 商家和支付宝签约的产品码。 枚举值（点击查看签约情况）：QUICK_MSECURITY_PAY：无线快捷支付产品；CYCLE_PAY_AUTH：周期扣款产品。默认值为QUICK_MSECURITY_PAY。
+	Body                string               `json:"body,omitempty"`                                             // 订单附加信息。如果请求时传递了该参数，将在异步通知、对账单中原样返回，同时会在商户和用户的pc账单详情中作为交易描述展示
+	QrPayMode           string               `json:"qr_pay_mode,omitempty"`                                      // PC扫码支付的方式。 支持前置模式和跳转模式。前置模式是将二维码前置到商户的订单确认页的模式。需要商户在自己的页面中以 iframe 方式请求支付宝页面。具体支持的枚举值请查看文档
+	QrcodeWidth         string               `json:"qrcode_width,omitempty"`                                     // 商户自定义二维码宽度。 注：qr_pay_mode=4时该参数有效
+	GoodsDetail         []*GoodsDetailParams `json:"goods_detail,omitempty"`                                     // 订单包含的商品列表信息，json格式，其它说明详见商品明细说明
+	TimeExpire          string               `json:"time_expire,omitempty"`                                      // 订单绝对超时时间。格式为yyyy-MM-dd HH:mm:ss。注：time_expire和timeout_express两者只需传入一个或者都不传，如果两者都传，优先使用time_expire。
+	TimeExpress         string               `json:"time_express,omitempty"`                                     // 建议使用time_expire字段。 订单相对超时时间。从买家确认支付时间开始计算。该笔订单允许的最晚付款时间，逾期将关闭交易。取值范围：5m～15d。m-分钟，h-小时，d-天，1c-当天（1c-当天的情况下，无论交易何时创建，都在0点关闭）。 该参数数值不接受小数点， 如 1.5h，可转换为 90m。默认值为15d。注：1. 无线支付场景最小值为5m，低于5m支付超时时间按5m计算。2. time_expire和timeout_express两者只需传入一个或者都不传，如果两者都传，优先使用time_expire。
+	RoyaltyInfo         *RoyaltyInfo         `json:"royalty_info,omitempty"`                                     // 描述分账信息，json格式。
+	SubMerchant         *SubMerchant         `json:"sub_merchant,omitempty"`                                     // 二级商户信息。 直付通模式和机构间连模式下必传，其它场景下不需要传入。
+	SettleInfo          *SettleInfoParams    `json:"settle_info,omitempty"`                                      // 描述结算信息，json格式。
+	ExtendParams        *ExtendParamsParams  `json:"extend_params,omitempty"`                                    // 业务扩展参数
+	BusinessParams      string               `json:"business_params,omitempty"`                                  // 商户传入业务信息，具体值要和支付宝约定，应用于安全，营销等参数直传场景，格式为json格式
+	PromoParams         string               `json:"promo_params,omitempty"`                                     // 优惠参数 注：仅与支付宝协商后可用
+	PassbackParams      string               `json:"passback_params,omitempty"`                                  // 公用回传参数。 如果请求时传递了该参数，支付宝会在异步通知时将该参数原样返回。本参数必须进行UrlEncode之后才可以发送给支付宝。
+	IntegrationType     string               `json:"integration_type,omitempty"`                                 // 请求后页面的集成方式。 枚举值：ALIAPP：支付宝钱包内PCWEB：PC端访问默认值为PCWEB。
+	RequestFromUrl      string               `json:"request_from_url,omitempty"`                                 // 请求来源地址。如果使用ALIAPP的集成方式，用户中途取消支付会返回该地址。
+	AgreementSignParams *AgreementSignParams `json:"agreement_sign_params,omitempty"`                            // 签约参数，支付后签约场景使用
+	StoreId             string               `json:"store_id,omitempty"`                                         // 商户门店编号。 指商户创建门店时输入的门店编号。
+	EnablePayChannels   string               `json:"enable_pay_channels,omitempty" alipay:"exclusive=channels"`  // 指定支付渠道，多个渠道以逗号分割。用户只能使用此处指定渠道进行支付。 与disable_pay_channels互斥，支持传入的值：渠道列表。注意：如果传入了指定支付渠道，则用户只能用指定内的渠道支付，包括营销渠道也要指定才能使用。若所有指定渠道用户都不可使用，将导致用户无法支付，慎用。
+	DisablePayChannels  string               `json:"disable_pay_channels,omitempty" alipay:"exclusive=channels"` // 禁用渠道,用户不可用指定渠道支付，多个渠道以逗号分割 注，与enable_pay_channels互斥
+	MerchantOrderNo     string               `json:"merchant_order_no,omitempty"`                                // 商户的原始订单号
+	ExtUserInfo         *ExtUserInfo         `json:"ext_user_info,omitempty"`                                    // 外部指定买家
+	InvoiceInfo         *InvoiceInfo         `json:"invoice_info,omitempty"`                                     // 开票信息
+	TransCurrency       Currency             `json:"trans_currency,omitempty"`                                   // 标价币种，跨境支付时使用。total_amount 对应的币种单位，支持英镑：GBP、港币：HKD、美元：USD、新加坡元：SGD、日元：JPY、加拿大元：CAD、澳元：AUD、欧元：EUR、新西兰元：NZD、韩元：KRW、泰铢：THB、瑞士法郎：CHF、瑞典克朗：SEK、丹麦克朗：DKK、挪威克朗：NOK、马来西亚林吉特：MYR、印尼卢比：IDR、菲律宾比索：PHP、毛里求斯卢比：MUR、以色列新谢克尔：ILS、斯里兰卡卢比：LKR、俄罗斯卢布：RUB、阿联酋迪拉姆：AED、捷克克朗：CZK、南非兰特：ZAR、人民币：CNY。指定时 total_amount 以该币种的最小单位保存（可使用 ParseAmountIn 解析），按该币种的小数位数序列化
+	SettleCurrency      Currency             `json:"settle_currency,omitempty"`                                  // 商户指定的结算币种，跨境支付时使用。支持的币种同 trans_currency
 }
 
 func (t *TradePagePayRequestParams) GetOtherParams() url.Values {
@@ -653,24 +794,33 @@ func (t *TradePagePayRequestParams) GetNeedEncrypt() bool {
 	return t.NeedEncrypt == true
 }
 
+// MarshalJSON 指定标价币种时按币种的小数位数序列化金额，如JPY为"1000"，KWD为"1.234"
+func (t TradePagePayRequestParams) MarshalJSON() ([]byte, error) {
+	type params TradePagePayRequestParams
+	return json.Marshal(struct {
+		params
+		TotalAmount CurrencyAmount `json:"total_amount"`
+	}{params(t), t.TotalAmount.CurrencyAmount(t.TransCurrency)})
+}
+
 // RoyaltyInfo 分账信息
 type RoyaltyInfo struct {
-	RoyaltyType        string              `json:"royalty_type,omitempty"` // 分账类型 卖家的分账类型，目前只支持传入ROYALTY（普通分账类型）
-	RoyaltyDetailInfos *RoyaltyDetailInfos `json:"royalty_detail_infos"`   // 分账明细的信息，可以描述多条分账指令，json数组。
+	RoyaltyType        string                `json:"royalty_type,omitempty"` // 分账类型 卖家的分账类型，目前只支持传入ROYALTY（普通分账类型）
+	RoyaltyDetailInfos []*RoyaltyDetailInfos `json:"royalty_detail_infos"`   // 分账明细的信息，可以描述多条分账指令，json数组。
 }
 
 // RoyaltyDetailInfos 分账明细信息
 type RoyaltyDetailInfos struct {
-	SerialNo         int     `json:"serial_no,omitempty"`         // 分账序列号，表示分账执行的顺序，必须为正整数
-	TransInType      string  `json:"trans_in_type,omitempty"`     // 接受分账金额的账户类型
-	BatchNo          string  `json:"batch_no"`                    // 分账批次号 分账批次号。目前需要和转入账号类型为bankIndex配合使用。
-	OutRelationId    string  `json:"out_relation_id,omitempty"`   // 商户分账的外部关联号，用于关联到每一笔分账信息，商户需保证其唯一性。 如果为空，该值则默认为“商户网站唯一订单号+分账序列号”
-	TransOutType     string  `json:"trans_out_type"`              // 要分账的账户类型。 目前只支持userId：支付宝账号对应的支付宝唯一用户号。默认值为userId。
-	TransOut         string  `json:"trans_out"`                   // 如果转出账号类型为userId，本参数为要分账的支付宝账号对应的支付宝唯一用户号。以2088开头的纯16位数字。
-	TransIn          string  `json:"trans_in"`                    // 如果转入账号类型为userId，本参数为接受分账金额的支付宝账号对应的支付宝唯一用户号。以2088开头的纯16位数字。  如果转入账号类型为bankIndex，本参数为28位的银行编号（商户和支付宝签约时确定）。如果转入账号类型为storeId，本参数为商户的门店ID。
-	Amount           float64 `json:"amount"`                      // 分账的金额，单位为元
-	Desc             float64 `json:"desc,omitempty"`              // 分账描述信息
-	AmountPercentage string  `json:"amount_percentage,omitempty"` // 分账的比例，值为20代表按20%的比例分账
+	SerialNo         int    `json:"serial_no,omitempty"`         // 分账序列号，表示分账执行的顺序，必须为正整数
+	TransInType      string `json:"trans_in_type,omitempty"`     // 接受分账金额的账户类型
+	BatchNo          string `json:"batch_no"`                    // 分账批次号 分账批次号。目前需要和转入账号类型为bankIndex配合使用。
+	OutRelationId    string `json:"out_relation_id,omitempty"`   // 商户分账的外部关联号，用于关联到每一笔分账信息，商户需保证其唯一性。 如果为空，该值则默认为“商户网站唯一订单号+分账序列号”
+	TransOutType     string `json:"trans_out_type"`              // 要分账的账户类型。 目前只支持userId：支付宝账号对应的支付宝唯一用户号。默认值为userId。
+	TransOut         string `json:"trans_out"`                   // 如果转出账号类型为userId，本参数为要分账的支付宝账号对应的支付宝唯一用户号。以2088开头的纯16位数字。
+	TransIn          string `json:"trans_in"`                    // 如果转入账号类型为userId，本参数为接受分账金额的支付宝账号对应的支付宝唯一用户号。以2088开头的纯16位数字。  如果转入账号类型为bankIndex，本参数为28位的银行编号（商户和支付宝签约时确定）。如果转入账号类型为storeId，本参数为商户的门店ID。
+	Amount           Amount `json:"amount"`                      // 分账的金额，单位为元
+	Desc             string `json:"desc,omitempty"`              // 分账描述信息
+	AmountPercentage string `json:"amount_percentage,omitempty"` // 分账的比例，值为20代表按20%的比例分账
 
 }
 
@@ -709,15 +859,509 @@ type InvoiceKeyInfo struct {
 
 ///////////////////////////////////////////////////////////////////////////////////////
 
+// UserAgreementPageSignRequestParams 支付宝个人协议页面签约接口请求参数
+// 文档地址：https://opendocs.alipay.com/open/02fkan
+type UserAgreementPageSignRequestParams struct {
+	OtherRequestParams
+	PersonalProductCode string              `json:"personal_product_code" alipay:"required"`         // 个人签约产品码，周期扣款为 CYCLE_PAY_AUTH_P，商户代扣为 GENERAL_WITHHOLDING_P
+	ProductCode         ProductCode         `json:"product_code,omitempty"`                          // 销售产品码，周期扣款为 CYCLE_PAY_AUTH，商户代扣为 GENERAL_WITHHOLDING
+	SignScene           string              `json:"sign_scene,omitempty"`                            // 协议签约场景，商户和支付宝签约时确定
+	ExternalAgreementNo string              `json:"external_agreement_no,omitempty" alipay:"max=32"` // 商户签约号，代扣协议中标示用户的唯一签约号（确保在商户系统中唯一）
+	ExternalLogonId     string              `json:"external_logon_id,omitempty"`                     // 用户在商户网站的登录账号，用于在签约页面展示
+	AccessParams        *AccessParams       `json:"access_params" alipay:"required"`                 // 签约的接入方式，如 ALIPAYAPP（钱包h5页面签约）、QRCODE（扫码签约）
+	PeriodRuleParams    *PeriodRuleParams   `json:"period_rule_params,omitempty"`                    // 周期管控规则参数，签约周期扣款产品时必传
+	SignValidityPeriod  string              `json:"sign_validity_period,omitempty"`                  // 协议有效周期，整形数字加上时间单位d（天）或m（月），未传入时默认为长期有效
+	ThirdPartyType      string              `json:"third_party_type,omitempty"`                      // 签约第三方主体类型：PARTNER（平台商户，默认值）、MERCHANT（集团商户）
+	SubMerchant         *SignMerchantParams `json:"sub_merchant,omitempty"`                          // 子商户信息
+	MerchantProcessUrl  string              `json:"merchant_process_url,omitempty"`                  // 签约成功后跳转的商户页面地址
+	EffectTime          int                 `json:"effect_time,omitempty"`                           // 签约有效时间，单位为秒，超过该时间未签约则签约请求失效
+}
+
+func (u *UserAgreementPageSignRequestParams) GetOtherParams() url.Values {
+	urlValue := url.Values{}
+	urlValue.Add(NotifyUrlFiled, u.NotifyUrl)
+	urlValue.Add(ReturnUrlFiled, u.ReturnUrl)
+	urlValue.Add(AppAuthTokenFiled, u.AppAuthToken)
+	urlValue.Add(ApiMethodNameFiled, "alipay.user.agreement.page.sign")
+	bytes, _ := json.Marshal(u)
+	urlValue.Add(BizContentFiled, string(bytes))
+	return urlValue
+}
+
+func (u *UserAgreementPageSignRequestParams) GetNeedEncrypt() bool {
+	return u.NeedEncrypt == true
+}
+
+///////////////////////////////////////////////////////////////////////////////////////
+
+// UserAgreementQueryRequestParams 支付宝个人代扣协议查询接口请求参数
+// 文档地址：https://opendocs.alipay.com/open/02fkao
+type UserAgreementQueryRequestParams struct {
+	OtherRequestParams
+	AgreementNo         string `json:"agreement_no,omitempty"`          // 支付宝系统中用以唯一标识用户签约记录的编号，传入时其他参数可不传
+	PersonalProductCode string `json:"personal_product_code,omitempty"` // 个人签约产品码，未传入协议号 agreement_no 时必传
+	AlipayUserId        string `json:"alipay_user_id,omitempty"`        // 用户的支付宝账号对应的支付宝唯一用户号，与 alipay_logon_id 不能同时为空
+	AlipayLogonId       string `json:"alipay_logon_id,omitempty"`       // 用户的支付宝登录账号
+	SignScene           string `json:"sign_scene,omitempty"`            // 签约协议场景
+	ExternalAgreementNo string `json:"external_agreement_no,omitempty"` // 商户签约号
+	ThirdPartyType      string `json:"third_party_type,omitempty"`      // 签约第三方主体类型
+}
+
+func (u *UserAgreementQueryRequestParams) GetOtherParams() url.Values {
+	urlValue := url.Values{}
+	urlValue.Add(AppAuthTokenFiled, u.AppAuthToken)
+	urlValue.Add(ApiMethodNameFiled, "alipay.user.agreement.query")
+	bytes, _ := json.Marshal(u)
+	urlValue.Add(BizContentFiled, string(bytes))
+	return urlValue
+}
+
+func (u *UserAgreementQueryRequestParams) GetNeedEncrypt() bool {
+	return u.NeedEncrypt == true
+}
+
+// UserAgreementQueryResponseParams 支付宝个人代扣协议查询接口响应参数
+type UserAgreementQueryResponseParams struct {
+	Data struct {
+		CommonResParams
+		AgreementNo         string          `json:"agreement_no"`          // 支付宝系统中用以唯一标识用户签约记录的编号
+		Status              AgreementStatus `json:"status"`                // 协议当前状态：TEMP（暂存）、NORMAL（正常）、STOP（暂停）
+		PersonalProductCode string          `json:"personal_product_code"` // 协议产品码
+		SignScene           string          `json:"sign_scene"`            // 签约协议的场景
+		SignTime            string          `json:"sign_time"`             // 协议签约时间，格式为yyyy-MM-dd HH:mm:ss
+		ValidTime           string          `json:"valid_time"`            // 协议生效时间
+		InvalidTime         string          `json:"invalid_time"`          // 协议失效时间
+		AlipayLogonId       string          `json:"alipay_logon_id"`       // 用户的支付宝登录账号
+		PrincipalId         string          `json:"principal_id"`          // 签约主体标识，签约主体类型为用户时为支付宝用户号
+		PrincipalType       string          `json:"pricipal_type"`         // 签约主体类型：CARD（支付宝账号）、CUSTOMER（支付宝用户）
+		ExternalAgreementNo string          `json:"external_agreement_no"` // 商户签约号
+		ExternalLogonId     string          `json:"external_logon_id"`     // 用户在商户网站的登录账号
+		ThirdPartyType      string          `json:"third_party_type"`      // 签约第三方主体类型
+		DeviceId            string          `json:"device_id"`             // 设备ID
+		ZmOpenId            string          `json:"zm_open_id"`            // 用户在芝麻信用的唯一标识
+		CreditAuthMode      string          `json:"credit_auth_mode"`      // 授信模式
+		SingleQuota         Amount          `json:"single_quota"`          // 单笔代扣额度
+		LastDeductTime      string          `json:"last_deduct_time"`      // 周期扣协议上次扣款成功时间
+		NextDeductTime      string          `json:"next_deduct_time"`      // 周期扣协议预计下次扣款时间
+	} `json:"alipay_user_agreement_query_response"`
+	Sign string `json:"sign"` // 签名
+}
+
+///////////////////////////////////////////////////////////////////////////////////////
+
+// UserAgreementUnsignRequestParams 支付宝个人代扣协议解约接口请求参数
+// 文档地址：https://opendocs.alipay.com/open/02fkar
+type UserAgreementUnsignRequestParams struct {
+	OtherRequestParams
+	AgreementNo         string `json:"agreement_no,omitempty"`          // 支付宝系统中用以唯一标识用户签约记录的编号，传入时其他参数可不传
+	PersonalProductCode string `json:"personal_product_code,omitempty"` // 个人签约产品码，未传入协议号 agreement_no 时必传
+	AlipayUserId        string `json:"alipay_user_id,omitempty"`        // 用户的支付宝账号对应的支付宝唯一用户号
+	AlipayLogonId       string `json:"alipay_logon_id,omitempty"`       // 用户的支付宝登录账号
+	SignScene           string `json:"sign_scene,omitempty"`            // 签约协议场景
+	ExternalAgreementNo string `json:"external_agreement_no,omitempty"` // 商户签约号
+	ThirdPartyType      string `json:"third_party_type,omitempty"`      // 签约第三方主体类型
+	ExtendParams        string `json:"extend_params,omitempty"`         // 扩展参数，JSON格式
+	OperateType         string `json:"operate_type,omitempty"`          // 操作类型：confirm（解约确认）、invalid（解约作废）
+}
+
+func (u *UserAgreementUnsignRequestParams) GetOtherParams() url.Values {
+	urlValue := url.Values{}
+	urlValue.Add(NotifyUrlFiled, u.NotifyUrl)
+	urlValue.Add(AppAuthTokenFiled, u.AppAuthToken)
+	urlValue.Add(ApiMethodNameFiled, "alipay.user.agreement.unsign")
+	bytes, _ := json.Marshal(u)
+	urlValue.Add(BizContentFiled, string(bytes))
+	return urlValue
+}
+
+func (u *UserAgreementUnsignRequestParams) GetNeedEncrypt() bool {
+	return u.NeedEncrypt == true
+}
+
+// UserAgreementUnsignResponseParams 支付宝个人代扣协议解约接口响应参数
+type UserAgreementUnsignResponseParams struct {
+	Data struct {
+		CommonResParams
+	} `json:"alipay_user_agreement_unsign_response"`
+	Sign string `json:"sign"` // 签名
+}
+
+///////////////////////////////////////////////////////////////////////////////////////
+
+// UserAgreementExecutionPlanModifyRequestParams 周期性扣款协议执行计划修改接口请求参数
+// 文档地址：https://opendocs.alipay.com/open/02fkaq
+type UserAgreementExecutionPlanModifyRequestParams struct {
+	OtherRequestParams
+	AgreementNo string `json:"agreement_no" alipay:"required,max=64"` // 周期性扣款产品，授权免密支付协议号
+	DeductTime  string `json:"deduct_time" alipay:"required"`         // 商户下一次扣款时间，格式为yyyy-MM-dd
+	Memo        string `json:"memo,omitempty"`                        // 具体修改原因
+}
+
+func (u *UserAgreementExecutionPlanModifyRequestParams) GetOtherParams() url.Values {
+	urlValue := url.Values{}
+	urlValue.Add(AppAuthTokenFiled, u.AppAuthToken)
+	urlValue.Add(ApiMethodNameFiled, "alipay.user.agreement.executionplan.modify")
+	bytes, _ := json.Marshal(u)
+	urlValue.Add(BizContentFiled, string(bytes))
+	return urlValue
+}
+
+func (u *UserAgreementExecutionPlanModifyRequestParams) GetNeedEncrypt() bool {
+	return u.NeedEncrypt == true
+}
+
+// UserAgreementExecutionPlanModifyResponseParams 周期性扣款协议执行计划修改接口响应参数
+type UserAgreementExecutionPlanModifyResponseParams struct {
+	Data struct {
+		CommonResParams
+		AgreementNo string `json:"agreement_no"` // 周期性扣款产品，授权免密支付协议号
+		DeductTime  string `json:"deduct_time"`  // 商户下一次扣款时间
+	} `json:"alipay_user_agreement_executionplan_modify_response"`
+	Sign string `json:"sign"` // 签名
+}
+
+///////////////////////////////////////////////////////////////////////////////////////
+
+// AgreementTradePayRequestParams 协议扣款请求参数，使用 alipay.trade.pay 根据已签约的代扣协议扣款
+// 文档地址：https://opendocs.alipay.com/open/02fkat
+type AgreementTradePayRequestParams struct {
+	OtherRequestParams
+	OutTradeNo      string                 `json:"out_trade_no" alipay:"required,max=64,pattern=out_trade_no"` // 商户订单号，同一笔扣款重试时需使用相同的订单号
+	TotalAmount     Amount                 `json:"total_amount" alipay:"required,amount"`                      // 订单总金额，周期扣款不能超过协议的单次扣款最大金额
+	Subject         string                 `json:"subject" alipay:"required,max=256"`                          // 订单标题
+	ProductCode     ProductCode            `json:"product_code" alipay:"required"`                             // 销售产品码，周期扣款为 CYCLE_PAY_AUTH，商户代扣为 GENERAL_WITHHOLDING
+	AgreementParams *AgreementParamsParams `json:"agreement_params" alipay:"required"`                         // 代扣信息
+	Body            string                 `json:"body,omitempty"`                                             // 订单附加信息
+	BuyerId         string                 `json:"buyer_id,omitempty"`                                         // 买家支付宝用户ID
+	StoreId         string                 `json:"store_id,omitempty"`                                         // 商户门店编号
+	TimeoutExpress  string                 `json:"timeout_express,omitempty"`                                  // 订单相对超时时间
+	QueryOptions    []string               `json:"query_options,omitempty"`                                    // 返回参数选项
+}
+
+// AgreementParamsParams 代扣信息
+type AgreementParamsParams struct {
+	AgreementNo   string `json:"agreement_no" alipay:"required"` // 支付宝系统中用以唯一标识用户签约记录的编号（用户签约成功后的协议号）
+	AuthConfirmNo string `json:"auth_confirm_no,omitempty"`      // 鉴权确认码，在需要做支付鉴权校验时，该参数不能为空
+	ApplyToken    string `json:"apply_token,omitempty"`          // 鉴权申请 token，其格式和内容，由支付宝定义
+}
+
+func (t *AgreementTradePayRequestParams) GetOtherParams() url.Values {
+	urlValue := url.Values{}
+	urlValue.Add(NotifyUrlFiled, t.NotifyUrl)
+	urlValue.Add(AppAuthTokenFiled, t.AppAuthToken)
+	urlValue.Add(ApiMethodNameFiled, "alipay.trade.pay")
+	bytes, _ := json.Marshal(t)
+	urlValue.Add(BizContentFiled, string(bytes))
+	return urlValue
+}
+
+func (t *AgreementTradePayRequestParams) GetNeedEncrypt() bool {
+	return t.NeedEncrypt == true
+}
+
+///////////////////////////////////////////////////////////////////////////////////////
+
+// RoyaltyEntity 分账接收方
+type RoyaltyEntity struct {
+	Type          string `json:"type" alipay:"required"`    // 分账接收方方类型：userId（支付宝账号对应的支付宝唯一用户号）、loginName（支付宝登录号）、openId
+	Account       string `json:"account" alipay:"required"` // 分账接收方账号
+	Name          string `json:"name,omitempty"`            // 分账接收方真实姓名，绑定时传入则校验与支付宝账号的一致性
+	Memo          string `json:"memo,omitempty"`            // 分账关系描述
+	LoginName     string `json:"login_name,omitempty"`      // 分账接收方的支付宝登录号，查询时返回
+	BindLoginName string `json:"bind_login_name,omitempty"` // 作为分账关系绑定的支付宝登录号，查询时返回
+}
+
+// TradeRoyaltyRelationBindRequestParams 分账关系绑定接口请求参数
+// 文档地址：https://opendocs.alipay.com/open/02c7hq
+type TradeRoyaltyRelationBindRequestParams struct {
+	OtherRequestParams
+	ReceiverList []*RoyaltyEntity `json:"receiver_list" alipay:"required"`         // 分账接收方列表，单次传入最多20个
+	OutRequestNo string           `json:"out_request_no" alipay:"required,max=64"` // 外部请求号，由商家自定义，需保证唯一
+}
+
+func (t *TradeRoyaltyRelationBindRequestParams) GetOtherParams() url.Values {
+	urlValue := url.Values{}
+	urlValue.Add(AppAuthTokenFiled, t.AppAuthToken)
+	urlValue.Add(ApiMethodNameFiled, "alipay.trade.royalty.relation.bind")
+	bytes, _ := json.Marshal(t)
+	urlValue.Add(BizContentFiled, string(bytes))
+	return urlValue
+}
+
+func (t *TradeRoyaltyRelationBindRequestParams) GetNeedEncrypt() bool {
+	return t.NeedEncrypt == true
+}
+
+// TradeRoyaltyRelationBindResponseParams 分账关系绑定接口响应参数
+type TradeRoyaltyRelationBindResponseParams struct {
+	Data struct {
+		CommonResParams
+		ResultCode string `json:"result_code"` // 绑定结果：SUCCESS（成功）、FAIL（失败）
+	} `json:"alipay_trade_royalty_relation_bind_response"`
+	Sign string `json:"sign"` // 签名
+}
+
+///////////////////////////////////////////////////////////////////////////////////////
+
+// TradeRoyaltyRelationUnbindRequestParams 分账关系解绑接口请求参数
+// 文档地址：https://opendocs.alipay.com/open/02c7hr
+type TradeRoyaltyRelationUnbindRequestParams struct {
+	OtherRequestParams
+	ReceiverList []*RoyaltyEntity `json:"receiver_list" alipay:"required"`         // 分账接收方列表，单次传入最多20个
+	OutRequestNo string           `json:"out_request_no" alipay:"required,max=64"` // 外部请求号，由商家自定义，需保证唯一
+}
+
+func (t *TradeRoyaltyRelationUnbindRequestParams) GetOtherParams() url.Values {
+	urlValue := url.Values{}
+	urlValue.Add(AppAuthTokenFiled, t.AppAuthToken)
+	urlValue.Add(ApiMethodNameFiled, "alipay.trade.royalty.relation.unbind")
+	bytes, _ := json.Marshal(t)
+	urlValue.Add(BizContentFiled, string(bytes))
+	return urlValue
+}
+
+func (t *TradeRoyaltyRelationUnbindRequestParams) GetNeedEncrypt() bool {
+	return t.NeedEncrypt == true
+}
+
+// TradeRoyaltyRelationUnbindResponseParams 分账关系解绑接口响应参数
+type TradeRoyaltyRelationUnbindResponseParams struct {
+	Data struct {
+		CommonResParams
+		ResultCode string `json:"result_code"` // 解绑结果：SUCCESS（成功）、FAIL（失败）
+	} `json:"alipay_trade_royalty_relation_unbind_response"`
+	Sign string `json:"sign"` // 签名
+}
+
+///////////////////////////////////////////////////////////////////////////////////////
+
+// TradeRoyaltyRelationBatchQueryRequestParams 分账关系查询接口请求参数
+// 文档地址：https://opendocs.alipay.com/open/02c7hs
+type TradeRoyaltyRelationBatchQueryRequestParams struct {
+	OtherRequestParams
+	PageNum      int    `json:"page_num,omitempty"`                      // 页码，从1开始，默认为1
+	PageSize     int    `json:"page_size,omitempty"`                     // 页面大小，每页记录数，取值范围是(0,100]，默认为20
+	OutRequestNo string `json:"out_request_no" alipay:"required,max=64"` // 外部请求号，由商家自定义，需保证唯一
+}
+
+func (t *TradeRoyaltyRelationBatchQueryRequestParams) GetOtherParams() url.Values {
+	urlValue := url.Values{}
+	urlValue.Add(AppAuthTokenFiled, t.AppAuthToken)
+	urlValue.Add(ApiMethodNameFiled, "alipay.trade.royalty.relation.batchquery")
+	bytes, _ := json.Marshal(t)
+	urlValue.Add(BizContentFiled, string(bytes))
+	return urlValue
+}
+
+func (t *TradeRoyaltyRelationBatchQueryRequestParams) GetNeedEncrypt() bool {
+	return t.NeedEncrypt == true
+}
+
+// TradeRoyaltyRelationBatchQueryResponseParams 分账关系查询接口响应参数
+type TradeRoyaltyRelationBatchQueryResponseParams struct {
+	Data struct {
+		CommonResParams
+		ResultCode      string           `json:"result_code"`       // 查询结果：SUCCESS（成功）、FAIL（失败）
+		ReceiverList    []*RoyaltyEntity `json:"receiver_list"`     // 分账接收方列表
+		TotalPageNum    int              `json:"total_page_num"`    // 总页数
+		TotalRecordNum  int              `json:"total_record_num"`  // 总记录数
+		CurrentPageNum  int              `json:"current_page_num"`  // 当前页码
+		CurrentPageSize int              `json:"current_page_size"` // 当前页面大小
+	} `json:"alipay_trade_royalty_relation_batchquery_response"`
+	Sign string `json:"sign"` // 签名
+}
+
+///////////////////////////////////////////////////////////////////////////////////////
+
+// TradeOrderSettleRequestParams 统一收单交易结算接口请求参数，交易成功后按分账明细将资金分给分账接收方
+// 文档地址：https://opendocs.alipay.com/open/02c7i5
+type TradeOrderSettleRequestParams struct {
+	OtherRequestParams
+	OutRequestNo      string                                `json:"out_request_no" alipay:"required,max=64"` // 结算请求流水号，由商家自定义，需保证唯一，同一笔结算重试时需使用相同的值
+	TradeNo           string                                `json:"trade_no" alipay:"required,max=64"`       // 支付宝交易号
+	RoyaltyParameters []*OpenApiRoyaltyDetailInfoPojoParams `json:"royalty_parameters" alipay:"required"`    // 分账明细信息
+	OperatorId        string                                `json:"operator_id,omitempty"`                   // 操作员id
+	ExtendParams      *SettleExtendParams                   `json:"extend_params,omitempty"`                 // 分账扩展参数
+	RoyaltyMode       string                                `json:"royalty_mode,omitempty"`                  // 分账模式：sync（同步执行，默认值）、async（异步执行，结果通过结算通知返回）
+}
+
+// SettleExtendParams 分账扩展参数
+type SettleExtendParams struct {
+	RoyaltyFinish string `json:"royalty_finish,omitempty"` // 是否完结分账：true 表示本次分账后解冻交易剩余的待分账金额，后续不能再分账
+}
+
+func (t *TradeOrderSettleRequestParams) GetOtherParams() url.Values {
+	urlValue := url.Values{}
+	urlValue.Add(NotifyUrlFiled, t.NotifyUrl)
+	urlValue.Add(AppAuthTokenFiled, t.AppAuthToken)
+	urlValue.Add(ApiMethodNameFiled, "alipay.trade.order.settle")
+	bytes, _ := json.Marshal(t)
+	urlValue.Add(BizContentFiled, string(bytes))
+	return urlValue
+}
+
+func (t *TradeOrderSettleRequestParams) GetNeedEncrypt() bool {
+	return t.NeedEncrypt == true
+}
+
+// TradeOrderSettleResponseParams 统一收单交易结算接口响应参数
+type TradeOrderSettleResponseParams struct {
+	Data struct {
+		CommonResParams
+		TradeNo  string `json:"trade_no"`  // 支付宝交易号
+		SettleNo string `json:"settle_no"` // 支付宝分账单号，可以根据该单号查询单次分账请求执行结果
+	} `json:"alipay_trade_order_settle_response"`
+	Sign string `json:"sign"` // 签名
+}
+
+///////////////////////////////////////////////////////////////////////////////////////
+
+// TradeOrderSettleQueryRequestParams 交易分账查询接口请求参数
+// 文档地址：https://opendocs.alipay.com/open/02pj6l
+type TradeOrderSettleQueryRequestParams struct {
+	OtherRequestParams
+	SettleNo     string `json:"settle_no,omitempty" alipay:"oneof=settle"`      // 支付宝分账单号，与结算请求流水号、支付宝交易号的组合不能同时为空
+	OutRequestNo string `json:"out_request_no,omitempty" alipay:"oneof=settle"` // 结算请求流水号，需与支付宝交易号同时传入
+	TradeNo      string `json:"trade_no,omitempty"`                             // 支付宝交易号
+}
+
+func (t *TradeOrderSettleQueryRequestParams) GetOtherParams() url.Values {
+	urlValue := url.Values{}
+	urlValue.Add(AppAuthTokenFiled, t.AppAuthToken)
+	urlValue.Add(ApiMethodNameFiled, "alipay.trade.order.settle.query")
+	bytes, _ := json.Marshal(t)
+	urlValue.Add(BizContentFiled, string(bytes))
+	return urlValue
+}
+
+func (t *TradeOrderSettleQueryRequestParams) GetNeedEncrypt() bool {
+	return t.NeedEncrypt == true
+}
+
+// TradeOrderSettleQueryResponseParams 交易分账查询接口响应参数
+type TradeOrderSettleQueryResponseParams struct {
+	Data struct {
+		CommonResParams
+		OutRequestNo      string                 `json:"out_request_no"`      // 结算请求流水号
+		OperationDt       string                 `json:"operation_dt"`        // 分账受理时间
+		RoyaltyDetailList []*SettleRoyaltyDetail `json:"royalty_detail_list"` // 分账明细
+	} `json:"alipay_trade_order_settle_query_response"`
+	Sign string `json:"sign"` // 签名
+}
+
+// SettleRoyaltyDetail 分账明细的执行结果
+type SettleRoyaltyDetail struct {
+	OperationType string            `json:"operation_type"` // 分账操作类型：replenish（补差）、replenish_refund（退补差）、transfer（分账）、transfer_refund（退分账）
+	ExecuteDt     string            `json:"execute_dt"`     // 分账执行时间
+	TransOut      string            `json:"trans_out"`      // 分账转出账号
+	TransOutType  string            `json:"trans_out_type"` // 分账转出账号类型
+	TransIn       string            `json:"trans_in"`       // 分账转入账号
+	TransInType   string            `json:"trans_in_type"`  // 分账转入账号类型
+	Amount        Amount            `json:"amount"`         // 分账金额
+	State         SettleDetailState `json:"state"`          // 分账状态：SUCCESS（成功）、FAIL（失败）、PROCESSING（处理中）
+	DetailId      string            `json:"detail_id"`      // 分账明细单号
+	ErrorCode     string            `json:"error_code"`     // 分账失败的错误码，仅当分账失败时返回
+	ErrorDesc     string            `json:"error_desc"`     // 分账失败的错误描述信息，仅当分账失败时返回
+}
+
+///////////////////////////////////////////////////////////////////////////////////////
+
+// TradeRoyaltyRateQueryRequestParams 分账比例查询接口请求参数
+// 文档地址：https://opendocs.alipay.com/open/02c7i6
+type TradeRoyaltyRateQueryRequestParams struct {
+	OtherRequestParams
+	OutRequestNo string `json:"out_request_no" alipay:"required,max=64"` // 外部请求号，由商家自定义，需保证唯一
+}
+
+func (t *TradeRoyaltyRateQueryRequestParams) GetOtherParams() url.Values {
+	urlValue := url.Values{}
+	urlValue.Add(AppAuthTokenFiled, t.AppAuthToken)
+	urlValue.Add(ApiMethodNameFiled, "alipay.trade.royalty.rate.query")
+	bytes, _ := json.Marshal(t)
+	urlValue.Add(BizContentFiled, string(bytes))
+	return urlValue
+}
+
+func (t *TradeRoyaltyRateQueryRequestParams) GetNeedEncrypt() bool {
+	return t.NeedEncrypt == true
+}
+
+// TradeRoyaltyRateQueryResponseParams 分账比例查询接口响应参数
+type TradeRoyaltyRateQueryResponseParams struct {
+	Data struct {
+		CommonResParams
+		UserId   string `json:"user_id"`   // 分账受理方的支付宝用户ID
+		MaxRatio int    `json:"max_ratio"` // 可分账的最大比例，百分比的整数部分，如30表示30%
+	} `json:"alipay_trade_royalty_rate_query_response"`
+	Sign string `json:"sign"` // 签名
+}
+
+///////////////////////////////////////////////////////////////////////////////////////
+
+// TradeWapPayRequestParams 手机网站支付接口2.0请求参数
+// 文档地址：https://opendocs.alipay.com/apis/api_1/alipay.trade.wap.pay
+type TradeWapPayRequestParams struct {
+	OtherRequestParams
+
+	OutTradeNo         string               `json:"out_trade_no" alipay:"required,max=64,pattern=out_trade_no"` // 商户订单号。由商家自定义，64个字符以内，仅支持字母、数字、下划线且需保证在商户端不重复。
+	TotalAmount        Amount               `json:"total_amount" alipay:"required,amount"`                      // 订单总金额。单位为元，精确到小数点后两位，取值范围：[0.01,100000000] 。
+	Subject            string               `json:"subject" alipay:"required,max=256"`                          // 订单标题。 注意：不可使用特殊字符，如 /，=，& 等。
+	ProductCode        ProductCode          `json:"product_code"`                                               // 销售产品码，商家和支付宝签约的产品码。手机网站支付为：QUICK_WAP_WAY，未填写时默认使用QUICK_WAP_WAY
+	QuitUrl            string               `json:"quit_url,omitempty" alipay:"max=400"`                        // 用户付款中途退出返回商户网站的地址
+	AuthToken          string               `json:"auth_token,omitempty"`                                       // 针对用户授权接口，获取用户相关数据时，用于标识用户授权关系
+	Body               string               `json:"body,omitempty"`                                             // 订单附加信息。如果请求时传递了该参数，将在异步通知、对账单中原样返回，同时会在商户和用户的pc账单详情中作为交易描述展示
+	GoodsDetail        []*GoodsDetailParams `json:"goods_detail,omitempty"`                                     // 订单包含的商品列表信息，json格式，其它说明详见商品明细说明
+	TimeExpire         string               `json:"time_expire,omitempty"`                                      // 订单绝对超时时间。格式为yyyy-MM-dd HH:mm:ss。注：time_expire和timeout_express两者只需传入一个或者都不传，如果两者都传，优先使用time_expire。
+	TimeoutExpress     string               `json:"timeout_express,omitempty"`                                  // 建议使用time_expire字段。订单相对超时时间。取值范围：1m～15d。m-分钟，h-小时，d-天，1c-当天（无论交易何时创建，都在0点关闭）。该参数数值不接受小数点，如1.5h，可转换为90m。
+	ExtendParams       *ExtendParamsParams  `json:"extend_params,omitempty"`                                    // 业务扩展参数
+	BusinessParams     string               `json:"business_params,omitempty"`                                  // 商户传入业务信息，具体值要和支付宝约定，应用于安全，营销等参数直传场景，格式为json格式
+	PromoParams        string               `json:"promo_params,omitempty"`                                     // 优惠参数 注：仅与支付宝协商后可用
+	PassbackParams     string               `json:"passback_params,omitempty"`                                  // 公用回传参数。 如果请求时传递了该参数，支付宝会在异步通知时将该参数原样返回。本参数必须进行UrlEncode之后才可以发送给支付宝。
+	StoreId            string               `json:"store_id,omitempty"`                                         // 商户门店编号。 指商户创建门店时输入的门店编号。
+	EnablePayChannels  string               `json:"enable_pay_channels,omitempty" alipay:"exclusive=channels"`  // 指定支付渠道，多个渠道以逗号分割。用户只能使用此处指定渠道进行支付。与disable_pay_channels互斥
+	DisablePayChannels string               `json:"disable_pay_channels,omitempty" alipay:"exclusive=channels"` // 禁用渠道,用户不可用指定渠道支付，多个渠道以逗号分割 注，与enable_pay_channels互斥
+	SpecifiedChannel   string               `json:"specified_channel,omitempty"`                                // 指定单通道，仅支持传入一个渠道。目前仅支持传入 pcredit
+	MerchantOrderNo    string               `json:"merchant_order_no,omitempty"`                                // 商户的原始订单号
+	ExtUserInfo        *ExtUserInfo         `json:"ext_user_info,omitempty"`                                    // 外部指定买家
+	TransCurrency      Currency             `json:"trans_currency,omitempty"`                                   // 标价币种，跨境支付时使用。total_amount 对应的币种单位。指定时 total_amount 以该币种的最小单位保存（可使用 ParseAmountIn 解析），按该币种的小数位数序列化
+	SettleCurrency     Currency             `json:"settle_currency,omitempty"`                                  // 商户指定的结算币种，跨境支付时使用。支持的币种同 trans_currency
+}
+
+func (t *TradeWapPayRequestParams) GetOtherParams() url.Values {
+	urlValue := url.Values{}
+	urlValue.Add(ReturnUrlFiled, t.ReturnUrl)
+	urlValue.Add(NotifyUrlFiled, t.NotifyUrl)
+	urlValue.Add(AppAuthTokenFiled, t.AppAuthToken)
+	urlValue.Add(ApiMethodNameFiled, "alipay.trade.wap.pay")
+	bytes, _ := json.Marshal(t)
+	urlValue.Add(BizContentFiled, string(bytes))
+	return urlValue
+}
+
+func (t *TradeWapPayRequestParams) GetNeedEncrypt() bool {
+	return t.NeedEncrypt == true
+}
+
+// MarshalJSON 指定标价币种时按币种的小数位数序列化金额，如JPY为"1000"，KWD为"1.234"
+func (t TradeWapPayRequestParams) MarshalJSON() ([]byte, error) {
+	type params TradeWapPayRequestParams
+	return json.Marshal(struct {
+		params
+		TotalAmount CurrencyAmount `json:"total_amount"`
+	}{params(t), t.TotalAmount.CurrencyAmount(t.TransCurrency)})
+}
+
+///////////////////////////////////////////////////////////////////////////////////////
+
 // TradeFastPayRefundQueryRequestParams 统一收单交易退款查询请求参数
 // 文档地址：https://opendocs.alipay.com/apis/0287wc
 type TradeFastPayRefundQueryRequestParams struct {
 	OtherRequestParams
 
-	TradeNo      string    `json:"trade_no,omitempty"`      // 支付宝交易号。 和商户订单号不能同时为空
-	OutTradeNo   string    `json:"out_trade_no,omitempty"`  // 商户订单号。 订单支付时传入的商户订单号,和支付宝交易号不能同时为空。 trade_no,out_trade_no如果同时存在优先取trade_no
-	OutRequestNo string    `json:"out_request_no"`          // 退款请求号。 请求退款接口时，传入的退款请求号，如果在退款请求时未传入，则该值为创建交易时的商户订单号。
-	QueryOptions []*string `json:"query_options,omitempty"` // 查询选项
+	TradeNo      string    `json:"trade_no,omitempty" alipay:"oneof=trade,max=64"`     // 支付宝交易号。 和商户订单号不能同时为空
+	OutTradeNo   string    `json:"out_trade_no,omitempty" alipay:"oneof=trade,max=64"` // 商户订单号。 订单支付时传入的商户订单号,和支付宝交易号不能同时为空。 trade_no,out_trade_no如果同时存在优先取trade_no
+	OutRequestNo string    `json:"out_request_no" alipay:"required,max=64"`            // 退款请求号。 请求退款接口时，传入的退款请求号，如果在退款请求时未传入，则该值为创建交易时的商户订单号。
+	QueryOptions []*string `json:"query_options,omitempty"`                            // 查询选项
 }
 
 func (t *TradeFastPayRefundQueryRequestParams) GetOtherParams() url.Values {
@@ -740,13 +1384,13 @@ type TradeFastPayRefundQueryResponseParams struct {
 		TradeNo              string                      `json:"trade_no"`                // 支付宝交易号
 		OutTradeNo           string                      `json:"out_trade_no"`            // 创建交易传入的商户订单号
 		OutRequestNo         string                      `json:"out_request_no"`          // 本笔退款对应的退款请求号。
-		TotalAmount          float64                     `json:"total_amount"`            // 该笔退款所对应的交易的订单金额
-		RefundAmount         float64                     `json:"refund_amount"`           // 本次退款请求，对应的退款金额
-		RefundStatus         string                      `json:"refund_status"`           // 退款状态。枚举值： REFUND_SUCCESS 退款处理成功；未返回该字段表示退款请求未收到或者退款失败；注：如果退款查询发起时间早于退款时间，或者间隔退款发起时间太短，可能出现退款查询时还没处理成功，后面又处理成功的情况，建议商户在退款发起后间隔10秒以上再发起退款查询请求。
+		TotalAmount          Amount                      `json:"total_amount"`            // 该笔退款所对应的交易的订单金额
+		RefundAmount         Amount                      `json:"refund_amount"`           // 本次退款请求，对应的退款金额
+		RefundStatus         RefundStatus                `json:"refund_status"`           // 退款状态。枚举值： REFUND_SUCCESS 退款处理成功；未返回该字段表示退款请求未收到或者退款失败；注：如果退款查询发起时间早于退款时间，或者间隔退款发起时间太短，可能出现退款查询时还没处理成功，后面又处理成功的情况，建议商户在退款发起后间隔10秒以上再发起退款查询请求。
 		RefundRoyaltys       []RefundRoyaltyResultParams `json:"refund_royaltys"`         // 退分账明细信息
 		GmtRefundWay         string                      `json:"gmt_refund_way"`          // 退款时间。默认不返回该信息，需要在入参的query_options中指定"gmt_refund_pay"值时才返回该字段信息。格式为yyyy-MM-dd HH:mm:ss
 		RefundDetailItemList []TradeFundBillParams       `json:"refund_detail_item_list"` // 本次退款使用的资金渠道；默认不返回该信息，需要在入参的query_options中指定"refund_detail_item_list"值时才返回该字段信息。
-		SendBackFee          string                      `json:"send_back_fee"`           // 本次商户实际退回金额；默认不返回该信息，需要在入参的query_options中指定"refund_detail_item_list"值时才返回该字段信息。
+		SendBackFee          Amount                      `json:"send_back_fee"`           // 本次商户实际退回金额；默认不返回该信息，需要在入参的query_options中指定"refund_detail_item_list"值时才返回该字段信息。
 		DepositBackInfo      DepositBackInfoParams       `json:"deposit_back_info"`       // 银行卡冲退信息。 该字段默认不返回；
 		EnterprisePayInfo    EnterprisePayInfoParams     `json:"enterprise_pay_info"`     // 因公付退款信息，只有入参的query_options中指定enterprise_pay_info时才返回该字段信息
 	} `json:"alipay_trade_fastpay_refund_query_response"`
@@ -755,22 +1399,22 @@ type TradeFastPayRefundQueryResponseParams struct {
 
 // RefundRoyaltyResultParams 退分账明细信息
 type RefundRoyaltyResultParams struct {
-	RefundAmount  float64 `json:"refund_amount"`   // 退分账金额
-	RoyaltyType   string  `json:"royalty_type"`    // 分账类型. 普通分账为：transfer;补差为：replenish;为空默认为分账transfer;
-	ResultCode    string  `json:"result_code"`     // 退分账结果码
-	TransOut      string  `json:"trans_out"`       // 转出人支付宝账号对应用户ID
-	TransOutEmail string  `json:"trans_out_email"` // 转出人支付宝账号
-	TransIn       string  `json:"trans_in"`        // 转入人支付宝账号对应用户ID
-	TransInEmail  string  `json:"trans_in_email"`  // 转入人支付宝账号
+	RefundAmount  Amount `json:"refund_amount"`   // 退分账金额
+	RoyaltyType   string `json:"royalty_type"`    // 分账类型. 普通分账为：transfer;补差为：replenish;为空默认为分账transfer;
+	ResultCode    string `json:"result_code"`     // 退分账结果码
+	TransOut      string `json:"trans_out"`       // 转出人支付宝账号对应用户ID
+	TransOutEmail string `json:"trans_out_email"` // 转出人支付宝账号
+	TransIn       string `json:"trans_in"`        // 转入人支付宝账号对应用户ID
+	TransInEmail  string `json:"trans_in_email"`  // 转入人支付宝账号
 }
 
 // DepositBackInfoParams 银行卡冲退信息。
 type DepositBackInfoParams struct {
-	HasDepositBack     string  `json:"has_deposit_back"`      // 是否存在银行卡冲退信息。
-	DbackStatus        string  `json:"dback_status"`          // 银行卡冲退状态。S-成功，F-失败，P-处理中。银行卡冲退失败，资金自动转入用户支付宝余额。
-	DbackAmount        float64 `json:"dback_amount"`          // 银行卡冲退金额
-	BankAckTime        string  `json:"bank_ack_time"`         // 银行响应时间，格式为yyyy-MM-dd HH:mm:ss
-	EstBankReceiptTime string  `json:"est_bank_receipt_time"` // 预估银行到账时间，格式为yyyy-MM-dd HH:mm:ss
+	HasDepositBack     string `json:"has_deposit_back"`      // 是否存在银行卡冲退信息。
+	DbackStatus        string `json:"dback_status"`          // 银行卡冲退状态。S-成功，F-失败，P-处理中。银行卡冲退失败，资金自动转入用户支付宝余额。
+	DbackAmount        Amount `json:"dback_amount"`          // 银行卡冲退金额
+	BankAckTime        string `json:"bank_ack_time"`         // 银行响应时间，格式为yyyy-MM-dd HH:mm:ss
+	EstBankReceiptTime string `json:"est_bank_receipt_time"` // 预估银行到账时间，格式为yyyy-MM-dd HH:mm:ss
 }
 
 ///////////////////////////////////////////////////////////////////////////////////////
@@ -780,8 +1424,8 @@ type DepositBackInfoParams struct {
 type TradeBillDownloadUrlQueryRequestParams struct {
 	OtherRequestParams
 
-	BillType string `json:"bill_type"` // 必选 账单类型，商户通过接口或商户经开放平台授权后其所属服务商通过接口可以获取以下账单类型：trade、signcustomer；trade指商户基于支付宝交易收单的业务账单；signcustomer是指基于商户支付宝余额收入及支出等资金变动的帐务账单。
-	BillDate string `json:"bill_date"` // 必选 账单时间：日账单格式为yyyy-MM-dd，最早可下载2016年1月1日开始的日账单；月账单格式为yyyy-MM，最早可下载2016年1月开始的月账单。
+	BillType string `json:"bill_type" alipay:"required"` // 必选 账单类型，商户通过接口或商户经开放平台授权后其所属服务商通过接口可以获取以下账单类型：trade、signcustomer；trade指商户基于支付宝交易收单的业务账单；signcustomer是指基于商户支付宝余额收入及支出等资金变动的帐务账单。
+	BillDate string `json:"bill_date" alipay:"required"` // 必选 账单时间：日账单格式为yyyy-MM-dd，最早可下载2016年1月1日开始的日账单；月账单格式为yyyy-MM，最早可下载2016年1月开始的月账单。
 }
 
 func (t *TradeBillDownloadUrlQueryRequestParams) GetOtherParams() url.Values {
@@ -809,17 +1453,176 @@ type TradeBillDownloadUrlQueryResponseParams struct {
 
 ///////////////////////////////////////////////////////////////////////////////////////
 
+// DataBillBalanceQueryRequestParams 支付宝商家账户当前余额查询请求参数
+// 文档地址：https://opendocs.alipay.com/apis/api_15/alipay.data.bill.balance.query
+type DataBillBalanceQueryRequestParams struct {
+	OtherRequestParams
+
+	BillUserId string `json:"bill_user_id,omitempty"` // 目标查询账户（仅支持服务商代查询其授权的商户）
+}
+
+func (t *DataBillBalanceQueryRequestParams) GetOtherParams() url.Values {
+	urlValue := url.Values{}
+	urlValue.Add(AppAuthTokenFiled, t.AppAuthToken)
+	urlValue.Add(ApiMethodNameFiled, "alipay.data.bill.balance.query")
+	bytes, _ := json.Marshal(t)
+	urlValue.Add(BizContentFiled, string(bytes))
+	return urlValue
+}
+
+func (t *DataBillBalanceQueryRequestParams) GetNeedEncrypt() bool {
+	return t.NeedEncrypt == true
+}
+
+// DataBillBalanceQueryResponseParams 支付宝商家账户当前余额查询响应参数
+type DataBillBalanceQueryResponseParams struct {
+	Data struct {
+		CommonResParams
+		TotalAmount     Amount `json:"total_amount"`     // 支付宝账户余额
+		AvailableAmount Amount `json:"available_amount"` // 账户可用余额
+		FreezeAmount    Amount `json:"freeze_amount"`    // 冻结金额
+		SettleAmount    Amount `json:"settle_amount"`    // 待结算金额
+	} `json:"alipay_data_bill_balance_query_response"`
+	Sign string `json:"sign"` // 签名
+}
+
+///////////////////////////////////////////////////////////////////////////////////////
+
+// DataBillAccountLogQueryRequestParams 支付宝商家账户账务明细查询请求参数
+// 文档地址：https://opendocs.alipay.com/apis/api_15/alipay.data.bill.accountlog.query
+type DataBillAccountLogQueryRequestParams struct {
+	OtherRequestParams
+
+	StartTime       string `json:"start_time" alipay:"required"` // 账务流水创建时间的起始范围，格式为yyyy-MM-dd HH:mm:ss
+	EndTime         string `json:"end_time" alipay:"required"`   // 账务流水创建时间的结束范围，与起始时间间隔不超过31天，查询结果为起始时间至结束时间的左闭右开区间
+	AlipayOrderNo   string `json:"alipay_order_no,omitempty"`    // 支付宝订单号，通过支付宝订单号精确查询相关的流水明细，商户订单号与支付宝订单号互斥
+	MerchantOrderNo string `json:"merchant_order_no,omitempty"`  // 商户订单号，通过商户订单号精确查询相关的流水明细，商户订单号与支付宝订单号互斥
+	PageNo          string `json:"page_no,omitempty"`            // 分页号，从1开始
+	PageSize        string `json:"page_size,omitempty"`          // 分页大小1000-2000，默认2000
+	TransCode       string `json:"trans_code,omitempty"`         // 账务的类型代码，特殊场景下使用
+	BillUserId      string `json:"bill_user_id,omitempty"`       // 指定用户做账单查询
+}
+
+func (t *DataBillAccountLogQueryRequestParams) GetOtherParams() url.Values {
+	urlValue := url.Values{}
+	urlValue.Add(AppAuthTokenFiled, t.AppAuthToken)
+	urlValue.Add(ApiMethodNameFiled, "alipay.data.bill.accountlog.query")
+	bytes, _ := json.Marshal(t)
+	urlValue.Add(BizContentFiled, string(bytes))
+	return urlValue
+}
+
+func (t *DataBillAccountLogQueryRequestParams) GetNeedEncrypt() bool {
+	return t.NeedEncrypt == true
+}
+
+// DataBillAccountLogQueryResponseParams 支付宝商家账户账务明细查询响应参数
+type DataBillAccountLogQueryResponseParams struct {
+	Data struct {
+		CommonResParams
+		PageNo     string                 `json:"page_no"`     // 分页号，从1开始
+		PageSize   string                 `json:"page_size"`   // 分页大小
+		TotalSize  string                 `json:"total_size"`  // 账务明细总数
+		DetailList []AccountLogItemParams `json:"detail_list"` // 账务明细列表
+	} `json:"alipay_data_bill_accountlog_query_response"`
+	Sign string `json:"sign"` // 签名
+}
+
+// AccountLogItemParams 账务明细
+type AccountLogItemParams struct {
+	TransDt             string `json:"trans_dt"`               // 入账时间
+	AccountLogId        string `json:"account_log_id"`         // 支付宝账务流水号
+	AlipayOrderNo       string `json:"alipay_order_no"`        // 支付宝订单号
+	MerchantOrderNo     string `json:"merchant_order_no"`      // 商户订单号
+	TransAmount         Amount `json:"trans_amount"`           // 金额，收入为正数，支出为负数
+	Balance             Amount `json:"balance"`                // 余额，仅供参考
+	Type                string `json:"type"`                   // 账务记录的类型，如在线支付、转账、提现
+	OtherAccount        string `json:"other_account"`          // 对方账户
+	TransMemo           string `json:"trans_memo"`             // 收入/支出的备注
+	Direction           string `json:"direction"`              // 收支类型：收入、支出
+	BillSource          string `json:"bill_source"`            // 业务账单来源
+	BizNos              string `json:"biz_nos"`                // 业务订单号
+	BizOrigNo           string `json:"biz_orig_no"`            // 业务基础订单号
+	BizDesc             string `json:"biz_desc"`               // 业务描述
+	MerchantOutRefundNo string `json:"merchant_out_refund_no"` // 支付宝交易商户退款请求号
+	StoreName           string `json:"store_name"`             // 门店名称
+}
+
+///////////////////////////////////////////////////////////////////////////////////////
+
+// DataBillSellQueryRequestParams 支付宝商家账户交易明细查询请求参数
+// 文档地址：https://opendocs.alipay.com/apis/api_15/alipay.data.bill.sell.query
+type DataBillSellQueryRequestParams struct {
+	OtherRequestParams
+
+	StartTime       string `json:"start_time" alipay:"required"` // 交易流水创建时间的起始范围，格式为yyyy-MM-dd HH:mm:ss
+	EndTime         string `json:"end_time" alipay:"required"`   // 交易流水创建时间的结束范围，与起始时间间隔不超过31天，查询结果为起始时间至结束时间的左闭右开区间
+	AlipayOrderNo   string `json:"alipay_order_no,omitempty"`    // 支付宝交易流水号，商户订单号与支付宝交易流水号互斥
+	MerchantOrderNo string `json:"merchant_order_no,omitempty"`  // 商户订单号，商户订单号与支付宝交易流水号互斥
+	StoreNo         string `json:"store_no,omitempty"`           // 门店编号，模糊搜索
+	PageNo          string `json:"page_no,omitempty"`            // 分页号，从1开始
+	PageSize        string `json:"page_size,omitempty"`          // 分页大小1000-2000，默认2000
+	BillUserId      string `json:"bill_user_id,omitempty"`       // 指定用户做账单查询
+}
+
+func (t *DataBillSellQueryRequestParams) GetOtherParams() url.Values {
+	urlValue := url.Values{}
+	urlValue.Add(AppAuthTokenFiled, t.AppAuthToken)
+	urlValue.Add(ApiMethodNameFiled, "alipay.data.bill.sell.query")
+	bytes, _ := json.Marshal(t)
+	urlValue.Add(BizContentFiled, string(bytes))
+	return urlValue
+}
+
+func (t *DataBillSellQueryRequestParams) GetNeedEncrypt() bool {
+	return t.NeedEncrypt == true
+}
+
+// DataBillSellQueryResponseParams 支付宝商家账户交易明细查询响应参数
+type DataBillSellQueryResponseParams struct {
+	Data struct {
+		CommonResParams
+		PageNo     string                  `json:"page_no"`     // 分页号，从1开始
+		PageSize   string                  `json:"page_size"`   // 分页大小
+		TotalSize  string                  `json:"total_size"`  // 交易流水总数
+		DetailList []TradeItemResultParams `json:"detail_list"` // 交易流水详情
+	} `json:"alipay_data_bill_sell_query_response"`
+	Sign string `json:"sign"` // 签名
+}
+
+// TradeItemResultParams 交易流水详情
+type TradeItemResultParams struct {
+	GmtCreate       string `json:"gmt_create"`        // 交易创建时间
+	GmtPay          string `json:"gmt_pay"`           // 交易支付时间
+	GmtRefund       string `json:"gmt_refund"`        // 交易退款时间
+	AlipayOrderNo   string `json:"alipay_order_no"`   // 支付宝交易流水号
+	MerchantOrderNo string `json:"merchant_order_no"` // 商户订单号
+	GoodsTitle      string `json:"goods_title"`       // 商品名称
+	TotalAmount     Amount `json:"total_amount"`      // 订单金额
+	RefundAmount    Amount `json:"refund_amount"`     // 退款金额
+	TradeStatus     string `json:"trade_status"`      // 订单状态，如成功、关闭、等待付款
+	OrderType       string `json:"order_type"`        // 业务类型，如交易、退款
+	GoodsMemo       string `json:"goods_memo"`        // 商品备注信息
+	OtherAccount    string `json:"other_account"`     // 对方账户
+	StoreNo         string `json:"store_no"`          // 门店编号
+	StoreName       string `json:"store_name"`        // 门店名称
+	MdiscountAmount Amount `json:"mdiscount_amount"`  // 商家优惠金额
+	DiscountAmount  Amount `json:"discount_amount"`   // 平台优惠金额
+}
+
+///////////////////////////////////////////////////////////////////////////////////////
+
 // FundTransUniTransferRequestParams 单笔转账接口请求参数
 // 文档地址：https://opendocs.alipay.com/open/02byuo
 type FundTransUniTransferRequestParams struct {
-	OutBizNo       string       `json:"out_biz_no"`       // 商家侧唯一订单号，由商家自定义。对于不同转账请求，商家需保证该订单号在自身系统唯一。
-	TransAmount    float64      `json:"trans_amount"`     // 订单总金额，单位为元，不支持千位分隔符，精确到小数点后两位，取值范围[0.1,100000000]。
-	ProductCode    string       `json:"product_code"`     // 销售产品码。单笔无密转账固定为 TRANS_ACCOUNT_NO_PWD。
-	BizScene       string       `json:"biz_scene"`        // 业务场景。单笔无密转账固定为 DIRECT_TRANSFER。
-	OrderTitle     string       `json:"order_title"`      // 转账业务的标题，用于在支付宝用户的账单里显示。
-	PayeeInfo      *Participant `json:"payee_info"`       // 收款方信息
-	Remark         string       `json:"remark,omitempty"` // 业务备注。
-	BusinessParams string       `json:"business_params"`  // 转账业务请求的扩展参数，支持传入的扩展参数如下： payer_show_name_use_alias：是否展示付款方别名，可选，收款方在支付宝账单中可见。枚举支持：* true：展示别名，将展示商家支付宝在商家中心 商户信息 > 商户基本信息 页面配置的 商户别名。* false：不展示别名。默认为 false。
+	OutBizNo       string       `json:"out_biz_no" alipay:"required,max=64"`   // 商家侧唯一订单号，由商家自定义。对于不同转账请求，商家需保证该订单号在自身系统唯一。
+	TransAmount    Amount       `json:"trans_amount" alipay:"required,amount"` // 订单总金额，单位为元，不支持千位分隔符，精确到小数点后两位，取值范围[0.1,100000000]。
+	ProductCode    ProductCode  `json:"product_code" alipay:"required"`        // 销售产品码。单笔无密转账固定为 TRANS_ACCOUNT_NO_PWD。
+	BizScene       string       `json:"biz_scene" alipay:"required"`           // 业务场景。单笔无密转账固定为 DIRECT_TRANSFER。
+	OrderTitle     string       `json:"order_title"`                           // 转账业务的标题，用于在支付宝用户的账单里显示。
+	PayeeInfo      *Participant `json:"payee_info" alipay:"required"`          // 收款方信息
+	Remark         string       `json:"remark,omitempty"`                      // 业务备注。
+	BusinessParams string       `json:"business_params"`                       // 转账业务请求的扩展参数，支持传入的扩展参数如下： payer_show_name_use_alias：是否展示付款方别名，可选，收款方在支付宝账单中可见。枚举支持：* true：展示别名，将展示商家支付宝在商家中心 商户信息 > 商户基本信息 页面配置的 商户别名。* false：不展示别名。默认为 false。
 }
 
 func (f *FundTransUniTransferRequestParams) GetOtherParams() url.Values {
@@ -836,26 +1639,376 @@ func (f *FundTransUniTransferRequestParams) GetNeedEncrypt() bool {
 
 // Participant 收款方信息
 type Participant struct {
-	Identity     string `json:"identity"`       // 参与方的标识 ID。 当 identity_type=ALIPAY_USER_ID 时，填写支付宝用户 UID。示例值：2088123412341234。当 identity_type=ALIPAY_LOGON_ID 时，填写支付宝登录号。示例值：186xxxxxxxx。
-	IdentityType string `json:"identity_type"`  // 参与方的标识类型，目前支持如下枚举： ALIPAY_USER_ID：支付宝会员的用户 ID，可通过 获取会员信息 能力获取。ALIPAY_LOGON_ID：支付宝登录号，支持邮箱和手机号格式。
-	Name         string `json:"name,omitempty"` // 参与方真实姓名。如果非空，将校验收款支付宝账号姓名一致性。 当 identity_type=ALIPAY_LOGON_ID 时，本字段必填。若传入该属性，则在支付宝回单中将会显示这个属性。
+	Identity     string `json:"identity" alipay:"required,max=100"` // 参与方的标识 ID。 当 identity_type=ALIPAY_USER_ID 时，填写支付宝用户 UID。示例值：2088123412341234。当 identity_type=ALIPAY_LOGON_ID 时，填写支付宝登录号。示例值：186xxxxxxxx。
+	IdentityType string `json:"identity_type" alipay:"required"`    // 参与方的标识类型，目前支持如下枚举： ALIPAY_USER_ID：支付宝会员的用户 ID，可通过 获取会员信息 能力获取。ALIPAY_LOGON_ID：支付宝登录号，支持邮箱和手机号格式。
+	Name         string `json:"name,omitempty"`                     // 参与方真实姓名。如果非空，将校验收款支付宝账号姓名一致性。 当 identity_type=ALIPAY_LOGON_ID 时，本字段必填。若传入该属性，则在支付宝回单中将会显示这个属性。
 }
 
 // FundTransUniTransferResponseParams 单笔转账接口响应参数
 type FundTransUniTransferResponseParams struct {
 	Data struct {
 		CommonResParams
-		OutBizNo       string `json:"out_biz_no"`        // 商家订单号
-		OrderId        string `json:"order_id"`          // 支付宝转账订单号
-		PayFundOrderId string `json:"pay_fund_order_id"` // 支付宝支付资金流水号
-		Status         string `json:"status"`            // 转账单据状态
-		TransDate      string `json:"trans_date"`        // 订单支付时间，格式为yyyy-MM-dd HH:mm:ss
+		OutBizNo       string         `json:"out_biz_no"`        // 商家订单号
+		OrderId        string         `json:"order_id"`          // 支付宝转账订单号
+		PayFundOrderId string         `json:"pay_fund_order_id"` // 支付宝支付资金流水号
+		Status         TransferStatus `json:"status"`            // 转账单据状态：SUCCESS（成功）；转账到银行卡时可能为 DEALING（处理中）、FAIL（失败）
+		TransDate      string         `json:"trans_date"`        // 订单支付时间，格式为yyyy-MM-dd HH:mm:ss
 	} `json:"alipay_fund_trans_uni_transfer_response"`
 	Sign string `json:"sign"` // 签名
 }
 
 ///////////////////////////////////////////////////////////////////////////////////////
 
+// FundTransCommonQueryRequestParams 转账业务单据查询接口请求参数
+// 文档地址：https://opendocs.alipay.com/open/02byup
+type FundTransCommonQueryRequestParams struct {
+	OtherRequestParams
+	ProductCode    ProductCode `json:"product_code,omitempty"`                                  // 销售产品码，单笔无密转账到支付宝账户为 TRANS_ACCOUNT_NO_PWD，转账到银行卡为 TRANS_BANKCARD_NO_PWD
+	BizScene       string      `json:"biz_scene,omitempty"`                                     // 业务场景，单笔无密转账固定为 DIRECT_TRANSFER
+	OutBizNo       string      `json:"out_biz_no,omitempty" alipay:"oneof=order,max=64"`        // 商户转账唯一订单号，与支付宝转账单据号不能同时为空
+	OrderId        string      `json:"order_id,omitempty" alipay:"oneof=order,max=32"`          // 支付宝转账单据号，与商户转账唯一订单号不能同时为空
+	PayFundOrderId string      `json:"pay_fund_order_id,omitempty" alipay:"oneof=order,max=32"` // 支付宝支付资金流水号，转账失败时不返回
+}
+
+func (f *FundTransCommonQueryRequestParams) GetOtherParams() url.Values {
+	urlValue := url.Values{}
+	urlValue.Add(AppAuthTokenFiled, f.AppAuthToken)
+	urlValue.Add(ApiMethodNameFiled, "alipay.fund.trans.common.query")
+	bytes, _ := json.Marshal(f)
+	urlValue.Add(BizContentFiled, string(bytes))
+	return urlValue
+}
+
+func (f *FundTransCommonQueryRequestParams) GetNeedEncrypt() bool {
+	return f.NeedEncrypt == true
+}
+
+// FundTransCommonQueryResponseParams 转账业务单据查询接口响应参数
+type FundTransCommonQueryResponseParams struct {
+	Data struct {
+		CommonResParams
+		OrderId        string         `json:"order_id"`          // 支付宝转账单据号
+		PayFundOrderId string         `json:"pay_fund_order_id"` // 支付宝支付资金流水号
+		OutBizNo       string         `json:"out_biz_no"`        // 商户订单号
+		TransAmount    Amount         `json:"trans_amount"`      // 付款金额
+		Status         TransferStatus `json:"status"`            // 转账单据状态：SUCCESS、DEALING、FAIL、REFUND、WAIT_PAY、CLOSED
+		PayDate        string         `json:"pay_date"`          // 支付时间，格式为yyyy-MM-dd HH:mm:ss
+		ArrivalTimeEnd string         `json:"arrival_time_end"`  // 预计到账时间，转账到银行卡时返回
+		OrderFee       Amount         `json:"order_fee"`         // 预计收费金额，转账到银行卡时返回
+		ErrorCode      string         `json:"error_code"`        // 查询到的订单状态为FAIL失败或REFUND退票时，返回错误代码
+		FailReason     string         `json:"fail_reason"`       // 查询到的订单状态为FAIL失败或REFUND退票时，返回具体的原因
+		SubStatus      string         `json:"sub_status"`        // 转账单据状态的子状态
+	} `json:"alipay_fund_trans_common_query_response"`
+	Sign string `json:"sign"` // 签名
+}
+
+///////////////////////////////////////////////////////////////////////////////////////
+
+// FundAccountType 资金账户类型
+type FundAccountType string
+
+const (
+	FundAccountTypeAcctrans    FundAccountType = "ACCTRANS_ACCOUNT"    // 余额户，默认值
+	FundAccountTypeTrusteeship FundAccountType = "TRUSTEESHIP_ACCOUNT" // 托管账户
+)
+
+// FundAccountQueryRequestParams 支付宝资金账户资产查询接口请求参数
+// 文档地址：https://opendocs.alipay.com/open/02byuq
+type FundAccountQueryRequestParams struct {
+	OtherRequestParams
+	AlipayUserId string          `json:"alipay_user_id" alipay:"required,max=32"` // 支付宝会员 id，即商户的 2088 开头的 PID
+	AccountType  FundAccountType `json:"account_type,omitempty"`                  // 查询的账户类型，不传时默认为 ACCTRANS_ACCOUNT
+}
+
+func (f *FundAccountQueryRequestParams) GetOtherParams() url.Values {
+	urlValue := url.Values{}
+	urlValue.Add(AppAuthTokenFiled, f.AppAuthToken)
+	urlValue.Add(ApiMethodNameFiled, "alipay.fund.account.query")
+	bytes, _ := json.Marshal(f)
+	urlValue.Add(BizContentFiled, string(bytes))
+	return urlValue
+}
+
+func (f *FundAccountQueryRequestParams) GetNeedEncrypt() bool {
+	return f.NeedEncrypt == true
+}
+
+// FundAccountQueryResponseParams 支付宝资金账户资产查询接口响应参数
+type FundAccountQueryResponseParams struct {
+	Data struct {
+		CommonResParams
+		AvailableAmount Amount `json:"available_amount"` // 账户可用余额
+		FreezeAmount    Amount `json:"freeze_amount"`    // 实际冻结余额
+	} `json:"alipay_fund_account_query_response"`
+	Sign string `json:"sign"` // 签名
+}
+
+///////////////////////////////////////////////////////////////////////////////////////
+
+// FundAuthOrderAppFreezeRequestParams 线上资金授权冻结接口请求参数，生成调用支付宝App的签名字符串
+// 文档地址：https://opendocs.alipay.com/open/02f912
+type FundAuthOrderAppFreezeRequestParams struct {
+	OtherRequestParams
+	OutOrderNo         string      `json:"out_order_no" alipay:"required,max=64"`   // 商户授权资金订单号，需保证在商户端不重复
+	OutRequestNo       string      `json:"out_request_no" alipay:"required,max=64"` // 商户本次资金操作的请求流水号，用于标识请求流水的唯一性
+	OrderTitle         string      `json:"order_title" alipay:"required,max=100"`   // 业务订单的简单描述，如商品名称等
+	Amount             Amount      `json:"amount" alipay:"required,amount"`         // 需要冻结的金额，单位为元
+	ProductCode        ProductCode `json:"product_code" alipay:"required"`          // 销售产品码，线上预授权固定为 PRE_AUTH_ONLINE
+	PayeeUserId        string      `json:"payee_user_id,omitempty"`                 // 收款方支付宝账号（2088开头的用户ID），不传时默认为商户签约账号
+	PayeeLogonId       string      `json:"payee_logon_id,omitempty"`                // 收款方支付宝账号（邮箱或手机号）
+	TimeoutExpress     string      `json:"timeout_express,omitempty"`               // 该笔订单允许的最晚付款时间，逾期将关闭该笔订单，取值范围：1m～15d
+	ExtraParam         string      `json:"extra_param,omitempty"`                   // 业务扩展参数，JSON格式，如 {"category":"RENT_PHONE","outStoreCode":"charge001"}
+	DepositProductMode string      `json:"deposit_product_mode,omitempty"`          // 免押受理台模式，如 DEPOSIT_ONLY（支付宝预授权免押）
+	EnablePayChannels  string      `json:"enable_pay_channels,omitempty"`           // 可用渠道，JSON数组格式
+}
+
+func (f *FundAuthOrderAppFreezeRequestParams) GetOtherParams() url.Values {
+	urlValue := url.Values{}
+	urlValue.Add(NotifyUrlFiled, f.NotifyUrl)
+	urlValue.Add(AppAuthTokenFiled, f.AppAuthToken)
+	urlValue.Add(ApiMethodNameFiled, "alipay.fund.auth.order.app.freeze")
+	bytes, _ := json.Marshal(f)
+	urlValue.Add(BizContentFiled, string(bytes))
+	return urlValue
+}
+
+func (f *FundAuthOrderAppFreezeRequestParams) GetNeedEncrypt() bool {
+	return f.NeedEncrypt == true
+}
+
+///////////////////////////////////////////////////////////////////////////////////////
+
+// FundAuthOrderVoucherCreateRequestParams 资金授权发码接口请求参数，生成供用户扫码冻结的二维码
+// 文档地址：https://opendocs.alipay.com/open/02fkb9
+type FundAuthOrderVoucherCreateRequestParams struct {
+	OtherRequestParams
+	OutOrderNo     string      `json:"out_order_no" alipay:"required,max=64"`   // 商户授权资金订单号，需保证在商户端不重复
+	OutRequestNo   string      `json:"out_request_no" alipay:"required,max=64"` // 商户本次资金操作的请求流水号
+	OrderTitle     string      `json:"order_title" alipay:"required,max=100"`   // 业务订单的简单描述，如商品名称等
+	Amount         Amount      `json:"amount" alipay:"required,amount"`         // 需要冻结的金额，单位为元
+	ProductCode    ProductCode `json:"product_code" alipay:"required"`          // 销售产品码，线下预授权固定为 PRE_AUTH
+	PayeeUserId    string      `json:"payee_user_id,omitempty"`                 // 收款方支付宝账号（2088开头的用户ID）
+	PayeeLogonId   string      `json:"payee_logon_id,omitempty"`                // 收款方支付宝账号（邮箱或手机号）
+	PayTimeout     string      `json:"pay_timeout,omitempty"`                   // 用户扫码后允许的最晚付款时间，取值范围：1m～15d
+	ExtraParam     string      `json:"extra_param,omitempty"`                   // 业务扩展参数，JSON格式
+	TransCurrency  Currency    `json:"trans_currency,omitempty"`                // 标价币种，境外预授权时使用。指定时 amount 以该币种的最小单位保存（可使用 ParseAmountIn 解析），按该币种的小数位数序列化
+	SettleCurrency Currency    `json:"settle_currency,omitempty"`               // 结算币种，境外预授权时使用
+}
+
+func (f *FundAuthOrderVoucherCreateRequestParams) GetOtherParams() url.Values {
+	urlValue := url.Values{}
+	urlValue.Add(NotifyUrlFiled, f.NotifyUrl)
+	urlValue.Add(AppAuthTokenFiled, f.AppAuthToken)
+	urlValue.Add(ApiMethodNameFiled, "alipay.fund.auth.order.voucher.create")
+	bytes, _ := json.Marshal(f)
+	urlValue.Add(BizContentFiled, string(bytes))
+	return urlValue
+}
+
+func (f *FundAuthOrderVoucherCreateRequestParams) GetNeedEncrypt() bool {
+	return f.NeedEncrypt == true
+}
+
+// MarshalJSON 指定标价币种时按币种的小数位数序列化金额，如JPY为"1000"，KWD为"1.234"
+func (f FundAuthOrderVoucherCreateRequestParams) MarshalJSON() ([]byte, error) {
+	type params FundAuthOrderVoucherCreateRequestParams
+	return json.Marshal(struct {
+		params
+		Amount CurrencyAmount `json:"amount"`
+	}{params(f), f.Amount.CurrencyAmount(f.TransCurrency)})
+}
+
+// FundAuthOrderVoucherCreateResponseParams 资金授权发码接口响应参数
+type FundAuthOrderVoucherCreateResponseParams struct {
+	Data struct {
+		CommonResParams
+		OutOrderNo   string `json:"out_order_no"`   // 商户授权资金订单号
+		OutRequestNo string `json:"out_request_no"` // 商户本次资金操作的请求流水号
+		CodeType     string `json:"code_type"`      // 码类型，如 qrCode（二维码）
+		CodeValue    string `json:"code_value"`     // 当前发码请求生成的二维码码串，商户可以用此码串生成二维码图片
+		CodeUrl      string `json:"code_url"`       // 二维码图片的URL地址
+	} `json:"alipay_fund_auth_order_voucher_create_response"`
+	Sign string `json:"sign"` // 签名
+}
+
+///////////////////////////////////////////////////////////////////////////////////////
+
+// FundAuthOrderUnfreezeRequestParams 资金授权解冻接口请求参数
+// 文档地址：https://opendocs.alipay.com/open/02fkbc
+type FundAuthOrderUnfreezeRequestParams struct {
+	OtherRequestParams
+	AuthNo       string `json:"auth_no" alipay:"required,max=64"`        // 支付宝资金授权订单号
+	OutRequestNo string `json:"out_request_no" alipay:"required,max=64"` // 商户本次资金操作的请求流水号，同一商户每次不同的资金操作请求，商户请求流水号不要重复
+	Amount       Amount `json:"amount" alipay:"required,amount"`         // 本次操作解冻的金额，单位为元
+	Remark       string `json:"remark" alipay:"required,max=100"`        // 商户对本次解冻操作的附言描述
+	ExtraParam   string `json:"extra_param,omitempty"`                   // 解冻扩展信息，JSON格式，如信用服务完结时传 {"unfreezeBizInfo":"{\"bizComplete\":\"true\"}"}
+}
+
+func (f *FundAuthOrderUnfreezeRequestParams) GetOtherParams() url.Values {
+	urlValue := url.Values{}
+	urlValue.Add(NotifyUrlFiled, f.NotifyUrl)
+	urlValue.Add(AppAuthTokenFiled, f.AppAuthToken)
+	urlValue.Add(ApiMethodNameFiled, "alipay.fund.auth.order.unfreeze")
+	bytes, _ := json.Marshal(f)
+	urlValue.Add(BizContentFiled, string(bytes))
+	return urlValue
+}
+
+func (f *FundAuthOrderUnfreezeRequestParams) GetNeedEncrypt() bool {
+	return f.NeedEncrypt == true
+}
+
+// FundAuthOrderUnfreezeResponseParams 资金授权解冻接口响应参数
+type FundAuthOrderUnfreezeResponseParams struct {
+	Data struct {
+		CommonResParams
+		AuthNo       string                  `json:"auth_no"`        // 支付宝资金授权订单号
+		OutOrderNo   string                  `json:"out_order_no"`   // 商户授权资金订单号
+		OperationId  string                  `json:"operation_id"`   // 支付宝资金操作流水号
+		OutRequestNo string                  `json:"out_request_no"` // 商户本次资金操作的请求流水号
+		Amount       Amount                  `json:"amount"`         // 本次解冻操作的金额
+		Status       FundAuthOperationStatus `json:"status"`         // 资金操作流水的状态：INIT（初始）、SUCCESS（成功）、CLOSED（关闭）
+		GmtTrans     string                  `json:"gmt_trans"`      // 授权资金解冻成功时间，格式为yyyy-MM-dd HH:mm:ss
+		CreditAmount Amount                  `json:"credit_amount"`  // 本次解冻操作中信用解冻金额
+		FundAmount   Amount                  `json:"fund_amount"`    // 本次解冻操作中自有资金解冻金额
+	} `json:"alipay_fund_auth_order_unfreeze_response"`
+	Sign string `json:"sign"` // 签名
+}
+
+///////////////////////////////////////////////////////////////////////////////////////
+
+// FundAuthOperationDetailQueryRequestParams 资金授权操作查询接口请求参数
+// 文档地址：https://opendocs.alipay.com/open/02fkbd
+type FundAuthOperationDetailQueryRequestParams struct {
+	OtherRequestParams
+	AuthNo       string `json:"auth_no,omitempty" alipay:"oneof=order,max=64"`            // 支付宝资金授权订单号，与商户授权资金订单号不能同时为空
+	OutOrderNo   string `json:"out_order_no,omitempty" alipay:"oneof=order,max=64"`       // 商户授权资金订单号，与支付宝资金授权订单号不能同时为空
+	OperationId  string `json:"operation_id,omitempty" alipay:"oneof=operation,max=64"`   // 支付宝资金操作流水号，与商户请求流水号不能同时为空
+	OutRequestNo string `json:"out_request_no,omitempty" alipay:"oneof=operation,max=64"` // 商户资金操作的请求流水号，与支付宝资金操作流水号不能同时为空
+}
+
+func (f *FundAuthOperationDetailQueryRequestParams) GetOtherParams() url.Values {
+	urlValue := url.Values{}
+	urlValue.Add(AppAuthTokenFiled, f.AppAuthToken)
+	urlValue.Add(ApiMethodNameFiled, "alipay.fund.auth.operation.detail.query")
+	bytes, _ := json.Marshal(f)
+	urlValue.Add(BizContentFiled, string(bytes))
+	return urlValue
+}
+
+func (f *FundAuthOperationDetailQueryRequestParams) GetNeedEncrypt() bool {
+	return f.NeedEncrypt == true
+}
+
+// FundAuthOperationDetailQueryResponseParams 资金授权操作查询接口响应参数
+type FundAuthOperationDetailQueryResponseParams struct {
+	Data struct {
+		CommonResParams
+		AuthNo                  string                  `json:"auth_no"`                    // 支付宝资金授权订单号
+		OutOrderNo              string                  `json:"out_order_no"`               // 商户授权资金订单号
+		OrderStatus             FundAuthOrderStatus     `json:"order_status"`               // 授权单状态：INIT（初始）、AUTHORIZED（已授权）、FINISH（完成）、CLOSED（关闭）
+		TotalFreezeAmount       Amount                  `json:"total_freeze_amount"`        // 订单累计的冻结金额
+		RestAmount              Amount                  `json:"rest_amount"`                // 订单总共剩余的冻结金额
+		TotalPayAmount          Amount                  `json:"total_pay_amount"`           // 订单累计用于支付的金额
+		OrderTitle              string                  `json:"order_title"`                // 业务订单的简单描述
+		OperationId             string                  `json:"operation_id"`               // 支付宝资金操作流水号
+		OutRequestNo            string                  `json:"out_request_no"`             // 商户资金操作的请求流水号
+		Amount                  Amount                  `json:"amount"`                     // 本次操作的金额
+		OperationType           FundAuthOperationType   `json:"operation_type"`             // 操作类型：FREEZE（冻结）、UNFREEZE（解冻）、PAY（支付）
+		Status                  FundAuthOperationStatus `json:"status"`                     // 资金操作流水的状态：INIT（初始）、SUCCESS（成功）、CLOSED（关闭）
+		Remark                  string                  `json:"remark"`                     // 商户对本次操作的附言描述
+		GmtCreate               string                  `json:"gmt_create"`                 // 资金授权单据操作流水创建时间
+		GmtTrans                string                  `json:"gmt_trans"`                  // 支付宝账务处理成功时间
+		PayerLogonId            string                  `json:"payer_logon_id"`             // 付款方支付宝账号登录号
+		PayerUserId             string                  `json:"payer_user_id"`              // 付款方支付宝账号UID
+		ExtraParam              string                  `json:"extra_param"`                // 商户请求创建预授权订单时传入的扩展参数
+		CreditAmount            Amount                  `json:"credit_amount"`              // 本次操作中信用金额
+		FundAmount              Amount                  `json:"fund_amount"`                // 本次操作中自有资金金额
+		TotalFreezeCreditAmount Amount                  `json:"total_freeze_credit_amount"` // 累计冻结信用金额
+		TotalFreezeFundAmount   Amount                  `json:"total_freeze_fund_amount"`   // 累计冻结自有资金金额
+		TotalPayCreditAmount    Amount                  `json:"total_pay_credit_amount"`    // 累计信用金额支付金额
+		TotalPayFundAmount      Amount                  `json:"total_pay_fund_amount"`      // 累计自有资金支付金额
+		RestCreditAmount        Amount                  `json:"rest_credit_amount"`         // 剩余冻结信用金额
+		RestFundAmount          Amount                  `json:"rest_fund_amount"`           // 剩余冻结自有资金金额
+	} `json:"alipay_fund_auth_operation_detail_query_response"`
+	Sign string `json:"sign"` // 签名
+}
+
+///////////////////////////////////////////////////////////////////////////////////////
+
+// FundAuthOperationCancelRequestParams 资金授权撤销接口请求参数，用于冻结、解冻结果未知时撤销操作
+// 文档地址：https://opendocs.alipay.com/open/02fkbb
+type FundAuthOperationCancelRequestParams struct {
+	OtherRequestParams
+	AuthNo       string `json:"auth_no,omitempty" alipay:"oneof=order,max=64"`            // 支付宝资金授权订单号，与商户授权资金订单号不能同时为空
+	OutOrderNo   string `json:"out_order_no,omitempty" alipay:"oneof=order,max=64"`       // 商户授权资金订单号，与支付宝资金授权订单号不能同时为空
+	OperationId  string `json:"operation_id,omitempty" alipay:"oneof=operation,max=64"`   // 支付宝资金操作流水号，与商户请求流水号不能同时为空
+	OutRequestNo string `json:"out_request_no,omitempty" alipay:"oneof=operation,max=64"` // 商户资金操作的请求流水号，与支付宝资金操作流水号不能同时为空
+	Remark       string `json:"remark" alipay:"required,max=100"`                         // 商户对本次撤销操作的附言描述
+}
+
+func (f *FundAuthOperationCancelRequestParams) GetOtherParams() url.Values {
+	urlValue := url.Values{}
+	urlValue.Add(NotifyUrlFiled, f.NotifyUrl)
+	urlValue.Add(AppAuthTokenFiled, f.AppAuthToken)
+	urlValue.Add(ApiMethodNameFiled, "alipay.fund.auth.operation.cancel")
+	bytes, _ := json.Marshal(f)
+	urlValue.Add(BizContentFiled, string(bytes))
+	return urlValue
+}
+
+func (f *FundAuthOperationCancelRequestParams) GetNeedEncrypt() bool {
+	return f.NeedEncrypt == true
+}
+
+// FundAuthOperationCancelResponseParams 资金授权撤销接口响应参数
+type FundAuthOperationCancelResponseParams struct {
+	Data struct {
+		CommonResParams
+		AuthNo       string `json:"auth_no"`        // 支付宝资金授权订单号
+		OutOrderNo   string `json:"out_order_no"`   // 商户授权资金订单号
+		OperationId  string `json:"operation_id"`   // 支付宝资金操作流水号
+		OutRequestNo string `json:"out_request_no"` // 商户资金操作的请求流水号
+		Action       string `json:"action"`         // 本次撤销触发的资金动作：close（关闭冻结明细，无资金解冻）、unfreeze（产生了资金解冻）
+	} `json:"alipay_fund_auth_operation_cancel_response"`
+	Sign string `json:"sign"` // 签名
+}
+
+///////////////////////////////////////////////////////////////////////////////////////
+
+// FundAuthTradePayRequestParams 预授权转支付请求参数，使用 alipay.trade.pay 将冻结的资金转为支付
+// 文档地址：https://opendocs.alipay.com/open/02fkb8
+type FundAuthTradePayRequestParams struct {
+	OtherRequestParams
+	OutTradeNo      string      `json:"out_trade_no" alipay:"required,max=64,pattern=out_trade_no"` // 商户订单号，需保证在商户端不重复，仅支持字母、数字、下划线
+	TotalAmount     Amount      `json:"total_amount" alipay:"required,amount"`                      // 订单总金额，单位为元，不能超过冻结的剩余金额
+	Subject         string      `json:"subject" alipay:"required,max=256"`                          // 订单标题
+	ProductCode     ProductCode `json:"product_code" alipay:"required"`                             // 销售产品码，线上预授权为 PRE_AUTH_ONLINE，线下预授权为 PRE_AUTH
+	AuthNo          string      `json:"auth_no" alipay:"required,max=64"`                           // 支付宝资金授权订单号
+	AuthConfirmMode string      `json:"auth_confirm_mode,omitempty"`                                // 预授权确认模式：COMPLETE（转支付完成后自动解冻剩余冻结金额）、NOT_COMPLETE（不自动解冻，默认值）
+	BuyerId         string      `json:"buyer_id,omitempty"`                                         // 买家支付宝用户ID，即冻结时的付款方
+	SellerId        string      `json:"seller_id,omitempty"`                                        // 卖家支付宝用户ID，即冻结时的收款方
+	Body            string      `json:"body,omitempty"`                                             // 订单附加信息
+	StoreId         string      `json:"store_id,omitempty"`                                         // 商户门店编号
+	TerminalId      string      `json:"terminal_id,omitempty"`                                      // 商户机具终端编号
+}
+
+func (f *FundAuthTradePayRequestParams) GetOtherParams() url.Values {
+	urlValue := url.Values{}
+	urlValue.Add(NotifyUrlFiled, f.NotifyUrl)
+	urlValue.Add(AppAuthTokenFiled, f.AppAuthToken)
+	urlValue.Add(ApiMethodNameFiled, "alipay.trade.pay")
+	bytes, _ := json.Marshal(f)
+	urlValue.Add(BizContentFiled, string(bytes))
+	return urlValue
+}
+
+func (f *FundAuthTradePayRequestParams) GetNeedEncrypt() bool {
+	return f.NeedEncrypt == true
+}
+
+///////////////////////////////////////////////////////////////////////////////////////
+
 // AppAliPayCertDownloadRequestParams 应用支付宝公钥证书下载请求参数
 // 文档地址：https://opendocs.alipay.com/apis/api_9/alipay.open.app.alipaycert.download
 type AppAliPayCertDownloadRequestParams struct {
@@ -1097,38 +2250,186 @@ type AppAliPayCertDownloadResponseParams struct {
 // TradeNotificationParams 异步通知响应参数
 // 文档：https://opendocs.alipay.com/open/203/105286
 type TradeNotificationParams struct {
-	AuthAppId           string `json:"auth_app_id"`           // App Id
-	NotifyTime          string `json:"notify_time"`           // 通知时间
-	NotifyType          string `json:"notify_type"`           // 通知类型
-	NotifyId            string `json:"notify_id"`             // 通知校验ID
-	AppId               string `json:"app_id"`                // 开发者的app_id
-	Charset             string `json:"charset"`               // 编码格式
-	Version             string `json:"version"`               // 接口版本
-	SignType            string `json:"sign_type"`             // 签名类型
-	Sign                string `json:"sign"`                  // 签名
-	TradeNo             string `json:"trade_no"`              // 支付宝交易号
-	OutTradeNo          string `json:"out_trade_no"`          // 商户订单号
-	OutBizNo            string `json:"out_biz_no"`            // 商户业务号
-	BuyerId             string `json:"buyer_id"`              // 买家支付宝用户号
-	BuyerLogonId        string `json:"buyer_logon_id"`        // 买家支付宝账号
-	SellerId            string `json:"seller_id"`             // 卖家支付宝用户号
-	SellerEmail         string `json:"seller_email"`          // 卖家支付宝账号
-	TradeStatus         string `json:"trade_status"`          // 交易状态
-	TotalAmount         string `json:"total_amount"`          // 订单金额
-	ReceiptAmount       string `json:"receipt_amount"`        // 实收金额
-	InvoiceAmount       string `json:"invoice_amount"`        // 开票金额
-	BuyerPayAmount      string `json:"buyer_pay_amount"`      // 付款金额
-	PointAmount         string `json:"point_amount"`          // 集分宝金额
-	RefundFee           string `json:"refund_fee"`            // 总退款金额
-	Subject             string `json:"subject"`               // 商品的标题/交易标题/订单标题/订单关键字等，是请求时对应的参数，原样通知回来。
-	Body                string `json:"body"`                  // 商品描述
-	GmtCreate           string `json:"gmt_create"`            // 交易创建时间
-	GmtPayment          string `json:"gmt_payment"`           // 交易付款时间
-	GmtRefund           string `json:"gmt_refund"`            // 交易退款时间
-	GmtClose            string `json:"gmt_close"`             // 交易结束时间
-	FundBillList        string `json:"fund_bill_list"`        // 支付金额信息
-	PassbackParams      string `json:"passback_params"`       // 回传参数
-	VoucherDetailList   string `json:"voucher_detail_list"`   // 优惠券信息
-	AgreementNo         string `json:"agreement_no"`          //支付宝签约号
-	ExternalAgreementNo string `json:"external_agreement_no"` // 商户自定义签约号
+	AuthAppId           string         `json:"auth_app_id"`           // App Id
+	NotifyTime          string         `json:"notify_time"`           // 通知时间
+	NotifyType          string         `json:"notify_type"`           // 通知类型
+	NotifyId            string         `json:"notify_id"`             // 通知校验ID
+	AppId               string         `json:"app_id"`                // 开发者的app_id
+	Charset             string         `json:"charset"`               // 编码格式
+	Version             string         `json:"version"`               // 接口版本
+	SignType            string         `json:"sign_type"`             // 签名类型
+	Sign                string         `json:"sign"`                  // 签名
+	TradeNo             string         `json:"trade_no"`              // 支付宝交易号
+	OutTradeNo          string         `json:"out_trade_no"`          // 商户订单号
+	OutBizNo            string         `json:"out_biz_no"`            // 商户业务号
+	BuyerId             string         `json:"buyer_id"`              // 买家支付宝用户号
+	BuyerLogonId        string         `json:"buyer_logon_id"`        // 买家支付宝账号
+	SellerId            string         `json:"seller_id"`             // 卖家支付宝用户号
+	SellerEmail         string         `json:"seller_email"`          // 卖家支付宝账号
+	TradeStatus         TradeStatus    `json:"trade_status"`          // 交易状态
+	TotalAmount         CurrencyAmount `json:"total_amount"`          // 订单金额，标价币种（trans_currency，为空时为人民币）的金额，精度为该币种的小数位数
+	ReceiptAmount       Amount         `json:"receipt_amount"`        // 实收金额
+	InvoiceAmount       Amount         `json:"invoice_amount"`        // 开票金额
+	BuyerPayAmount      Amount         `json:"buyer_pay_amount"`      // 付款金额
+	PointAmount         Amount         `json:"point_amount"`          // 集分宝金额
+	RefundFee           Amount         `json:"refund_fee"`            // 总退款金额
+	Subject             string         `json:"subject"`               // 商品的标题/交易标题/订单标题/订单关键字等，是请求时对应的参数，原样通知回来。
+	Body                string         `json:"body"`                  // 商品描述
+	GmtCreate           string         `json:"gmt_create"`            // 交易创建时间
+	GmtPayment          string         `json:"gmt_payment"`           // 交易付款时间
+	GmtRefund           string         `json:"gmt_refund"`            // 交易退款时间
+	GmtClose            string         `json:"gmt_close"`             // 交易结束时间
+	FundBillList        string         `json:"fund_bill_list"`        // 支付金额信息
+	PassbackParams      string         `json:"passback_params"`       // 回传参数
+	VoucherDetailList   string         `json:"voucher_detail_list"`   // 优惠券信息
+	AgreementNo         string         `json:"agreement_no"`          //支付宝签约号
+	ExternalAgreementNo string         `json:"external_agreement_no"` // 商户自定义签约号
+	TransCurrency       Currency       `json:"trans_currency"`        // 标价币种，跨境交易时返回
+	SettleCurrency      Currency       `json:"settle_currency"`       // 结算币种，跨境交易时返回
+	SettleAmount        CurrencyAmount `json:"settle_amount"`         // 结算币种金额，精度为结算币种的小数位数
+	PayCurrency         Currency       `json:"pay_currency"`          // 支付币种，跨境交易时返回
+	PayAmount           CurrencyAmount `json:"pay_amount"`            // 支付币种金额，精度为支付币种的小数位数
+	ForexRate           string         `json:"forex_rate"`            // 汇率，跨境交易时返回
+}
+
+// IsRefund 是否为退款产生的交易通知，退款通知中 out_biz_no 为退款请求号，refund_fee 为总退款金额
+func (t TradeNotificationParams) IsRefund() bool {
+	return t.GmtRefund != "" || t.OutBizNo != "" && !t.RefundFee.IsZero()
+}
+
+// MsgNotificationParams 消息服务异步通知的公共参数，业务参数在 biz_content 中
+type MsgNotificationParams struct {
+	NotifyId     string `json:"notify_id"`     // 通知ID
+	UtcTimestamp string `json:"utc_timestamp"` // 消息发送时的服务端时间，毫秒时间戳
+	MsgMethod    string `json:"msg_method"`    // 消息接口名称
+	AppId        string `json:"app_id"`        // 开发者的app_id
+	Version      string `json:"version"`       // 版本号
+	BizContent   string `json:"biz_content"`   // 消息报文
+	Charset      string `json:"charset"`       // 编码格式
+	SignType     string `json:"sign_type"`     // 签名类型
+	Sign         string `json:"sign"`          // 签名
+}
+
+// RefundDepositBackNotificationParams 退款银行卡冲退完成通知（alipay.trade.refund.depositback.completed）
+type RefundDepositBackNotificationParams struct {
+	MsgNotificationParams
+	Content RefundDepositBackContent `json:"-"` // 解析后的 biz_content
+}
+
+// RefundDepositBackContent 退款银行卡冲退完成通知的业务参数
+type RefundDepositBackContent struct {
+	TradeNo            string `json:"trade_no"`              // 支付宝交易号
+	OutTradeNo         string `json:"out_trade_no"`          // 商户订单号
+	OutRequestNo       string `json:"out_request_no"`        // 退款请求号
+	DbackStatus        string `json:"dback_status"`          // 银行卡冲退状态。S-成功，F-失败。银行卡冲退失败，资金自动转入用户支付宝余额。
+	DbackAmount        Amount `json:"dback_amount"`          // 银行卡冲退金额
+	BankAckTime        string `json:"bank_ack_time"`         // 银行响应时间，格式为yyyy-MM-dd HH:mm:ss
+	EstBankReceiptTime string `json:"est_bank_receipt_time"` // 预估银行到账时间，格式为yyyy-MM-dd HH:mm:ss
+}
+
+// IsSuccess 银行卡冲退是否成功
+func (r RefundDepositBackContent) IsSuccess() bool {
+	return r.DbackStatus == "S"
+}
+
+// FundTransOrderChangedNotificationParams 资金单据状态变更通知（alipay.fund.trans.order.changed）
+type FundTransOrderChangedNotificationParams struct {
+	MsgNotificationParams
+	Content FundTransOrderChangedContent `json:"-"` // 解析后的 biz_content
+}
+
+// FundTransOrderChangedContent 资金单据状态变更通知的业务参数
+type FundTransOrderChangedContent struct {
+	ActionType      string         `json:"action_type"`       // 通知类型，如 FINISH（单据完结）、REFUND（退票）
+	BizScene        string         `json:"biz_scene"`         // 业务场景
+	OriginInterface string         `json:"origin_interface"`  // 发起转账的接口，如 alipay.fund.trans.uni.transfer
+	ProductCode     ProductCode    `json:"product_code"`      // 销售产品码
+	OutBizNo        string         `json:"out_biz_no"`        // 商户订单号
+	OrderId         string         `json:"order_id"`          // 支付宝转账单据号
+	PayFundOrderId  string         `json:"pay_fund_order_id"` // 支付宝支付资金流水号
+	Status          TransferStatus `json:"status"`            // 转账单据状态：SUCCESS、FAIL、REFUND、CLOSED等
+	TransAmount     Amount         `json:"trans_amount"`      // 转账金额
+	PayDate         string         `json:"pay_date"`          // 支付时间，格式为yyyy-MM-dd HH:mm:ss
+	ErrorCode       string         `json:"error_code"`        // 失败或退票时的错误代码
+	FailReason      string         `json:"fail_reason"`       // 失败或退票时的具体原因
+}
+
+// TradeOrderSettleNotificationParams 交易分账结果通知（alipay.trade.order.settle.notify），异步分账完成时发送
+type TradeOrderSettleNotificationParams struct {
+	MsgNotificationParams
+	Content TradeOrderSettleContent `json:"-"` // 解析后的 biz_content
+}
+
+// TradeOrderSettleContent 交易分账结果通知的业务参数
+type TradeOrderSettleContent struct {
+	TradeNo           string                 `json:"trade_no"`            // 支付宝交易号
+	SettleNo          string                 `json:"settle_no"`           // 支付宝分账单号
+	OutRequestNo      string                 `json:"out_request_no"`      // 结算请求流水号
+	OperationDt       string                 `json:"operation_dt"`        // 分账受理时间
+	RoyaltyDetailList []*SettleRoyaltyDetail `json:"royalty_detail_list"` // 分账明细
+}
+
+// UserAgreementNotificationParams 个人代扣协议签约、解约异步通知参数（notify_type 为 dut_user_sign 或 dut_user_unsign）
+type UserAgreementNotificationParams struct {
+	NotifyId            string          `json:"notify_id"`             // 通知校验ID
+	NotifyTime          string          `json:"notify_time"`           // 通知的发送时间，格式为yyyy-MM-dd HH:mm:ss
+	NotifyType          string          `json:"notify_type"`           // 通知类型：dut_user_sign（签约）、dut_user_unsign（解约）
+	AgreementNo         string          `json:"agreement_no"`          // 支付宝系统中用以唯一标识用户签约记录的编号
+	Status              AgreementStatus `json:"status"`                // 协议当前状态，签约成功为 NORMAL，解约为 UNSIGN
+	PersonalProductCode string          `json:"personal_product_code"` // 协议产品码
+	SignScene           string          `json:"sign_scene"`            // 签约协议的场景
+	SignTime            string          `json:"sign_time"`             // 协议签约时间
+	ValidTime           string          `json:"valid_time"`            // 协议生效时间
+	InvalidTime         string          `json:"invalid_time"`          // 协议失效时间
+	UnsignTime          string          `json:"unsign_time"`           // 协议解约时间，解约通知时返回
+	AlipayUserId        string          `json:"alipay_user_id"`        // 用户的支付宝唯一用户号
+	AlipayLogonId       string          `json:"alipay_logon_id"`       // 用户的支付宝登录账号
+	ExternalAgreementNo string          `json:"external_agreement_no"` // 商户签约号
+	ExternalLogonId     string          `json:"external_logon_id"`     // 用户在商户网站的登录账号
+	ZmOpenId            string          `json:"zm_open_id"`            // 用户在芝麻信用的唯一标识
+	PartnerId           string          `json:"partner_id"`            // 签约商户的支付宝用户号
+	MerchantAppId       string          `json:"merchant_app_id"`       // 商户的应用ID
+	AuthAppId           string          `json:"auth_app_id"`           // 授权方的app_id
+	AppId               string          `json:"app_id"`                // 开发者的app_id
+	Charset             string          `json:"charset"`               // 编码格式
+	Version             string          `json:"version"`               // 接口版本
+	SignType            string          `json:"sign_type"`             // 签名类型
+	Sign                string          `json:"sign"`                  // 签名
+}
+
+// IsUnsign 是否为解约通知
+func (u UserAgreementNotificationParams) IsUnsign() bool {
+	return u.NotifyType == "dut_user_unsign"
+}
+
+// FundAuthNotificationParams 资金授权冻结、解冻异步通知参数（notify_type 为 fund_auth_freeze 或 fund_auth_unfreeze）
+type FundAuthNotificationParams struct {
+	NotifyId            string                  `json:"notify_id"`             // 通知校验ID
+	NotifyTime          string                  `json:"notify_time"`           // 通知的发送时间，格式为yyyy-MM-dd HH:mm:ss
+	NotifyType          string                  `json:"notify_type"`           // 通知类型：fund_auth_freeze（冻结）、fund_auth_unfreeze（解冻）
+	AuthNo              string                  `json:"auth_no"`               // 支付宝资金授权订单号
+	OutOrderNo          string                  `json:"out_order_no"`          // 商户授权资金订单号
+	OperationId         string                  `json:"operation_id"`          // 支付宝资金操作流水号
+	OutRequestNo        string                  `json:"out_request_no"`        // 商户资金操作的请求流水号
+	OperationType       FundAuthOperationType   `json:"operation_type"`        // 操作类型：FREEZE（冻结）、UNFREEZE（解冻）
+	Amount              Amount                  `json:"amount"`                // 本次操作的金额
+	Status              FundAuthOperationStatus `json:"status"`                // 资金操作流水的状态
+	GmtCreate           string                  `json:"gmt_create"`            // 操作创建时间
+	GmtTrans            string                  `json:"gmt_trans"`             // 支付宝账务处理成功时间
+	PayerLogonId        string                  `json:"payer_logon_id"`        // 付款方支付宝账号登录号
+	PayerUserId         string                  `json:"payer_user_id"`         // 付款方支付宝账号UID
+	PayeeLogonId        string                  `json:"payee_logon_id"`        // 收款方支付宝账号登录号
+	PayeeUserId         string                  `json:"payee_user_id"`         // 收款方支付宝账号UID
+	TotalFreezeAmount   Amount                  `json:"total_freeze_amount"`   // 订单累计的冻结金额
+	TotalUnfreezeAmount Amount                  `json:"total_unfreeze_amount"` // 订单累计的解冻金额
+	TotalPayAmount      Amount                  `json:"total_pay_amount"`      // 订单累计用于支付的金额
+	RestAmount          Amount                  `json:"rest_amount"`           // 订单总共剩余的冻结金额
+	CreditAmount        Amount                  `json:"credit_amount"`         // 本次操作中信用金额
+	FundAmount          Amount                  `json:"fund_amount"`           // 本次操作中自有资金金额
+	PreAuthType         string                  `json:"pre_auth_type"`         // 预授权类型，信用预授权时为 CREDIT_AUTH
+	AppId               string                  `json:"app_id"`                // 开发者的app_id
+	Charset             string                  `json:"charset"`               // 编码格式
+	Version             string                  `json:"version"`               // 接口版本
+	SignType            string                  `json:"sign_type"`             // 签名类型
+	Sign                string                  `json:"sign"`                  // 签名
 }
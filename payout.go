@@ -0,0 +1,500 @@
+package alipay
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+var (
+	payoutNotFoundErr       = errors.New("the payout record does not exist in the payout store")
+	payoutExistsErr         = errors.New("the payout record already exists in the payout store")
+	payoutBatchNoIsEmptyErr = errors.New("the payout batch no is empty")
+)
+
+const (
+	// DefaultPayoutConcurrency 批量付款的默认并发数
+	DefaultPayoutConcurrency = 5
+	// DefaultPayoutMaxAttempts 单笔付款中转账及确认的默认最大尝试次数
+	DefaultPayoutMaxAttempts = 3
+	// PayoutBizSceneDirectTransfer 单笔无密转账的业务场景
+	PayoutBizSceneDirectTransfer = "DIRECT_TRANSFER"
+)
+
+// PayoutRecordStatus 付款记录状态
+type PayoutRecordStatus string
+
+const (
+	PayoutRecordPending    PayoutRecordStatus = "PENDING"    // 未发起转账或转账结果未确认，再次运行批次时会使用相同的 out_biz_no 重试
+	PayoutRecordProcessing PayoutRecordStatus = "PROCESSING" // 支付宝处理中（如转账到银行卡 DEALING），再次运行批次时只查询不重新转账
+	PayoutRecordSuccess    PayoutRecordStatus = "SUCCESS"    // 付款成功
+	PayoutRecordFailed     PayoutRecordStatus = "FAILED"     // 付款失败，如收款账号不存在、余额不足、退票
+)
+
+// IsTerminal 是否为最终状态
+func (s PayoutRecordStatus) IsTerminal() bool {
+	return s == PayoutRecordSuccess || s == PayoutRecordFailed
+}
+
+// PayoutItem 付款明细
+type PayoutItem struct {
+	OutBizNo     string       // 商户转账唯一订单号，必填，批次内及不同批次间均不能重复
+	TransAmount  Amount       // 转账金额
+	PayeeInfo    *Participant // 收款方信息
+	OrderTitle   string       // 转账业务的标题，用于在支付宝用户的账单里显示
+	Remark       string       // 业务备注
+	BusinessInfo string       // 转账业务请求的扩展参数，即 business_params
+}
+
+// PayoutRecord 付款记录，保存每笔付款的进度，进程中断后再次运行批次时据此继续
+type PayoutRecord struct {
+	BatchNo        string             `json:"batch_no"`          // 批次号
+	OutBizNo       string             `json:"out_biz_no"`        // 商户转账唯一订单号
+	TransAmount    Amount             `json:"trans_amount"`      // 转账金额
+	PayeeIdentity  string             `json:"payee_identity"`    // 收款方标识
+	Status         PayoutRecordStatus `json:"status"`            // 付款记录状态
+	TransferStatus TransferStatus     `json:"transfer_status"`   // 支付宝返回的转账单据状态
+	OrderId        string             `json:"order_id"`          // 支付宝转账单据号
+	PayFundOrderId string             `json:"pay_fund_order_id"` // 支付宝支付资金流水号
+	TransDate      string             `json:"trans_date"`        // 支付时间
+	FailReason     string             `json:"fail_reason"`       // 失败的原因或最后一次结果未知的原因
+	Attempts       int                `json:"attempts"`          // 已发起转账的次数
+	CreatedAt      time.Time          `json:"created_at"`        // 创建时间
+	UpdatedAt      time.Time          `json:"updated_at"`        // 更新时间
+}
+
+// PayoutStore 付款记录存储，以 out_biz_no 为唯一键，需在发起转账前持久化
+type PayoutStore interface {
+	// Get 获取付款记录，不存在时返回 IsPayoutNotFound 可判断的错误
+	Get(outBizNo string) (record *PayoutRecord, err error)
+	// Create 新增付款记录，记录已存在时返回 IsPayoutExists 可判断的错误，需保证并发调用时只有一个成功
+	Create(record *PayoutRecord) error
+	// Update 更新付款记录
+	Update(record *PayoutRecord) error
+}
+
+// IsPayoutNotFound 判断错误是否为付款记录不存在
+func IsPayoutNotFound(err error) bool {
+	return errors.Is(err, payoutNotFoundErr)
+}
+
+// IsPayoutExists 判断错误是否为付款记录已存在
+func IsPayoutExists(err error) bool {
+	return errors.Is(err, payoutExistsErr)
+}
+
+// MemoryPayoutStore 内存付款记录存储，进程重启后失效，仅用于测试或单机场景
+type MemoryPayoutStore struct {
+	mutex   sync.RWMutex
+	records map[string]PayoutRecord
+}
+
+// NewMemoryPayoutStore 初始化内存付款记录存储
+func NewMemoryPayoutStore() *MemoryPayoutStore {
+	return &MemoryPayoutStore{records: make(map[string]PayoutRecord)}
+}
+
+func (m *MemoryPayoutStore) Get(outBizNo string) (record *PayoutRecord, err error) {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+	stored, ok := m.records[outBizNo]
+	if !ok {
+		return nil, payoutNotFoundErr
+	}
+	return &stored, nil
+}
+
+func (m *MemoryPayoutStore) Create(record *PayoutRecord) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	if _, ok := m.records[record.OutBizNo]; ok {
+		return payoutExistsErr
+	}
+	m.records[record.OutBizNo] = *record
+	return nil
+}
+
+func (m *MemoryPayoutStore) Update(record *PayoutRecord) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	if _, ok := m.records[record.OutBizNo]; !ok {
+		return payoutNotFoundErr
+	}
+	m.records[record.OutBizNo] = *record
+	return nil
+}
+
+// PayoutOptions 批量付款选项
+type PayoutOptions struct {
+	Concurrency   int           // 并发转账数，默认5
+	RateLimit     int           // 每秒最多发起的请求数（转账及查询），为0时不限制
+	MaxAttempts   int           // 单笔付款中转账及确认的最大尝试次数，默认3次
+	RetryInterval time.Duration // 转账结果未知时，再次查询或转账前的等待时间，默认不等待
+	ProductCode   ProductCode   // 销售产品码，默认 TRANS_ACCOUNT_NO_PWD
+	BizScene      string        // 业务场景，默认 DIRECT_TRANSFER
+	// BalanceUserId 付款方支付宝会员 id（2088 开头的 PID），不为空时 Run 在转账前检查余额户可用余额是否足够支付尚未发起转账的付款，
+	// 不足时返回 ErrInsufficientBalance 可判断的错误，不会发起任何转账
+	BalanceUserId string
+}
+
+// PayoutReport 批量付款报告
+type PayoutReport struct {
+	BatchNo         string         // 批次号
+	Total           int            // 付款总笔数
+	SuccessCount    int            // 成功笔数
+	FailedCount     int            // 失败笔数
+	ProcessingCount int            // 支付宝处理中的笔数
+	PendingCount    int            // 结果未确认的笔数
+	SuccessAmount   Amount         // 成功总金额
+	FailedAmount    Amount         // 失败总金额
+	Records         []PayoutRecord // 付款记录，按 out_biz_no 排序
+}
+
+// Done 批次中的付款是否全部进入最终状态
+func (r *PayoutReport) Done() bool {
+	return r.ProcessingCount == 0 && r.PendingCount == 0
+}
+
+// Filter 获取指定状态的付款记录
+func (r *PayoutReport) Filter(status PayoutRecordStatus) (records []PayoutRecord) {
+	for _, record := range r.Records {
+		if record.Status == status {
+			records = append(records, record)
+		}
+	}
+	return
+}
+
+// PayoutManager 批量付款，基于单笔转账接口 FundTransUniTransfer
+// 每笔付款在转账前以 out_biz_no 持久化，结果未知时（网络超时、20000、SYSTEM_ERROR）使用转账业务单据查询确认，
+// 进程中断后使用相同的批次号及付款明细再次调用 Run 即可继续，已成功或失败的付款不会再次转账
+type PayoutManager struct {
+	client  *Client
+	store   PayoutStore
+	options PayoutOptions
+}
+
+// NewPayoutManager 初始化批量付款，store 为空时使用内存存储，opts 为空时使用默认选项
+func NewPayoutManager(client *Client, store PayoutStore, opts *PayoutOptions) *PayoutManager {
+	manager := &PayoutManager{client: client, store: store}
+	if manager.store == nil {
+		manager.store = NewMemoryPayoutStore()
+	}
+	if opts != nil {
+		manager.options = *opts
+	}
+	if manager.options.Concurrency <= 0 {
+		manager.options.Concurrency = DefaultPayoutConcurrency
+	}
+	if manager.options.MaxAttempts <= 0 {
+		manager.options.MaxAttempts = DefaultPayoutMaxAttempts
+	}
+	if manager.options.ProductCode == "" {
+		manager.options.ProductCode = ProductCodeTransAccountNoPwd
+	}
+	if manager.options.BizScene == "" {
+		manager.options.BizScene = PayoutBizSceneDirectTransfer
+	}
+	return manager
+}
+
+// Run 执行批量付款并返回报告
+// 付款明细的 out_biz_no 为空、批次内重复、或已被其他批次及不同金额、收款方使用时返回错误，不会发起任何转账
+// ctx 取消时不再发起新的转账，已发起的转账完成后返回报告及 ctx.Err()，未处理的付款保持 PENDING
+func (m *PayoutManager) Run(ctx context.Context, batchNo string, items []PayoutItem) (report *PayoutReport, err error) {
+	if batchNo == "" {
+		return nil, payoutBatchNoIsEmptyErr
+	}
+	if err = checkPayoutItems(items); err != nil {
+		return
+	}
+	records := make([]*PayoutRecord, len(items))
+	for i, item := range items {
+		if records[i], err = m.loadOrCreate(batchNo, item); err != nil {
+			return
+		}
+	}
+
+	if m.options.BalanceUserId != "" {
+		if err = m.checkBalance(records); err != nil {
+			return
+		}
+	}
+
+	var limiter <-chan time.Time
+	if m.options.RateLimit > 0 {
+		ticker := time.NewTicker(time.Second / time.Duration(m.options.RateLimit))
+		defer ticker.Stop()
+		limiter = ticker.C
+	}
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	var errMutex sync.Mutex
+	for worker := 0; worker < m.options.Concurrency; worker++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				if payErr := m.pay(ctx, limiter, items[i], records[i]); payErr != nil {
+					errMutex.Lock()
+					if err == nil {
+						err = payErr
+					}
+					errMutex.Unlock()
+				}
+			}
+		}()
+	}
+dispatch:
+	for i, record := range records {
+		if record.Status.IsTerminal() {
+			continue
+		}
+		select {
+		case <-ctx.Done():
+			break dispatch
+		case indexes <- i:
+		}
+	}
+	close(indexes)
+	wg.Wait()
+	if ctxErr := ctx.Err(); ctxErr != nil && err == nil {
+		err = ctxErr
+	}
+	return newPayoutReport(batchNo, records), err
+}
+
+// Query 使用付款记录中的 out_biz_no 查询确认付款结果，用于 PROCESSING、PENDING 的付款
+// 转账到银行卡（TRANS_BANKCARD_NO_PWD）成功后仍可能被银行退票（REFUND），此时 SUCCESS 的付款同样会查询，退票后变为 FAILED
+func (m *PayoutManager) Query(outBizNo string) (record *PayoutRecord, err error) {
+	if record, err = m.store.Get(outBizNo); err != nil {
+		return
+	}
+	if record.Status == PayoutRecordFailed ||
+		record.Status == PayoutRecordSuccess && m.options.ProductCode != ProductCodeTransBankcardNoPwd {
+		return
+	}
+	if _, err = m.queryTransfer(record); err != nil {
+		return
+	}
+	record.UpdatedAt = time.Now()
+	err = m.store.Update(record)
+	return
+}
+
+// checkBalance 检查可用余额是否足够支付待转账的付款，处理中的付款已扣款不再计入。
+// 已发起过转账但结果未确认的付款可能已被支付宝受理扣款，同样不计入，避免误判余额不足
+func (m *PayoutManager) checkBalance(records []*PayoutRecord) (err error) {
+	var amount Amount
+	for _, record := range records {
+		if record.Status == PayoutRecordPending && record.Attempts == 0 && record.OrderId == "" {
+			amount = amount.Add(record.TransAmount)
+		}
+	}
+	if amount == 0 {
+		return nil
+	}
+	_, err = m.client.CheckFundBalance(m.options.BalanceUserId, FundAccountTypeAcctrans, amount)
+	return
+}
+
+func checkPayoutItems(items []PayoutItem) error {
+	outBizNos := make(map[string]struct{}, len(items))
+	for _, item := range items {
+		if item.OutBizNo == "" {
+			return errors.New("the payout out_biz_no is empty")
+		}
+		if _, ok := outBizNos[item.OutBizNo]; ok {
+			return fmt.Errorf("duplicate payout out_biz_no %s", item.OutBizNo)
+		}
+		outBizNos[item.OutBizNo] = struct{}{}
+		if item.TransAmount <= 0 || item.PayeeInfo == nil {
+			return fmt.Errorf("payout %s: invalid trans amount or payee info", item.OutBizNo)
+		}
+	}
+	return nil
+}
+
+// loadOrCreate 获取已有的付款记录，不存在时先持久化
+func (m *PayoutManager) loadOrCreate(batchNo string, item PayoutItem) (record *PayoutRecord, err error) {
+	record, err = m.store.Get(item.OutBizNo)
+	if err == nil {
+		return record, checkPayoutRecord(record, batchNo, item)
+	}
+	if !IsPayoutNotFound(err) {
+		return nil, err
+	}
+	now := time.Now()
+	record = &PayoutRecord{
+		BatchNo:       batchNo,
+		OutBizNo:      item.OutBizNo,
+		TransAmount:   item.TransAmount,
+		PayeeIdentity: item.PayeeInfo.Identity,
+		Status:        PayoutRecordPending,
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	}
+	if err = m.store.Create(record); err != nil {
+		if !IsPayoutExists(err) {
+			return nil, err
+		}
+		if record, err = m.store.Get(item.OutBizNo); err != nil {
+			return nil, err
+		}
+		return record, checkPayoutRecord(record, batchNo, item)
+	}
+	return
+}
+
+func checkPayoutRecord(record *PayoutRecord, batchNo string, item PayoutItem) error {
+	if record.BatchNo != batchNo || record.TransAmount != item.TransAmount || record.PayeeIdentity != item.PayeeInfo.Identity {
+		return fmt.Errorf("payout out_biz_no %s was used by batch %s with different amount or payee", item.OutBizNo, record.BatchNo)
+	}
+	return nil
+}
+
+// pay 处理单笔付款：PENDING 的付款发起转账，结果未知时查询确认；PROCESSING 的付款只查询
+func (m *PayoutManager) pay(ctx context.Context, limiter <-chan time.Time, item PayoutItem, record *PayoutRecord) (err error) {
+	for attempt := 0; attempt < m.options.MaxAttempts; attempt++ {
+		if attempt > 0 && m.options.RetryInterval > 0 {
+			if err = waitRetry(ctx, m.options.RetryInterval); err != nil {
+				break
+			}
+		}
+		var confirmed bool
+		if record.Status == PayoutRecordPending {
+			if err = waitPayoutLimiter(ctx, limiter); err != nil {
+				break
+			}
+			if confirmed, err = m.transferOnce(item, record); err != nil || confirmed {
+				break
+			}
+		}
+		// 结果未知或处理中，使用相同的 out_biz_no 查询确认
+		if err = waitPayoutLimiter(ctx, limiter); err != nil {
+			break
+		}
+		if confirmed, err = m.queryTransfer(record); err != nil || confirmed || record.Status == PayoutRecordProcessing {
+			break
+		}
+	}
+	record.UpdatedAt = time.Now()
+	if updateErr := m.store.Update(record); updateErr != nil && err == nil {
+		err = fmt.Errorf("update payout record %s: %w", record.OutBizNo, updateErr)
+	}
+	return
+}
+
+func waitPayoutLimiter(ctx context.Context, limiter <-chan time.Time) error {
+	if limiter == nil {
+		return ctx.Err()
+	}
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-limiter:
+		return nil
+	}
+}
+
+// transferOnce 发起一次转账，转账成功、处理中或业务失败时 confirmed 为 true
+func (m *PayoutManager) transferOnce(item PayoutItem, record *PayoutRecord) (confirmed bool, err error) {
+	record.Attempts++
+	response, err := m.client.FundTransUniTransfer(FundTransUniTransferRequestParams{
+		OutBizNo:       record.OutBizNo,
+		TransAmount:    record.TransAmount,
+		ProductCode:    m.options.ProductCode,
+		BizScene:       m.options.BizScene,
+		OrderTitle:     item.OrderTitle,
+		PayeeInfo:      item.PayeeInfo,
+		Remark:         item.Remark,
+		BusinessParams: item.BusinessInfo,
+	})
+	if err != nil {
+		if IsValidationError(err) {
+			record.Status, record.FailReason = PayoutRecordFailed, err.Error()
+			return true, nil
+		}
+		record.FailReason = err.Error()
+		return false, nil
+	}
+	data := response.Data
+	switch {
+	case data.Code == SuccessCode:
+		record.OrderId, record.PayFundOrderId, record.TransDate = data.OrderId, data.PayFundOrderId, data.TransDate
+		record.applyTransferStatus(data.Status, "")
+		return record.Status != PayoutRecordPending, nil
+	case data.Code == UnknownErrorCode, data.SubCode == SubCodeFundSystemError:
+		record.FailReason = fmt.Sprintf("%s %s %s", data.Code, data.SubCode, data.SubMsg)
+		return false, nil
+	}
+	record.Status = PayoutRecordFailed
+	record.FailReason = fmt.Sprintf("%s %s %s", data.Code, data.SubCode, data.SubMsg)
+	return true, nil
+}
+
+// queryTransfer 使用 out_biz_no 查询转账结果，进入最终状态时 confirmed 为 true
+// 转账单据不存在时付款保持 PENDING，可使用相同的 out_biz_no 重新转账
+func (m *PayoutManager) queryTransfer(record *PayoutRecord) (confirmed bool, err error) {
+	response, err := m.client.FundTransCommonQuery(FundTransCommonQueryRequestParams{
+		ProductCode: m.options.ProductCode,
+		BizScene:    m.options.BizScene,
+		OutBizNo:    record.OutBizNo,
+	})
+	if err != nil {
+		if IsValidationError(err) {
+			return false, err
+		}
+		return false, nil
+	}
+	data := response.Data
+	switch {
+	case data.Code == SuccessCode:
+		record.OrderId, record.PayFundOrderId, record.TransDate = data.OrderId, data.PayFundOrderId, data.PayDate
+		record.applyTransferStatus(data.Status, strings.TrimSpace(data.ErrorCode+" "+data.FailReason))
+		return record.Status.IsTerminal(), nil
+	case data.SubCode == SubCodeOrderNotExist:
+		record.Status = PayoutRecordPending
+	}
+	return false, nil
+}
+
+// applyTransferStatus 根据支付宝的转账单据状态更新付款记录状态
+func (r *PayoutRecord) applyTransferStatus(status TransferStatus, failReason string) {
+	r.TransferStatus = status
+	switch {
+	case status.IsSuccess():
+		r.Status, r.FailReason = PayoutRecordSuccess, ""
+	case status.IsFailed():
+		r.Status, r.FailReason = PayoutRecordFailed, failReason
+	case status.IsProcessing():
+		r.Status = PayoutRecordProcessing
+	}
+}
+
+func newPayoutReport(batchNo string, records []*PayoutRecord) *PayoutReport {
+	report := &PayoutReport{BatchNo: batchNo, Total: len(records)}
+	for _, record := range records {
+		switch record.Status {
+		case PayoutRecordSuccess:
+			report.SuccessCount++
+			report.SuccessAmount = report.SuccessAmount.Add(record.TransAmount)
+		case PayoutRecordFailed:
+			report.FailedCount++
+			report.FailedAmount = report.FailedAmount.Add(record.TransAmount)
+		case PayoutRecordProcessing:
+			report.ProcessingCount++
+		default:
+			report.PendingCount++
+		}
+		report.Records = append(report.Records, *record)
+	}
+	sort.Slice(report.Records, func(i, j int) bool {
+		return report.Records[i].OutBizNo < report.Records[j].OutBizNo
+	})
+	return report
+}
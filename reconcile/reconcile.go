@@ -0,0 +1,376 @@
+// Package reconcile 对账：比对本地订单、退款记录与支付宝业务账单
+package reconcile
+
+import (
+	"alipay"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"sort"
+	"strconv"
+)
+
+// RecordType 记录类型
+type RecordType string
+
+const (
+	RecordTypeTrade  RecordType = "TRADE"  // 交易
+	RecordTypeRefund RecordType = "REFUND" // 退款
+)
+
+// LocalStatus 本地记录状态
+type LocalStatus string
+
+const (
+	LocalStatusSuccess LocalStatus = "SUCCESS" // 本地已确认支付成功或退款成功，账单中应存在
+	LocalStatusPending LocalStatus = "PENDING" // 本地未确认，如等待支付、退款处理中
+	LocalStatusClosed  LocalStatus = "CLOSED"  // 本地已关闭或失败，账单中不应存在
+)
+
+// EntryKind 对账结果类型
+type EntryKind string
+
+const (
+	EntryMatched        EntryKind = "MATCHED"         // 一致
+	EntryMissingLocal   EntryKind = "MISSING_LOCAL"   // 账单中存在，本地不存在
+	EntryMissingAlipay  EntryKind = "MISSING_ALIPAY"  // 本地已成功，账单中不存在
+	EntryAmountMismatch EntryKind = "AMOUNT_MISMATCH" // 金额不一致
+	EntryStatusMismatch EntryKind = "STATUS_MISMATCH" // 账单中存在，本地状态不是成功
+)
+
+// LocalRecord 本地订单或退款记录
+type LocalRecord struct {
+	Type         RecordType    // 记录类型
+	OutTradeNo   string        // 商户订单号
+	OutRequestNo string        // 退款请求号，退款记录必填
+	Amount       alipay.Amount // 订单金额或退款金额（正数）
+	Status       LocalStatus   // 本地记录状态
+}
+
+// LocalSource 本地记录数据源，以迭代器方式逐条读取，用法同 alipay.TradeBillIterator
+type LocalSource interface {
+	Next() bool
+	Record() LocalRecord
+	Err() error
+}
+
+// BillSource 业务账单明细数据源，*alipay.TradeBillIterator 实现了该接口
+type BillSource interface {
+	Next() bool
+	Record() alipay.TradeBillRecord
+	Err() error
+}
+
+// TradeQuerier 交易查询，*alipay.Client 实现了该接口
+type TradeQuerier interface {
+	TradeQuery(requestParam alipay.TradeQueryRequestParams) (responseParam alipay.TradeQueryResponseParams, err error)
+}
+
+// Options 对账选项
+type Options struct {
+	BillDate    string       // 账单日期，仅用于报告展示
+	OmitMatched bool         // 报告中是否省略一致的记录，只保留差异
+	Querier     TradeQuerier // 不为空时，对账单中不存在的成功交易再调用交易查询确认，用于识别跨日交易
+}
+
+// Entry 单条对账结果
+type Entry struct {
+	Kind         EntryKind          `json:"kind"`           // 对账结果类型
+	Type         RecordType         `json:"type"`           // 记录类型
+	OutTradeNo   string             `json:"out_trade_no"`   // 商户订单号
+	OutRequestNo string             `json:"out_request_no"` // 退款请求号
+	TradeNo      string             `json:"trade_no"`       // 支付宝交易号
+	LocalAmount  alipay.Amount      `json:"local_amount"`   // 本地金额
+	AlipayAmount alipay.Amount      `json:"alipay_amount"`  // 账单金额，退款为正数
+	LocalStatus  LocalStatus        `json:"local_status"`   // 本地状态
+	ServiceFee   alipay.Amount      `json:"service_fee"`    // 账单中的服务费
+	QueryStatus  alipay.TradeStatus `json:"query_status"`   // 交易查询返回的交易状态，仅在设置了 Querier 时返回
+	Duplicate    bool               `json:"duplicate"`      // 账单或本地存在多条对账键相同的记录，如退款请求号为空的多笔退款
+}
+
+// Summary 对账汇总，金额均来自支付宝账单
+type Summary struct {
+	Counts       map[EntryKind]int `json:"counts"`        // 各对账结果类型的数量
+	TradeCount   int               `json:"trade_count"`   // 账单交易笔数
+	RefundCount  int               `json:"refund_count"`  // 账单退款笔数
+	TradeAmount  alipay.Amount     `json:"trade_amount"`  // 账单交易总金额
+	RefundAmount alipay.Amount     `json:"refund_amount"` // 账单退款总金额（正数）
+	ServiceFee   alipay.Amount     `json:"service_fee"`   // 账单服务费合计，支出为负数
+	NetAmount    alipay.Amount     `json:"net_amount"`    // 实收净额：交易总金额-退款总金额+服务费
+	DuplicateKey int               `json:"duplicate_key"` // 账单或本地存在多条记录的对账键数量
+}
+
+// Report 对账报告
+type Report struct {
+	BillDate string  `json:"bill_date"` // 账单日期
+	Summary  Summary `json:"summary"`   // 对账汇总
+	Entries  []Entry `json:"entries"`   // 对账明细，按结果类型、商户订单号排序
+}
+
+// HasDifference 是否存在差异
+func (r *Report) HasDifference() bool {
+	for kind, count := range r.Summary.Counts {
+		if kind != EntryMatched && count > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// Filter 获取指定对账结果类型的明细
+func (r *Report) Filter(kind EntryKind) (entries []Entry) {
+	for _, entry := range r.Entries {
+		if entry.Kind == kind {
+			entries = append(entries, entry)
+		}
+	}
+	return
+}
+
+// WriteJSON 以JSON格式导出对账报告
+func (r *Report) WriteJSON(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(r)
+}
+
+// csvHeader 导出CSV的表头
+var csvHeader = []string{"kind", "type", "out_trade_no", "out_request_no", "trade_no", "local_amount", "alipay_amount", "local_status", "service_fee", "query_status", "duplicate"}
+
+// WriteCSV 以CSV格式导出对账明细（UTF-8编码）
+func (r *Report) WriteCSV(w io.Writer) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(csvHeader); err != nil {
+		return err
+	}
+	for _, entry := range r.Entries {
+		if err := writer.Write([]string{
+			string(entry.Kind), string(entry.Type), entry.OutTradeNo, entry.OutRequestNo, entry.TradeNo,
+			entry.LocalAmount.String(), entry.AlipayAmount.String(), string(entry.LocalStatus),
+			entry.ServiceFee.String(), string(entry.QueryStatus), strconv.FormatBool(entry.Duplicate),
+		}); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// Daily 下载指定日期的业务账单并与本地记录对账
+func Daily(ctx context.Context, client *alipay.Client, billDate string, local LocalSource, opts *Options) (report *Report, err error) {
+	bill, err := client.DownloadBill(ctx, alipay.BillTypeTrade, billDate)
+	if err != nil {
+		return
+	}
+	defer bill.Close()
+	iterator, err := bill.TradeRecords()
+	if err != nil {
+		return
+	}
+	defer iterator.Close()
+	options := Options{}
+	if opts != nil {
+		options = *opts
+	}
+	if options.BillDate == "" {
+		options.BillDate = billDate
+	}
+	return Reconcile(local, iterator, &options)
+}
+
+// Reconcile 比对本地记录与业务账单明细
+// 账单明细全部加载到内存中，本地记录逐条比对，剩余的账单明细为本地不存在的记录
+// 对账键（商户订单号，退款为商户订单号+退款请求号）相同的多条记录按金额优先逐条配对，相关明细标记为 Duplicate
+func Reconcile(local LocalSource, bill BillSource, opts *Options) (report *Report, err error) {
+	options := Options{}
+	if opts != nil {
+		options = *opts
+	}
+	report = &Report{BillDate: options.BillDate, Summary: Summary{Counts: make(map[EntryKind]int)}}
+
+	billRecords := make(map[string][]alipay.TradeBillRecord)
+	for bill.Next() {
+		record := bill.Record()
+		recordType := RecordTypeTrade
+		amount := record.TotalAmount
+		if record.IsRefund() {
+			recordType = RecordTypeRefund
+			amount = absAmount(amount)
+			report.Summary.RefundCount++
+			report.Summary.RefundAmount = report.Summary.RefundAmount.Add(amount)
+		} else {
+			report.Summary.TradeCount++
+			report.Summary.TradeAmount = report.Summary.TradeAmount.Add(amount)
+		}
+		report.Summary.ServiceFee = report.Summary.ServiceFee.Add(record.ServiceFee)
+		key := recordKey(recordType, record.OutTradeNo, record.OutRequestNo)
+		billRecords[key] = append(billRecords[key], record)
+	}
+	recordCounts := make(map[string]int, len(billRecords))
+	for key, records := range billRecords {
+		recordCounts[key] = len(records)
+	}
+	if err = bill.Err(); err != nil {
+		return nil, err
+	}
+	report.Summary.NetAmount = report.Summary.TradeAmount.Sub(report.Summary.RefundAmount).Add(report.Summary.ServiceFee)
+
+	localCounts := make(map[string]int)
+	for local.Next() {
+		record := local.Record()
+		key := recordKey(record.Type, record.OutTradeNo, record.OutRequestNo)
+		billRecord, inBill := takeBillRecord(billRecords, key, record.Amount)
+		if localCounts[key]++; localCounts[key] > recordCounts[key] {
+			recordCounts[key] = localCounts[key]
+		}
+		entry := Entry{
+			Type:         record.Type,
+			OutTradeNo:   record.OutTradeNo,
+			OutRequestNo: record.OutRequestNo,
+			LocalAmount:  record.Amount,
+			LocalStatus:  record.Status,
+		}
+		switch {
+		case !inBill && record.Status != LocalStatusSuccess:
+			// 未成功的记录不在账单中，无需对账
+			continue
+		case !inBill:
+			entry.Kind = EntryMissingAlipay
+			if options.Querier != nil && record.Type == RecordTypeTrade {
+				entry.TradeNo, entry.QueryStatus = queryTrade(options.Querier, record.OutTradeNo)
+			}
+		default:
+			fillBillRecord(&entry, billRecord)
+			switch {
+			case record.Status != LocalStatusSuccess:
+				entry.Kind = EntryStatusMismatch
+			case entry.AlipayAmount != record.Amount:
+				entry.Kind = EntryAmountMismatch
+			default:
+				entry.Kind = EntryMatched
+			}
+		}
+		report.add(entry, options.OmitMatched)
+	}
+	if err = local.Err(); err != nil {
+		return nil, err
+	}
+
+	for _, records := range billRecords {
+		for _, billRecord := range records {
+			entry := Entry{Kind: EntryMissingLocal, Type: RecordTypeTrade, OutTradeNo: billRecord.OutTradeNo, OutRequestNo: billRecord.OutRequestNo}
+			if billRecord.IsRefund() {
+				entry.Type = RecordTypeRefund
+			}
+			fillBillRecord(&entry, billRecord)
+			report.add(entry, options.OmitMatched)
+		}
+	}
+	for i := range report.Entries {
+		entry := &report.Entries[i]
+		entry.Duplicate = recordCounts[recordKey(entry.Type, entry.OutTradeNo, entry.OutRequestNo)] > 1
+	}
+	for _, count := range recordCounts {
+		if count > 1 {
+			report.Summary.DuplicateKey++
+		}
+	}
+	sort.SliceStable(report.Entries, func(i, j int) bool {
+		if report.Entries[i].Kind != report.Entries[j].Kind {
+			return report.Entries[i].Kind < report.Entries[j].Kind
+		}
+		if report.Entries[i].OutTradeNo != report.Entries[j].OutTradeNo {
+			return report.Entries[i].OutTradeNo < report.Entries[j].OutTradeNo
+		}
+		return report.Entries[i].OutRequestNo < report.Entries[j].OutRequestNo
+	})
+	return
+}
+
+func (r *Report) add(entry Entry, omitMatched bool) {
+	r.Summary.Counts[entry.Kind]++
+	if entry.Kind == EntryMatched && omitMatched {
+		return
+	}
+	r.Entries = append(r.Entries, entry)
+}
+
+func fillBillRecord(entry *Entry, record alipay.TradeBillRecord) {
+	entry.TradeNo = record.TradeNo
+	entry.AlipayAmount = absAmount(record.TotalAmount)
+	entry.ServiceFee = record.ServiceFee
+}
+
+// queryTrade 查询账单中不存在的交易，如跨日支付的交易会出现在次日账单中
+func queryTrade(querier TradeQuerier, outTradeNo string) (tradeNo string, tradeStatus alipay.TradeStatus) {
+	response, err := querier.TradeQuery(alipay.TradeQueryRequestParams{OutTradeNo: outTradeNo})
+	if err != nil || response.Data.Code != alipay.SuccessCode {
+		return
+	}
+	return response.Data.TradeNo, response.Data.TradeStatus
+}
+
+// takeBillRecord 取出对账键对应的一条账单明细，存在多条时优先取金额相同的
+func takeBillRecord(billRecords map[string][]alipay.TradeBillRecord, key string, amount alipay.Amount) (record alipay.TradeBillRecord, ok bool) {
+	records := billRecords[key]
+	if len(records) == 0 {
+		return
+	}
+	index := 0
+	for i := range records {
+		if absAmount(records[i].TotalAmount) == amount {
+			index = i
+			break
+		}
+	}
+	record = records[index]
+	records = append(records[:index], records[index+1:]...)
+	if len(records) == 0 {
+		delete(billRecords, key)
+	} else {
+		billRecords[key] = records
+	}
+	return record, true
+}
+
+func recordKey(recordType RecordType, outTradeNo, outRequestNo string) string {
+	if recordType == RecordTypeRefund {
+		return string(recordType) + "|" + outTradeNo + "|" + outRequestNo
+	}
+	return string(recordType) + "|" + outTradeNo
+}
+
+func absAmount(amount alipay.Amount) alipay.Amount {
+	if amount < 0 {
+		return -amount
+	}
+	return amount
+}
+
+// SliceSource 基于切片的本地记录数据源
+type SliceSource struct {
+	records []LocalRecord
+	index   int
+}
+
+// NewSliceSource 以切片创建本地记录数据源
+func NewSliceSource(records []LocalRecord) *SliceSource {
+	return &SliceSource{records: records, index: -1}
+}
+
+func (s *SliceSource) Next() bool {
+	if s.index+1 >= len(s.records) {
+		return false
+	}
+	s.index++
+	return true
+}
+
+func (s *SliceSource) Record() LocalRecord {
+	return s.records[s.index]
+}
+
+func (s *SliceSource) Err() error {
+	return nil
+}
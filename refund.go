@@ -0,0 +1,324 @@
+package alipay
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"math/big"
+	"sync"
+	"time"
+)
+
+var (
+	refundNotFoundErr      = errors.New("the refund record does not exist in the refund store")
+	refundExistsErr        = errors.New("the refund record already exists in the refund store")
+	refundKeyIsEmptyErr    = errors.New("the refund key is empty")
+	refundParamsChangedErr = errors.New("the refund key was used with different trade or refund amount")
+)
+
+// ErrRefundPending 退款结果仍未确认，可使用相同的 RefundKey 再次调用 Refund，会继续使用原退款请求号，不会重复退款
+var ErrRefundPending = errors.New("the refund result is not confirmed yet")
+
+// DefaultRefundMaxAttempts RefundManager 单次 Refund 调用中退款及确认的默认最大尝试次数
+const DefaultRefundMaxAttempts = 3
+
+// RefundRecordStatus 退款记录状态
+type RefundRecordStatus string
+
+const (
+	RefundRecordPending RefundRecordStatus = "PENDING" // 已生成退款请求号，退款结果未确认
+	RefundRecordSuccess RefundRecordStatus = "SUCCESS" // 退款成功
+	RefundRecordFailed  RefundRecordStatus = "FAILED"  // 退款业务失败，如余额不足，可使用相同的 RefundKey 重试
+)
+
+// RefundRecord 退款记录，保存业务退款单与支付宝退款请求号 out_request_no 的对应关系
+type RefundRecord struct {
+	RefundKey    string             `json:"refund_key"`     // 业务退款单号，如售后单号，同一笔退款多次调用需使用相同的值
+	OutTradeNo   string             `json:"out_trade_no"`   // 商户订单号
+	TradeNo      string             `json:"trade_no"`       // 支付宝交易号
+	OutRequestNo string             `json:"out_request_no"` // 退款请求号，同一笔退款始终使用同一个请求号
+	RefundAmount Amount             `json:"refund_amount"`  // 退款金额
+	Status       RefundRecordStatus `json:"status"`         // 退款记录状态
+	FailReason   string             `json:"fail_reason"`    // 最后一次失败的原因
+	CreatedAt    time.Time          `json:"created_at"`     // 创建时间
+	UpdatedAt    time.Time          `json:"updated_at"`     // 更新时间
+}
+
+// RefundStore 退款记录存储，需在发起退款前持久化退款请求号，保证重试时使用同一个请求号
+type RefundStore interface {
+	// Get 获取退款记录，不存在时返回 IsRefundNotFound 可判断的错误
+	Get(refundKey string) (record *RefundRecord, err error)
+	// Create 新增退款记录，记录已存在时返回 IsRefundExists 可判断的错误，需保证并发调用时只有一个成功
+	Create(record *RefundRecord) error
+	// Update 更新退款记录
+	Update(record *RefundRecord) error
+}
+
+// IsRefundNotFound 判断错误是否为退款记录不存在
+func IsRefundNotFound(err error) bool {
+	return errors.Is(err, refundNotFoundErr)
+}
+
+// IsRefundExists 判断错误是否为退款记录已存在
+func IsRefundExists(err error) bool {
+	return errors.Is(err, refundExistsErr)
+}
+
+// MemoryRefundStore 内存退款记录存储，进程重启后失效，仅用于测试或单机场景
+type MemoryRefundStore struct {
+	mutex   sync.RWMutex
+	records map[string]RefundRecord
+}
+
+// NewMemoryRefundStore 初始化内存退款记录存储
+func NewMemoryRefundStore() *MemoryRefundStore {
+	return &MemoryRefundStore{records: make(map[string]RefundRecord)}
+}
+
+func (m *MemoryRefundStore) Get(refundKey string) (record *RefundRecord, err error) {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+	stored, ok := m.records[refundKey]
+	if !ok {
+		return nil, refundNotFoundErr
+	}
+	return &stored, nil
+}
+
+func (m *MemoryRefundStore) Create(record *RefundRecord) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	if _, ok := m.records[record.RefundKey]; ok {
+		return refundExistsErr
+	}
+	m.records[record.RefundKey] = *record
+	return nil
+}
+
+func (m *MemoryRefundStore) Update(record *RefundRecord) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	if _, ok := m.records[record.RefundKey]; !ok {
+		return refundNotFoundErr
+	}
+	m.records[record.RefundKey] = *record
+	return nil
+}
+
+// RefundRequest 退款请求
+type RefundRequest struct {
+	RefundKey    string // 业务退款单号，必填，用于幂等
+	OutTradeNo   string // 商户订单号，与 TradeNo 不能同时为空
+	TradeNo      string // 支付宝交易号
+	RefundAmount Amount // 退款金额
+	RefundReason string // 退款原因
+	AppAuthToken string // 应用授权令牌，服务商代调用时使用
+}
+
+// RefundManagerOptions RefundManager 选项
+type RefundManagerOptions struct {
+	MaxAttempts          int                                // 单次 Refund 调用中退款及确认的最大尝试次数，默认3次
+	RetryInterval        time.Duration                      // 退款结果未知时，再次查询或退款前的等待时间，默认不等待
+	GenerateOutRequestNo func(request RefundRequest) string // 生成退款请求号，默认为 R+时间+随机数
+}
+
+// RefundManager 退款管理，为每笔业务退款生成并持久化唯一的退款请求号 out_request_no
+// 结果未知时（网络超时、20000、ACQ.SYSTEM_ERROR、fund_change=N）使用相同的退款请求号查询确认，保证不会重复退款
+type RefundManager struct {
+	client  *Client
+	store   RefundStore
+	options RefundManagerOptions
+}
+
+// NewRefundManager 初始化退款管理，store 为空时使用内存存储，opts 为空时使用默认选项
+func NewRefundManager(client *Client, store RefundStore, opts *RefundManagerOptions) *RefundManager {
+	manager := &RefundManager{client: client, store: store}
+	if manager.store == nil {
+		manager.store = NewMemoryRefundStore()
+	}
+	if opts != nil {
+		manager.options = *opts
+	}
+	if manager.options.MaxAttempts <= 0 {
+		manager.options.MaxAttempts = DefaultRefundMaxAttempts
+	}
+	if manager.options.GenerateOutRequestNo == nil {
+		manager.options.GenerateOutRequestNo = defaultOutRequestNo
+	}
+	return manager
+}
+
+// Refund 发起退款并确认结果
+// 同一个 RefundKey 始终使用同一个退款请求号；已成功的退款直接返回记录，不会再次请求
+// 返回 ErrRefundPending 时退款结果仍未确认，可稍后使用相同的 RefundKey 再次调用；重试等待期间 ctx 取消时返回 ctx.Err()，退款保持未确认
+func (m *RefundManager) Refund(ctx context.Context, request RefundRequest) (record *RefundRecord, err error) {
+	if request.RefundKey == "" {
+		return nil, refundKeyIsEmptyErr
+	}
+	if record, err = m.loadOrCreate(request); err != nil {
+		return
+	}
+	if record.Status == RefundRecordSuccess {
+		return
+	}
+	// 业务失败的退款使用相同的退款请求号重试
+	record.Status = RefundRecordPending
+
+	for attempt := 0; attempt < m.options.MaxAttempts; attempt++ {
+		if attempt > 0 && m.options.RetryInterval > 0 {
+			if err = waitRetry(ctx, m.options.RetryInterval); err != nil {
+				break
+			}
+		}
+		var confirmed bool
+		if confirmed, err = m.refundOnce(request, record); err != nil || confirmed {
+			break
+		}
+		// 结果未知，使用相同的退款请求号查询确认
+		if confirmed, err = m.queryRefund(request, record); err != nil || confirmed {
+			break
+		}
+	}
+	if err == nil && record.Status == RefundRecordPending {
+		err = ErrRefundPending
+	}
+	record.UpdatedAt = time.Now()
+	if updateErr := m.store.Update(record); updateErr != nil && err == nil {
+		err = fmt.Errorf("update refund record %s: %w", record.RefundKey, updateErr)
+	}
+	return
+}
+
+// waitRetry 等待重试间隔，ctx 取消时立即返回
+func waitRetry(ctx context.Context, interval time.Duration) error {
+	timer := time.NewTimer(interval)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// Query 使用退款记录中的退款请求号查询确认退款结果
+func (m *RefundManager) Query(refundKey string) (record *RefundRecord, err error) {
+	if record, err = m.store.Get(refundKey); err != nil {
+		return
+	}
+	if record.Status == RefundRecordSuccess {
+		return
+	}
+	request := RefundRequest{RefundKey: refundKey, OutTradeNo: record.OutTradeNo, TradeNo: record.TradeNo}
+	if _, err = m.queryRefund(request, record); err != nil {
+		return
+	}
+	record.UpdatedAt = time.Now()
+	err = m.store.Update(record)
+	return
+}
+
+// loadOrCreate 获取已有的退款记录，不存在时生成退款请求号并先持久化
+func (m *RefundManager) loadOrCreate(request RefundRequest) (record *RefundRecord, err error) {
+	record, err = m.store.Get(request.RefundKey)
+	if err == nil {
+		return record, checkRefundParams(record, request)
+	}
+	if !IsRefundNotFound(err) {
+		return nil, err
+	}
+	now := time.Now()
+	record = &RefundRecord{
+		RefundKey:    request.RefundKey,
+		OutTradeNo:   request.OutTradeNo,
+		TradeNo:      request.TradeNo,
+		OutRequestNo: m.options.GenerateOutRequestNo(request),
+		RefundAmount: request.RefundAmount,
+		Status:       RefundRecordPending,
+		CreatedAt:    now,
+		UpdatedAt:    now,
+	}
+	if err = m.store.Create(record); err != nil {
+		if !IsRefundExists(err) {
+			return nil, err
+		}
+		// 并发创建时使用已保存的记录
+		if record, err = m.store.Get(request.RefundKey); err != nil {
+			return nil, err
+		}
+		return record, checkRefundParams(record, request)
+	}
+	return
+}
+
+func checkRefundParams(record *RefundRecord, request RefundRequest) error {
+	if record.OutTradeNo != request.OutTradeNo || record.TradeNo != request.TradeNo || record.RefundAmount != request.RefundAmount {
+		return refundParamsChangedErr
+	}
+	return nil
+}
+
+// refundOnce 发起一次退款，退款成功或业务失败时 confirmed 为 true
+func (m *RefundManager) refundOnce(request RefundRequest, record *RefundRecord) (confirmed bool, err error) {
+	response, err := m.client.TradeRefund(TradeRefundRequestParams{
+		OtherRequestParams: OtherRequestParams{AppAuthToken: request.AppAuthToken},
+		OutTradeNo:         record.OutTradeNo,
+		TradeNo:            record.TradeNo,
+		RefundAmount:       record.RefundAmount,
+		RefundReason:       request.RefundReason,
+		OutRequestNo:       record.OutRequestNo,
+	})
+	if err != nil {
+		if IsValidationError(err) {
+			return false, err
+		}
+		record.FailReason = err.Error()
+		return false, nil
+	}
+	data := response.Data
+	switch {
+	case data.Code == SuccessCode && data.FundChange == "Y":
+		record.Status, record.FailReason = RefundRecordSuccess, ""
+		return true, nil
+	case data.Code == SuccessCode, data.Code == UnknownErrorCode, data.SubCode == SubCodeSystemError:
+		// fund_change=N 可能是重复请求，需查询确认
+		record.FailReason = fmt.Sprintf("%s %s %s", data.Code, data.SubCode, data.SubMsg)
+		return false, nil
+	}
+	record.Status = RefundRecordFailed
+	record.FailReason = fmt.Sprintf("%s %s %s", data.Code, data.SubCode, data.SubMsg)
+	return true, fmt.Errorf("refund %s fail: %s", record.RefundKey, record.FailReason)
+}
+
+// queryRefund 使用退款请求号查询退款结果，退款成功时 confirmed 为 true
+func (m *RefundManager) queryRefund(request RefundRequest, record *RefundRecord) (confirmed bool, err error) {
+	response, err := m.client.TradeFastPayRefundQuery(TradeFastPayRefundQueryRequestParams{
+		OtherRequestParams: OtherRequestParams{AppAuthToken: request.AppAuthToken},
+		OutTradeNo:         record.OutTradeNo,
+		TradeNo:            record.TradeNo,
+		OutRequestNo:       record.OutRequestNo,
+	})
+	if err != nil {
+		if IsValidationError(err) {
+			return false, err
+		}
+		return false, nil
+	}
+	if response.Data.Code == SuccessCode && response.Data.RefundStatus.IsSuccess() {
+		record.Status, record.FailReason = RefundRecordSuccess, ""
+		return true, nil
+	}
+	// 未返回退款成功表示退款请求未收到或者退款失败，可使用相同的退款请求号重新发起退款
+	return false, nil
+}
+
+// defaultOutRequestNo 默认的退款请求号：R + 时间（精确到毫秒） + 6位随机数
+func defaultOutRequestNo(request RefundRequest) string {
+	n, err := rand.Int(rand.Reader, big.NewInt(1000000))
+	if err != nil {
+		n = big.NewInt(time.Now().UnixNano() % 1000000)
+	}
+	now := time.Now()
+	return fmt.Sprintf("R%s%03d%06d", now.Format("20060102150405"), now.Nanosecond()/int(time.Millisecond), n.Int64())
+}
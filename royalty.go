@@ -0,0 +1,61 @@
+package alipay
+
+// TradeRoyaltyRelationBind 分账关系绑定，分账前需先绑定分账接收方
+func (a *Client) TradeRoyaltyRelationBind(requestParam TradeRoyaltyRelationBindRequestParams) (
+	responseParam TradeRoyaltyRelationBindResponseParams, err error) {
+	if err = a.HandlerRequest("POST", &requestParam, &responseParam); err != nil {
+		return
+	}
+	return
+}
+
+// TradeRoyaltyRelationUnbind 分账关系解绑
+func (a *Client) TradeRoyaltyRelationUnbind(requestParam TradeRoyaltyRelationUnbindRequestParams) (
+	responseParam TradeRoyaltyRelationUnbindResponseParams, err error) {
+	if err = a.HandlerRequest("POST", &requestParam, &responseParam); err != nil {
+		return
+	}
+	return
+}
+
+// TradeRoyaltyRelationBatchQuery 分账关系查询
+func (a *Client) TradeRoyaltyRelationBatchQuery(requestParam TradeRoyaltyRelationBatchQueryRequestParams) (
+	responseParam TradeRoyaltyRelationBatchQueryResponseParams, err error) {
+	if err = a.HandlerRequest("POST", &requestParam, &responseParam); err != nil {
+		return
+	}
+	return
+}
+
+// TradeOrderSettle 统一收单交易结算接口，交易成功后按分账明细分账
+func (a *Client) TradeOrderSettle(requestParam TradeOrderSettleRequestParams) (
+	responseParam TradeOrderSettleResponseParams, err error) {
+	if err = a.HandlerRequest("POST", &requestParam, &responseParam); err != nil {
+		return
+	}
+	return
+}
+
+// TradeOrderSettleQuery 交易分账查询接口
+func (a *Client) TradeOrderSettleQuery(requestParam TradeOrderSettleQueryRequestParams) (
+	responseParam TradeOrderSettleQueryResponseParams, err error) {
+	if err = a.HandlerRequest("POST", &requestParam, &responseParam); err != nil {
+		return
+	}
+	return
+}
+
+// TradeRoyaltyRateQuery 分账比例查询
+func (a *Client) TradeRoyaltyRateQuery(requestParam TradeRoyaltyRateQueryRequestParams) (
+	responseParam TradeRoyaltyRateQueryResponseParams, err error) {
+	if err = a.HandlerRequest("POST", &requestParam, &responseParam); err != nil {
+		return
+	}
+	return
+}
+
+// AsyncNotifyTradeOrderSettle 处理交易分账结果通知（alipay.trade.order.settle.notify）
+func (a *Client) AsyncNotifyTradeOrderSettle(rawBody string) (notifyResult TradeOrderSettleNotificationParams, err error) {
+	err = a.verifyAndDecodeNotify(rawBody, false, &notifyResult, &notifyResult.Content)
+	return
+}
@@ -0,0 +1,131 @@
+package alipay
+
+import (
+	"alipay/utils"
+	"crypto/rsa"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// SelfCheckError 客户端配置自检错误，包含所有未通过的检查项
+type SelfCheckError struct {
+	Errors []error
+}
+
+func (e *SelfCheckError) Error() string {
+	messages := make([]string, 0, len(e.Errors))
+	for _, err := range e.Errors {
+		messages = append(messages, err.Error())
+	}
+	return "alipay client self check failed: " + strings.Join(messages, "; ")
+}
+
+// Is 任一未通过的检查项满足 errors.Is(err, target) 时返回 true
+// go.mod 声明的 Go 版本为1.17，errors.Is 不会遍历 Unwrap() []error，需由该方法逐项判断
+func (e *SelfCheckError) Is(target error) bool {
+	for _, err := range e.Errors {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// As 将第一个满足 errors.As(err, target) 的检查项赋值给 target
+func (e *SelfCheckError) As(target interface{}) bool {
+	for _, err := range e.Errors {
+		if errors.As(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// Unwrap 返回所有未通过的检查项，Go 1.20 及以上版本的 errors.Is/errors.As 也会遍历该方法的返回值
+func (e *SelfCheckError) Unwrap() []error {
+	return e.Errors
+}
+
+// AddAppPublicKey 设置应用公钥（公钥模式下，不包含begin，end），用于 SelfCheck 校验应用私钥与上传到支付宝的应用公钥是否匹配
+func AddAppPublicKey(appPublicKey string) OptionFunc {
+	return func(c *Client) {
+		// 解析失败时置空，由 SelfCheck 报告
+		c.appPublicKey, _ = utils.ParsePKIXPublicKey(utils.GetPemPublic(appPublicKey))
+		c.appPublicKeyRaw = appPublicKey
+	}
+}
+
+// SelfCheck 校验客户端的密钥配置，避免密钥不匹配时只能从网关的 invalid-signature 中发现问题
+// 校验内容：
+// 1.应用私钥已设置，且签名算法类型与私钥长度匹配（RSA2 要求至少2048位）
+// 2.公钥模式下支付宝公钥已设置；设置了应用公钥时，应用私钥与应用公钥匹配
+// 3.证书模式下应用公钥证书、支付宝公钥证书、支付宝根证书三个序列号均已加载，且应用私钥与应用公钥证书匹配
+// 所有未通过的检查项以 *SelfCheckError 返回，全部通过时返回 nil
+func (a *Client) SelfCheck() error {
+	var errs []error
+	appPrivateKey, _ := a.signingKey()
+
+	switch a.signType {
+	case SignTypeRSA, SignTypeRSA2:
+	default:
+		errs = append(errs, fmt.Errorf("unsupported sign type %q, only RSA and RSA2 are supported", a.signType))
+	}
+
+	if appPrivateKey == nil {
+		errs = append(errs, fmt.Errorf("the app private key is not set"))
+	} else {
+		bits := appPrivateKey.N.BitLen()
+		if a.signType == SignTypeRSA2 && bits < 2048 {
+			errs = append(errs, fmt.Errorf("sign type RSA2 requires an app private key of at least 2048 bits, got %d", bits))
+		}
+		if a.signType == SignTypeRSA && bits < 1024 {
+			errs = append(errs, fmt.Errorf("sign type RSA requires an app private key of at least 1024 bits, got %d", bits))
+		}
+	}
+
+	a.mutex.RLock()
+	appCertSN, aliCertSN, alipayRootCertSn := a.appCertSN, a.aliCertSN, a.alipayRootCertSn
+	appCert, appPublicKey, appPublicKeyRaw := a.appCert, a.appPublicKey, a.appPublicKeyRaw
+	a.mutex.RUnlock()
+
+	if appCertSN == "" && aliCertSN == "" && alipayRootCertSn == "" {
+		// 公钥模式
+		if a.aliPublicKey == nil {
+			errs = append(errs, fmt.Errorf("the alipay public key is not set"))
+		}
+		if appPublicKeyRaw != "" && appPublicKey == nil {
+			errs = append(errs, fmt.Errorf("the app public key cannot be parsed"))
+		}
+		if appPrivateKey != nil && appPublicKey != nil && !isKeyPairMatched(appPrivateKey, appPublicKey) {
+			errs = append(errs, fmt.Errorf("the app private key does not match the app public key"))
+		}
+	} else {
+		// 证书模式
+		if appCertSN == "" {
+			errs = append(errs, fmt.Errorf("cert mode: the app cert SN is not loaded, call LoadAppCertSN"))
+		}
+		if aliCertSN == "" {
+			errs = append(errs, fmt.Errorf("cert mode: the alipay cert SN is not loaded, call LoadAliCertSN"))
+		}
+		if alipayRootCertSn == "" {
+			errs = append(errs, fmt.Errorf("cert mode: the alipay root cert SN is not loaded, call LoadAlipayRootCertSN"))
+		}
+		if appPrivateKey != nil && appCert != nil {
+			appCertPublicKey, ok := appCert.PublicKey.(*rsa.PublicKey)
+			if !ok || !isKeyPairMatched(appPrivateKey, appCertPublicKey) {
+				errs = append(errs, fmt.Errorf("the app private key does not match the app public key certificate"))
+			}
+		}
+	}
+
+	if len(errs) > 0 {
+		return &SelfCheckError{Errors: errs}
+	}
+	return nil
+}
+
+// isKeyPairMatched 判断私钥与公钥是否为一对
+func isKeyPairMatched(privateKey *rsa.PrivateKey, publicKey *rsa.PublicKey) bool {
+	return privateKey.PublicKey.Equal(publicKey)
+}
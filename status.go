@@ -0,0 +1,188 @@
+package alipay
+
+// TradeStatus 交易状态
+// 状态流转：WAIT_BUYER_PAY → TRADE_SUCCESS/TRADE_FINISHED/TRADE_CLOSED，TRADE_SUCCESS → TRADE_FINISHED（超过可退款期限）/TRADE_CLOSED（全额退款）
+type TradeStatus string
+
+const (
+	TradeStatusWaitBuyerPay TradeStatus = "WAIT_BUYER_PAY" // 交易创建，等待买家付款
+	TradeStatusClosed       TradeStatus = "TRADE_CLOSED"   // 未付款交易超时关闭，或支付完成后全额退款
+	TradeStatusSuccess      TradeStatus = "TRADE_SUCCESS"  // 交易支付成功
+	TradeStatusFinished     TradeStatus = "TRADE_FINISHED" // 交易结束，不可退款
+)
+
+// tradeStatusTransitions 交易状态允许的流转
+var tradeStatusTransitions = map[TradeStatus][]TradeStatus{
+	TradeStatusWaitBuyerPay: {TradeStatusSuccess, TradeStatusFinished, TradeStatusClosed},
+	TradeStatusSuccess:      {TradeStatusFinished, TradeStatusClosed},
+}
+
+// IsValid 是否为已知的交易状态
+func (s TradeStatus) IsValid() bool {
+	switch s {
+	case TradeStatusWaitBuyerPay, TradeStatusClosed, TradeStatusSuccess, TradeStatusFinished:
+		return true
+	}
+	return false
+}
+
+// IsPaid 买家是否已付款（TRADE_SUCCESS 或 TRADE_FINISHED）
+func (s TradeStatus) IsPaid() bool {
+	return s == TradeStatusSuccess || s == TradeStatusFinished
+}
+
+// IsTerminal 是否为不会再变化的终态（TRADE_FINISHED 或 TRADE_CLOSED）
+// 注意 TRADE_SUCCESS 不是终态，后续可能因全额退款变为 TRADE_CLOSED
+func (s TradeStatus) IsTerminal() bool {
+	return s == TradeStatusFinished || s == TradeStatusClosed
+}
+
+// CanRefund 当前状态是否可以发起退款，仅 TRADE_SUCCESS 可退款
+func (s TradeStatus) CanRefund() bool {
+	return s == TradeStatusSuccess
+}
+
+// CanTransitionTo 是否可以从当前状态流转到 next，状态相同时返回 true
+func (s TradeStatus) CanTransitionTo(next TradeStatus) bool {
+	if s == next {
+		return s.IsValid()
+	}
+	for _, status := range tradeStatusTransitions[s] {
+		if status == next {
+			return true
+		}
+	}
+	return false
+}
+
+// RefundStatus 退款状态
+type RefundStatus string
+
+const (
+	// RefundStatusSuccess 退款处理成功；退款查询未返回退款状态表示退款请求未收到或者退款失败
+	RefundStatusSuccess RefundStatus = "REFUND_SUCCESS"
+)
+
+// IsSuccess 退款是否处理成功
+func (s RefundStatus) IsSuccess() bool {
+	return s == RefundStatusSuccess
+}
+
+// TransferStatus 转账单据状态
+type TransferStatus string
+
+const (
+	TransferStatusSuccess TransferStatus = "SUCCESS"  // 成功（对转账到支付宝账户的单据，该状态为最终状态）
+	TransferStatusWaitPay TransferStatus = "WAIT_PAY" // 等待支付
+	TransferStatusDealing TransferStatus = "DEALING"  // 处理中，转账到银行卡时需要等待最终状态
+	TransferStatusFail    TransferStatus = "FAIL"     // 失败
+	TransferStatusRefund  TransferStatus = "REFUND"   // 退票，转账到银行卡成功后被银行退回
+	TransferStatusClosed  TransferStatus = "CLOSED"   // 关闭
+	TransferStatusInit    TransferStatus = "INIT"     // 待处理
+)
+
+// IsSuccess 转账是否成功
+func (s TransferStatus) IsSuccess() bool {
+	return s == TransferStatusSuccess
+}
+
+// IsFailed 转账是否失败（失败、退票或关闭）
+func (s TransferStatus) IsFailed() bool {
+	return s == TransferStatusFail || s == TransferStatusRefund || s == TransferStatusClosed
+}
+
+// IsProcessing 转账是否处理中，需要查询或等待通知确认最终状态
+func (s TransferStatus) IsProcessing() bool {
+	return s == TransferStatusDealing || s == TransferStatusWaitPay || s == TransferStatusInit
+}
+
+// IsTerminal 是否为最终状态；注意转账到银行卡成功后仍可能退票
+func (s TransferStatus) IsTerminal() bool {
+	return s.IsSuccess() || s.IsFailed()
+}
+
+// ProductCode 销售产品码，商家和支付宝签约的产品码
+type ProductCode string
+
+const (
+	ProductCodeFastInstantTradePay ProductCode = "FAST_INSTANT_TRADE_PAY" // 电脑网站支付
+	ProductCodeQuickWapWay         ProductCode = "QUICK_WAP_WAY"          // 手机网站支付
+	ProductCodeQuickMSecurityPay   ProductCode = "QUICK_MSECURITY_PAY"    // App支付（无线快捷支付）
+	ProductCodeFaceToFacePayment   ProductCode = "FACE_TO_FACE_PAYMENT"   // 当面付
+	ProductCodeOfflinePayment      ProductCode = "OFFLINE_PAYMENT"        // 当面付快捷版
+	ProductCodeCyclePayAuth        ProductCode = "CYCLE_PAY_AUTH"         // 周期扣款
+	ProductCodeGeneralWithholding  ProductCode = "GENERAL_WITHHOLDING"    // 商户代扣
+	ProductCodeTransAccountNoPwd   ProductCode = "TRANS_ACCOUNT_NO_PWD"   // 单笔无密转账到支付宝账户
+	ProductCodeTransBankcardNoPwd  ProductCode = "TRANS_BANKCARD_NO_PWD"  // 单笔无密转账到银行卡
+	ProductCodeStdRedPacket        ProductCode = "STD_RED_PACKET"         // 现金红包
+	ProductCodePreAuth             ProductCode = "PRE_AUTH"               // 线下资金预授权（扫码冻结）
+	ProductCodePreAuthOnline       ProductCode = "PRE_AUTH_ONLINE"        // 线上资金预授权（App冻结）
+)
+
+// FundAuthOrderStatus 资金授权订单状态
+type FundAuthOrderStatus string
+
+const (
+	FundAuthOrderStatusInit       FundAuthOrderStatus = "INIT"       // 初始，等待用户授权
+	FundAuthOrderStatusAuthorized FundAuthOrderStatus = "AUTHORIZED" // 已授权，资金已冻结
+	FundAuthOrderStatusFinish     FundAuthOrderStatus = "FINISH"     // 完成，冻结的资金已全部解冻或转支付
+	FundAuthOrderStatusClosed     FundAuthOrderStatus = "CLOSED"     // 关闭，用户未授权或已撤销
+)
+
+// IsTerminal 是否为终态（FINISH 或 CLOSED）
+func (s FundAuthOrderStatus) IsTerminal() bool {
+	return s == FundAuthOrderStatusFinish || s == FundAuthOrderStatusClosed
+}
+
+// FundAuthOperationType 资金授权操作类型
+type FundAuthOperationType string
+
+const (
+	FundAuthOperationFreeze   FundAuthOperationType = "FREEZE"   // 冻结
+	FundAuthOperationUnfreeze FundAuthOperationType = "UNFREEZE" // 解冻
+	FundAuthOperationPay      FundAuthOperationType = "PAY"      // 转支付
+)
+
+// FundAuthOperationStatus 资金授权操作流水状态
+type FundAuthOperationStatus string
+
+const (
+	FundAuthOperationStatusInit    FundAuthOperationStatus = "INIT"    // 初始，处理中
+	FundAuthOperationStatusSuccess FundAuthOperationStatus = "SUCCESS" // 成功
+	FundAuthOperationStatusClosed  FundAuthOperationStatus = "CLOSED"  // 关闭
+)
+
+// IsSuccess 资金操作是否成功
+func (s FundAuthOperationStatus) IsSuccess() bool {
+	return s == FundAuthOperationStatusSuccess
+}
+
+// AgreementStatus 个人代扣协议状态
+type AgreementStatus string
+
+const (
+	AgreementStatusTemp   AgreementStatus = "TEMP"   // 暂存，协议未生效
+	AgreementStatusNormal AgreementStatus = "NORMAL" // 正常，可以扣款
+	AgreementStatusStop   AgreementStatus = "STOP"   // 暂停
+	AgreementStatusUnsign AgreementStatus = "UNSIGN" // 已解约，仅在解约通知中返回
+)
+
+// IsNormal 协议是否正常可扣款
+func (s AgreementStatus) IsNormal() bool {
+	return s == AgreementStatusNormal
+}
+
+// 个人签约产品码，商家和支付宝签约时确定
+const (
+	PersonalProductCodeCyclePayAuth       = "CYCLE_PAY_AUTH_P"      // 周期扣款
+	PersonalProductCodeGeneralWithholding = "GENERAL_WITHHOLDING_P" // 商户代扣
+)
+
+// SettleDetailState 分账明细状态
+type SettleDetailState string
+
+const (
+	SettleDetailStateSuccess    SettleDetailState = "SUCCESS"    // 分账成功
+	SettleDetailStateFail       SettleDetailState = "FAIL"       // 分账失败
+	SettleDetailStateProcessing SettleDetailState = "PROCESSING" // 分账处理中
+)
@@ -0,0 +1,450 @@
+package alipay
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+var (
+	subscriptionNotFoundErr = errors.New("the subscription does not exist in the subscription store")
+	subscriptionExistsErr   = errors.New("the subscription already exists in the subscription store")
+)
+
+const (
+	// DefaultSubscriptionInterval SubscriptionScheduler.Run 检查到期订阅的默认间隔
+	DefaultSubscriptionInterval = time.Hour
+	// PeriodTypeDay 扣款周期按天计
+	PeriodTypeDay = "DAY"
+	// PeriodTypeMonth 扣款周期按自然月计
+	PeriodTypeMonth = "MONTH"
+
+	deductDateLayout = "2006-01-02"
+)
+
+// DefaultSubscriptionRetryIntervals 扣款失败后的默认重试间隔：1小时、6小时、24小时后各重试一次
+var DefaultSubscriptionRetryIntervals = []time.Duration{time.Hour, 6 * time.Hour, 24 * time.Hour}
+
+// agreementInvalidSubCodes 协议无效，不再重试，订阅停止
+var agreementInvalidSubCodes = map[string]bool{
+	"ACQ.AGREEMENT_NOT_EXIST":         true,
+	"ACQ.AGREEMENT_INVALID":           true,
+	"ACQ.AGREEMENT_STATUS_NOT_NORMAL": true,
+}
+
+// SubscriptionStatus 订阅状态
+type SubscriptionStatus string
+
+const (
+	SubscriptionActive    SubscriptionStatus = "ACTIVE"    // 生效中，按周期扣款
+	SubscriptionCompleted SubscriptionStatus = "COMPLETED" // 已达到周期规则的总扣款次数或总金额
+	SubscriptionStopped   SubscriptionStatus = "STOPPED"   // 协议无效（如用户已解约）或扣款参数错误，停止扣款
+)
+
+// Subscription 订阅，记录协议的周期规则及扣款进度
+type Subscription struct {
+	SubscriptionId string             `json:"subscription_id"`   // 订阅ID，仅支持字母、数字、下划线，默认用于生成扣款的商户订单号
+	AgreementNo    string             `json:"agreement_no"`      // 支付宝代扣协议号
+	PeriodRule     PeriodRuleParams   `json:"period_rule"`       // 签约时的周期规则
+	Amount         Amount             `json:"amount"`            // 每期扣款金额，为0时使用周期规则的单次扣款最大金额
+	Subject        string             `json:"subject"`           // 扣款订单标题
+	Status         SubscriptionStatus `json:"status"`            // 订阅状态
+	PeriodIndex    int                `json:"period_index"`      // 当前期数，从0开始
+	NextDeductDate string             `json:"next_deduct_date"`  // 当前期的扣款日期，格式为yyyy-MM-dd，重试顺延后为修改后的日期
+	NextAttemptAt  time.Time          `json:"next_attempt_at"`   // 下一次尝试扣款的时间
+	Attempts       int                `json:"attempts"`          // 当前期已尝试扣款的次数
+	ClosedTrades   int                `json:"closed_trades"`     // 当前期已关闭的扣款交易数，大于0时在商户订单号后追加序号重新扣款
+	PaidCount      int                `json:"paid_count"`        // 已成功扣款的次数
+	PaidAmount     Amount             `json:"paid_amount"`       // 已成功扣款的总金额
+	LastOutTradeNo string             `json:"last_out_trade_no"` // 最后一次扣款的商户订单号
+	LastTradeNo    string             `json:"last_trade_no"`     // 最后一次扣款成功的支付宝交易号
+	LastFailReason string             `json:"last_fail_reason"`  // 最后一次扣款失败的原因
+	UpdatedAt      time.Time          `json:"updated_at"`        // 更新时间
+}
+
+// SubscriptionStore 订阅存储
+type SubscriptionStore interface {
+	// Create 新增订阅，订阅已存在时返回 IsSubscriptionExists 可判断的错误
+	Create(subscription *Subscription) error
+	// Update 更新订阅，不存在时返回 IsSubscriptionNotFound 可判断的错误
+	Update(subscription *Subscription) error
+	// ListDue 获取状态为 ACTIVE 且 NextAttemptAt 不晚于 now 的订阅
+	ListDue(now time.Time) (subscriptions []*Subscription, err error)
+}
+
+// IsSubscriptionNotFound 判断错误是否为订阅不存在
+func IsSubscriptionNotFound(err error) bool {
+	return errors.Is(err, subscriptionNotFoundErr)
+}
+
+// IsSubscriptionExists 判断错误是否为订阅已存在
+func IsSubscriptionExists(err error) bool {
+	return errors.Is(err, subscriptionExistsErr)
+}
+
+// MemorySubscriptionStore 内存订阅存储，进程重启后失效，仅用于测试或单机场景
+type MemorySubscriptionStore struct {
+	mutex         sync.RWMutex
+	subscriptions map[string]Subscription
+}
+
+// NewMemorySubscriptionStore 初始化内存订阅存储
+func NewMemorySubscriptionStore() *MemorySubscriptionStore {
+	return &MemorySubscriptionStore{subscriptions: make(map[string]Subscription)}
+}
+
+// Get 获取订阅
+func (m *MemorySubscriptionStore) Get(subscriptionId string) (subscription *Subscription, err error) {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+	stored, ok := m.subscriptions[subscriptionId]
+	if !ok {
+		return nil, subscriptionNotFoundErr
+	}
+	return &stored, nil
+}
+
+func (m *MemorySubscriptionStore) Create(subscription *Subscription) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	if _, ok := m.subscriptions[subscription.SubscriptionId]; ok {
+		return subscriptionExistsErr
+	}
+	m.subscriptions[subscription.SubscriptionId] = *subscription
+	return nil
+}
+
+func (m *MemorySubscriptionStore) Update(subscription *Subscription) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	if _, ok := m.subscriptions[subscription.SubscriptionId]; !ok {
+		return subscriptionNotFoundErr
+	}
+	m.subscriptions[subscription.SubscriptionId] = *subscription
+	return nil
+}
+
+func (m *MemorySubscriptionStore) ListDue(now time.Time) (subscriptions []*Subscription, err error) {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+	for _, stored := range m.subscriptions {
+		if stored.Status == SubscriptionActive && !stored.NextAttemptAt.After(now) {
+			subscription := stored
+			subscriptions = append(subscriptions, &subscription)
+		}
+	}
+	sort.Slice(subscriptions, func(i, j int) bool {
+		return subscriptions[i].SubscriptionId < subscriptions[j].SubscriptionId
+	})
+	return
+}
+
+// SubscriptionChargeStatus 单次扣款结果
+type SubscriptionChargeStatus string
+
+const (
+	SubscriptionChargeSuccess SubscriptionChargeStatus = "SUCCESS" // 扣款成功
+	SubscriptionChargeRetry   SubscriptionChargeStatus = "RETRY"   // 扣款失败或结果未知，将在重试间隔后使用相同的商户订单号重试，交易已关闭时使用新的商户订单号
+	SubscriptionChargeSkipped SubscriptionChargeStatus = "SKIPPED" // 重试次数用完且确认未扣款，跳过当前期
+	SubscriptionChargeStopped SubscriptionChargeStatus = "STOPPED" // 协议无效或扣款参数错误，订阅停止
+)
+
+// SubscriptionCharge 单次扣款记录
+type SubscriptionCharge struct {
+	SubscriptionId string                   // 订阅ID
+	PeriodIndex    int                      // 扣款的期数，从0开始
+	OutTradeNo     string                   // 商户订单号
+	TradeNo        string                   // 支付宝交易号
+	Amount         Amount                   // 扣款金额
+	Status         SubscriptionChargeStatus // 扣款结果
+	FailReason     string                   // 失败原因
+	ShiftedTo      string                   // 因重试顺延而修改后的扣款日期，未修改时为空
+}
+
+// SubscriptionSchedulerOptions 订阅扣款调度选项
+type SubscriptionSchedulerOptions struct {
+	RetryIntervals     []time.Duration                        // 每次扣款失败后到下一次重试的间隔，次数用完且确认未扣款后跳过当前期，默认 DefaultSubscriptionRetryIntervals
+	Interval           time.Duration                          // Run 检查到期订阅的间隔，默认1小时
+	Location           *time.Location                         // 扣款日期所在的时区，默认为北京时间
+	GenerateOutTradeNo func(subscription Subscription) string // 生成扣款的商户订单号，同一期的多次重试需返回相同的值，默认为 订阅ID_期数
+}
+
+// SubscriptionScheduler 订阅扣款调度，按签约时的周期规则对到期的协议发起扣款
+// 同一期的扣款使用相同的商户订单号，重试不会重复扣款，仅在交易已关闭时追加序号生成新的商户订单号；
+// 重试时间晚于当前期扣款日期时调用 UserAgreementExecutionPlanModify 顺延扣款日期，进入下一期时恢复按首期扣款日期计算的扣款计划
+type SubscriptionScheduler struct {
+	client  *Client
+	store   SubscriptionStore
+	options SubscriptionSchedulerOptions
+}
+
+// NewSubscriptionScheduler 初始化订阅扣款调度，store 为空时使用内存存储，opts 为空时使用默认选项
+func NewSubscriptionScheduler(client *Client, store SubscriptionStore, opts *SubscriptionSchedulerOptions) *SubscriptionScheduler {
+	scheduler := &SubscriptionScheduler{client: client, store: store}
+	if scheduler.store == nil {
+		scheduler.store = NewMemorySubscriptionStore()
+	}
+	if opts != nil {
+		scheduler.options = *opts
+	}
+	if scheduler.options.RetryIntervals == nil {
+		scheduler.options.RetryIntervals = DefaultSubscriptionRetryIntervals
+	}
+	if scheduler.options.Interval <= 0 {
+		scheduler.options.Interval = DefaultSubscriptionInterval
+	}
+	if scheduler.options.Location == nil {
+		scheduler.options.Location = time.FixedZone("CST", 8*3600)
+	}
+	if scheduler.options.GenerateOutTradeNo == nil {
+		scheduler.options.GenerateOutTradeNo = defaultSubscriptionOutTradeNo
+	}
+	return scheduler
+}
+
+// Add 新增订阅，首期扣款日期为周期规则的 execute_time
+func (s *SubscriptionScheduler) Add(subscription Subscription) (err error) {
+	if subscription.SubscriptionId == "" || subscription.AgreementNo == "" {
+		return errors.New("the subscription id or agreement no is empty")
+	}
+	if subscription.PeriodRule.Period <= 0 ||
+		subscription.PeriodRule.PeriodType != PeriodTypeDay && subscription.PeriodRule.PeriodType != PeriodTypeMonth {
+		return fmt.Errorf("subscription %s: invalid period rule %s %d", subscription.SubscriptionId, subscription.PeriodRule.PeriodType, subscription.PeriodRule.Period)
+	}
+	executeDate, err := time.ParseInLocation(deductDateLayout, subscription.PeriodRule.ExecuteTime, s.options.Location)
+	if err != nil {
+		return fmt.Errorf("subscription %s: invalid execute_time: %w", subscription.SubscriptionId, err)
+	}
+	subscription.Status = SubscriptionActive
+	subscription.NextDeductDate = subscription.PeriodRule.ExecuteTime
+	subscription.NextAttemptAt = executeDate
+	subscription.UpdatedAt = time.Now()
+	return s.store.Create(&subscription)
+}
+
+// Run 每隔 Interval 调用一次 RunDue，直到 ctx 取消
+func (s *SubscriptionScheduler) Run(ctx context.Context) error {
+	ticker := time.NewTicker(s.options.Interval)
+	defer ticker.Stop()
+	for {
+		if _, err := s.RunDue(ctx, time.Now()); err != nil && ctx.Err() == nil {
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// RunDue 对 now 时已到期的订阅发起扣款，返回每笔扣款的结果
+func (s *SubscriptionScheduler) RunDue(ctx context.Context, now time.Time) (charges []SubscriptionCharge, err error) {
+	subscriptions, err := s.store.ListDue(now)
+	if err != nil {
+		return
+	}
+	for _, subscription := range subscriptions {
+		if err = ctx.Err(); err != nil {
+			return
+		}
+		charge := s.charge(subscription, now)
+		subscription.UpdatedAt = time.Now()
+		if err = s.store.Update(subscription); err != nil {
+			err = fmt.Errorf("update subscription %s: %w", subscription.SubscriptionId, err)
+			return
+		}
+		charges = append(charges, charge)
+	}
+	return
+}
+
+// charge 对订阅的当前期发起一次扣款，并根据结果推进订阅
+func (s *SubscriptionScheduler) charge(subscription *Subscription, now time.Time) (charge SubscriptionCharge) {
+	amount := subscription.Amount
+	if amount == 0 {
+		amount = subscription.PeriodRule.SingleAmount
+	}
+	outTradeNo := s.options.GenerateOutTradeNo(*subscription)
+	if subscription.ClosedTrades > 0 {
+		// 当前期之前的扣款交易已关闭，相同的商户订单号无法再支付
+		outTradeNo = fmt.Sprintf("%s_%d", outTradeNo, subscription.ClosedTrades+1)
+	}
+	charge = SubscriptionCharge{
+		SubscriptionId: subscription.SubscriptionId,
+		PeriodIndex:    subscription.PeriodIndex,
+		OutTradeNo:     outTradeNo,
+		Amount:         amount,
+	}
+	subscription.Attempts++
+	subscription.LastOutTradeNo = charge.OutTradeNo
+
+	response, payErr := s.client.AgreementTradePay(AgreementTradePayRequestParams{
+		OutTradeNo:      charge.OutTradeNo,
+		TotalAmount:     amount,
+		Subject:         subscription.Subject,
+		AgreementParams: &AgreementParamsParams{AgreementNo: subscription.AgreementNo},
+	})
+	var uncertain bool
+	switch {
+	case payErr != nil && IsValidationError(payErr):
+		// 参数校验失败，请求未发送，重试也不会成功
+		charge.Status, charge.FailReason = SubscriptionChargeStopped, payErr.Error()
+		subscription.Status, subscription.LastFailReason = SubscriptionStopped, charge.FailReason
+		return
+	case payErr != nil:
+		charge.FailReason, uncertain = payErr.Error(), true
+	case response.Data.Code == SuccessCode || response.Data.SubCode == SubCodeTradeHasSuccess:
+		s.paid(subscription, &charge, response.Data.TradeNo)
+		return
+	case agreementInvalidSubCodes[response.Data.SubCode]:
+		charge.Status = SubscriptionChargeStopped
+		charge.FailReason = fmt.Sprintf("%s %s %s", response.Data.Code, response.Data.SubCode, response.Data.SubMsg)
+		subscription.Status, subscription.LastFailReason = SubscriptionStopped, charge.FailReason
+		return
+	case response.Data.SubCode == SubCodeTradeHasClose:
+		// 交易已关闭，重试时使用新的商户订单号
+		charge.FailReason = fmt.Sprintf("%s %s %s", response.Data.Code, response.Data.SubCode, response.Data.SubMsg)
+		subscription.ClosedTrades++
+	default:
+		// 余额不足、结果未知（10003、20000）等，使用相同的商户订单号重试
+		charge.FailReason = fmt.Sprintf("%s %s %s", response.Data.Code, response.Data.SubCode, response.Data.SubMsg)
+		uncertain = response.Data.Code == ProcessingCode || response.Data.Code == UnknownErrorCode || response.Data.SubCode == SubCodeSystemError
+	}
+	subscription.LastFailReason = charge.FailReason
+
+	if subscription.Attempts > len(s.options.RetryIntervals) {
+		if uncertain {
+			// 最后一次扣款结果未知，查询确认已支付时计为成功，仍无法确认时继续重试
+			uncertain = s.queryCharge(subscription, &charge)
+			if charge.Status == SubscriptionChargeSuccess {
+				return
+			}
+		}
+		if !uncertain {
+			charge.Status = SubscriptionChargeSkipped
+			s.nextPeriod(subscription)
+			return
+		}
+	}
+	charge.Status = SubscriptionChargeRetry
+	subscription.NextAttemptAt = now.Add(s.retryInterval(subscription.Attempts))
+	// 重试时间晚于当前期的扣款日期时，修改协议的执行计划，避免支付宝拒绝不在扣款计划内的扣款
+	retryDate := subscription.NextAttemptAt.In(s.options.Location).Format(deductDateLayout)
+	if retryDate > subscription.NextDeductDate {
+		if failReason := s.modifyDeductDate(subscription, retryDate, "扣款失败顺延"); failReason != "" {
+			charge.FailReason += failReason
+		} else {
+			subscription.NextDeductDate, charge.ShiftedTo = retryDate, retryDate
+		}
+		subscription.LastFailReason = charge.FailReason
+	}
+	return
+}
+
+// queryCharge 使用商户订单号查询结果未知的扣款，已支付时按扣款成功推进订阅，交易仍未确认时 uncertain 为 true
+func (s *SubscriptionScheduler) queryCharge(subscription *Subscription, charge *SubscriptionCharge) (uncertain bool) {
+	response, err := s.client.TradeQuery(TradeQueryRequestParams{OutTradeNo: charge.OutTradeNo})
+	switch {
+	case err != nil:
+		charge.FailReason += "; query: " + err.Error()
+		uncertain = true
+	case response.Data.Code == SuccessCode && response.Data.TradeStatus.IsPaid():
+		s.paid(subscription, charge, response.Data.TradeNo)
+		return
+	case response.Data.Code == SuccessCode && response.Data.TradeStatus == TradeStatusClosed:
+		charge.FailReason += "; query: trade closed"
+	case response.Data.SubCode == SubCodeTradeNotExist:
+		charge.FailReason += "; query: trade not exist"
+	default:
+		// 查询失败或交易等待付款
+		charge.FailReason += fmt.Sprintf("; query: %s %s %s %s", response.Data.Code, response.Data.SubCode, response.Data.SubMsg, response.Data.TradeStatus)
+		uncertain = true
+	}
+	subscription.LastFailReason = charge.FailReason
+	return
+}
+
+// paid 记录当前期扣款成功并进入下一期
+func (s *SubscriptionScheduler) paid(subscription *Subscription, charge *SubscriptionCharge, tradeNo string) {
+	charge.Status, charge.TradeNo, charge.FailReason = SubscriptionChargeSuccess, tradeNo, ""
+	subscription.PaidCount++
+	subscription.PaidAmount = subscription.PaidAmount.Add(charge.Amount)
+	subscription.LastTradeNo, subscription.LastFailReason = tradeNo, ""
+	s.nextPeriod(subscription)
+}
+
+// retryInterval 第 attempts 次扣款失败后到下一次重试的间隔，重试次数用完后仍需确认结果时使用最后一个间隔
+func (s *SubscriptionScheduler) retryInterval(attempts int) time.Duration {
+	intervals := s.options.RetryIntervals
+	switch {
+	case attempts <= len(intervals):
+		return intervals[attempts-1]
+	case len(intervals) > 0:
+		return intervals[len(intervals)-1]
+	}
+	return s.options.Interval
+}
+
+// modifyDeductDate 修改协议执行计划的下一次扣款日期，失败时返回失败原因
+func (s *SubscriptionScheduler) modifyDeductDate(subscription *Subscription, deductDate, memo string) (failReason string) {
+	response, err := s.client.UserAgreementExecutionPlanModify(UserAgreementExecutionPlanModifyRequestParams{
+		AgreementNo: subscription.AgreementNo,
+		DeductTime:  deductDate,
+		Memo:        memo,
+	})
+	switch {
+	case err != nil:
+		return "; modify execution plan: " + err.Error()
+	case response.Data.Code != SuccessCode:
+		return fmt.Sprintf("; modify execution plan: %s %s %s", response.Data.Code, response.Data.SubCode, response.Data.SubMsg)
+	}
+	return ""
+}
+
+// nextPeriod 进入下一期，下一期的扣款日期从首期扣款日期按周期计算，不受重试顺延影响；
+// 当前期曾顺延时修改协议的执行计划，恢复为下一期的扣款日期
+func (s *SubscriptionScheduler) nextPeriod(subscription *Subscription) {
+	currentDeductDate := subscription.NextDeductDate
+	subscription.PeriodIndex++
+	subscription.Attempts, subscription.ClosedTrades = 0, 0
+	rule := subscription.PeriodRule
+	if rule.TotalPayments > 0 && subscription.PaidCount >= rule.TotalPayments ||
+		rule.TotalAmount > 0 && subscription.PaidAmount >= rule.TotalAmount {
+		subscription.Status = SubscriptionCompleted
+		return
+	}
+	executeDate, err := time.ParseInLocation(deductDateLayout, rule.ExecuteTime, s.options.Location)
+	if err != nil {
+		subscription.Status, subscription.LastFailReason = SubscriptionStopped, err.Error()
+		return
+	}
+	scheduledDate := AddPeriod(executeDate, rule.PeriodType, rule.Period*(subscription.PeriodIndex-1)).Format(deductDateLayout)
+	deductDate := AddPeriod(executeDate, rule.PeriodType, rule.Period*subscription.PeriodIndex)
+	subscription.NextDeductDate = deductDate.Format(deductDateLayout)
+	subscription.NextAttemptAt = deductDate
+	if currentDeductDate != scheduledDate {
+		subscription.LastFailReason += s.modifyDeductDate(subscription, subscription.NextDeductDate, "恢复扣款计划")
+	}
+}
+
+// AddPeriod 计算 date 之后 period 个周期的日期，periodType 为 MONTH 时按自然月计算，目标月份没有对应日期时取该月最后一天
+func AddPeriod(date time.Time, periodType string, period int) time.Time {
+	if periodType != PeriodTypeMonth {
+		return date.AddDate(0, 0, period)
+	}
+	year, month, day := date.Date()
+	lastDay := time.Date(year, month+time.Month(period)+1, 0, 0, 0, 0, 0, date.Location()).Day()
+	if day > lastDay {
+		day = lastDay
+	}
+	return time.Date(year, month+time.Month(period), day, date.Hour(), date.Minute(), date.Second(), date.Nanosecond(), date.Location())
+}
+
+// defaultSubscriptionOutTradeNo 默认的扣款商户订单号：订阅ID_期数（从1开始）
+func defaultSubscriptionOutTradeNo(subscription Subscription) string {
+	return fmt.Sprintf("%s_%d", subscription.SubscriptionId, subscription.PeriodIndex+1)
+}
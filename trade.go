@@ -34,6 +34,14 @@ func (a *Client) TradeQuery(requestParam TradeQueryRequestParams) (responseParam
 	return
 }
 
+// TradePay 统一收单交易支付接口（当面付条码支付），需要确认支付结果时使用 PayAndConfirm
+func (a *Client) TradePay(requestParam TradePayRequestParams) (responseParam TradePayResponseParams, err error) {
+	if err = a.HandlerRequest("POST", &requestParam, &responseParam); err != nil {
+		return
+	}
+	return
+}
+
 // TradePreCreate 统一收单线下交易预创建
 func (a *Client) TradePreCreate(requestParam TradePreCreateRequestParams) (responseParam TradePreCreateResponseParams, err error) {
 	if err = a.HandlerRequest("POST", &requestParam, &responseParam); err != nil {
@@ -50,6 +58,12 @@ func (a *Client) TradeRefund(requestParam TradeRefundRequestParams) (responsePar
 	return
 }
 
+// TradePageRefund 统一收单退款页面接口，需要买家或商家在页面确认的退款使用
+// httpMethod 为 GET 时返回跳转URL，为 POST 时返回String形式的form
+func (a *Client) TradePageRefund(httpMethod string, requestParam TradePageRefundRequestParams) (result string, urlResult *url.URL, err error) {
+	return a.HandlerPageRequest(httpMethod, &requestParam)
+}
+
 // TradeAppPay app支付接口2.0
 func (a *Client) TradeAppPay(requestParam TradeAppPayRequestParams) (result string, err error) {
 	return a.HandlerSDKRequest(&requestParam)
@@ -60,6 +74,20 @@ func (a *Client) TradePagePay(requestParam TradePagePayRequestParams) (result st
 	return a.HandlerPageRequest("GET", &requestParam)
 }
 
+// TradePagePayForm 统一收单下单并支付页面接口，返回POST提交的表单，opts 为空时使用 DefaultFormOptions
+func (a *Client) TradePagePayForm(requestParam TradePagePayRequestParams, opts *FormOptions) (formHtml string, err error) {
+	return a.HandlerPageFormRequest(&requestParam, opts)
+}
+
+// TradeWapPay 手机网站支付接口2.0
+// 对请求参数只签名一次，同时返回POST提交的form和GET跳转的URL，未指定 ProductCode 时默认为 QUICK_WAP_WAY
+func (a *Client) TradeWapPay(requestParam TradeWapPayRequestParams) (formHtml string, urlResult *url.URL, err error) {
+	if requestParam.ProductCode == "" {
+		requestParam.ProductCode = ProductCodeQuickWapWay
+	}
+	return a.HandlerPageRequestBoth(&requestParam)
+}
+
 // TradeFastPayRefundQuery 统一收单交易退款查询
 func (a *Client) TradeFastPayRefundQuery(requestParam TradeFastPayRefundQueryRequestParams) (
 	responseParam TradeFastPayRefundQueryResponseParams, err error) {
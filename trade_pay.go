@@ -0,0 +1,150 @@
+package alipay
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+const (
+	// DefaultPayQueryInterval 条码支付结果未知时的默认查询间隔
+	DefaultPayQueryInterval = 5 * time.Second
+	// DefaultPayConfirmTimeout 条码支付等待用户确认的默认超时时间，超时后撤销交易
+	DefaultPayConfirmTimeout = 30 * time.Second
+	// DefaultCancelRetryInterval 撤销交易返回 retry_flag=Y 时的默认首次重试间隔，之后每次翻倍
+	DefaultCancelRetryInterval = 2 * time.Second
+	// defaultCancelRetryTimes 撤销交易需要重试时的最大重试次数
+	defaultCancelRetryTimes = 3
+)
+
+// PayConfirmOptions 条码支付确认选项
+type PayConfirmOptions struct {
+	QueryInterval       time.Duration // 查询间隔，默认5秒
+	Timeout             time.Duration // 等待用户确认支付的超时时间，默认30秒，超时后撤销交易
+	CancelRetryInterval time.Duration // 撤销交易需要重试时的首次重试间隔，之后每次翻倍，默认2秒
+}
+
+// PayConfirmResult 条码支付的最终结果
+type PayConfirmResult struct {
+	Paid          bool                      // 是否支付成功，false 表示支付失败且交易已关闭或已撤销
+	TradeNo       string                    // 支付宝交易号
+	OutTradeNo    string                    // 商户订单号
+	Cancelled     bool                      // 是否因超时撤销了交易
+	Reason        string                    // 支付失败的原因
+	PayResponse   *TradePayResponseParams   // 条码支付接口的响应
+	QueryResponse *TradeQueryResponseParams // 最后一次交易查询的响应，未查询时为空
+}
+
+// PayAndConfirm 条码支付并确认最终结果，参考：https://opendocs.alipay.com/open/194/106039
+// 1.调用 TradePay，返回10000或 ACQ.TRADE_HAS_SUCCESS（重复提交已支付的商户订单号）时支付成功，返回40004（非 ACQ.SYSTEM_ERROR）时支付失败
+// 2.返回10003（用户支付中）、20000、ACQ.SYSTEM_ERROR 或请求超时时，按 QueryInterval 轮询 TradeQuery
+// 3.轮询到 TRADE_SUCCESS/TRADE_FINISHED 时支付成功，TRADE_CLOSED 时支付失败
+// 4.超过 Timeout 或 ctx 取消后仍未确认时调用 TradeCancel 撤销交易，撤销成功返回支付失败；撤销失败时返回error，结果需人工确认
+// 撤销返回 retry_flag=Y 时按 CancelRetryInterval 退避重试，等待期间 ctx 取消则停止重试（ctx 已取消时只撤销一次）
+func (a *Client) PayAndConfirm(ctx context.Context, requestParam TradePayRequestParams, opts *PayConfirmOptions) (result PayConfirmResult, err error) {
+	interval, timeout, cancelInterval := DefaultPayQueryInterval, DefaultPayConfirmTimeout, DefaultCancelRetryInterval
+	if opts != nil && opts.QueryInterval > 0 {
+		interval = opts.QueryInterval
+	}
+	if opts != nil && opts.Timeout > 0 {
+		timeout = opts.Timeout
+	}
+	if opts != nil && opts.CancelRetryInterval > 0 {
+		cancelInterval = opts.CancelRetryInterval
+	}
+	result.OutTradeNo = requestParam.OutTradeNo
+
+	payResponse, payErr := a.TradePay(requestParam)
+	if payErr != nil {
+		if IsValidationError(payErr) {
+			// 参数校验失败，请求未发送
+			return result, payErr
+		}
+	} else {
+		result.PayResponse = &payResponse
+		result.TradeNo = payResponse.Data.TradeNo
+		switch {
+		case payResponse.Data.Code == SuccessCode || payResponse.Data.SubCode == SubCodeTradeHasSuccess:
+			result.Paid = true
+			return
+		case payResponse.Data.Code == ProcessingCode || payResponse.Data.Code == UnknownErrorCode ||
+			payResponse.Data.SubCode == SubCodeUserPaying || payResponse.Data.SubCode == SubCodeSystemError:
+			// 结果未知，查询确认
+		default:
+			result.Reason = fmt.Sprintf("%s %s %s", payResponse.Data.Code, payResponse.Data.SubCode, payResponse.Data.SubMsg)
+			return
+		}
+	}
+
+	deadline := time.NewTimer(timeout)
+	defer deadline.Stop()
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return a.cancelUnconfirmedPay(ctx, result, cancelInterval)
+		case <-deadline.C:
+			return a.cancelUnconfirmedPay(ctx, result, cancelInterval)
+		case <-ticker.C:
+		}
+
+		queryResponse, queryErr := a.TradeQuery(TradeQueryRequestParams{
+			OtherRequestParams: OtherRequestParams{AppAuthToken: requestParam.AppAuthToken},
+			OutTradeNo:         requestParam.OutTradeNo,
+		})
+		if queryErr != nil || queryResponse.Data.Code != SuccessCode {
+			// 查询失败或交易不存在时继续查询
+			continue
+		}
+		result.QueryResponse = &queryResponse
+		result.TradeNo = queryResponse.Data.TradeNo
+		switch queryResponse.Data.TradeStatus {
+		case TradeStatusSuccess, TradeStatusFinished:
+			result.Paid = true
+			return
+		case TradeStatusClosed:
+			result.Reason = "trade closed"
+			return
+		}
+	}
+}
+
+// cancelUnconfirmedPay 撤销超时未确认的条码支付交易，需要重试时从 interval 开始按翻倍的间隔等待
+func (a *Client) cancelUnconfirmedPay(ctx context.Context, result PayConfirmResult, interval time.Duration) (PayConfirmResult, error) {
+	requestParam := TradeCancelRequestParams{OutTradeNo: result.OutTradeNo}
+	if result.PayResponse != nil {
+		requestParam.TradeNo = result.PayResponse.Data.TradeNo
+	}
+	var lastErr error
+	for i := 0; i < defaultCancelRetryTimes; i++ {
+		if i > 0 {
+			timer := time.NewTimer(interval)
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return result, fmt.Errorf("the pay result of trade %s is unknown: %w", result.OutTradeNo, lastErr)
+			case <-timer.C:
+			}
+			interval *= 2
+		}
+		cancelResponse, err := a.TradeCancel(requestParam)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		if cancelResponse.Data.Code == SuccessCode {
+			result.Cancelled = true
+			result.Reason = "pay confirm timeout, trade cancelled"
+			if cancelResponse.Data.Action == "refund" {
+				result.Reason = "pay confirm timeout, trade cancelled and refunded"
+			}
+			return result, nil
+		}
+		lastErr = fmt.Errorf("cancel trade %s fail: %s %s", result.OutTradeNo, cancelResponse.Data.SubCode, cancelResponse.Data.SubMsg)
+		if cancelResponse.Data.RetryFlag != "Y" {
+			break
+		}
+	}
+	return result, fmt.Errorf("the pay result of trade %s is unknown: %w", result.OutTradeNo, lastErr)
+}
@@ -0,0 +1,147 @@
+package alipay
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+const (
+	// DefaultWaitTradeInitialInterval 等待交易结果时的默认初始查询间隔
+	DefaultWaitTradeInitialInterval = 2 * time.Second
+	// DefaultWaitTradeMaxInterval 等待交易结果时的默认最大查询间隔
+	DefaultWaitTradeMaxInterval = 30 * time.Second
+	// DefaultWaitTradeMultiplier 等待交易结果时查询间隔的默认增长倍数
+	DefaultWaitTradeMultiplier = 1.5
+)
+
+// ErrWaitTradeTimeout 等待交易结果超时，且未关闭交易
+var ErrWaitTradeTimeout = errors.New("wait for trade timeout")
+
+// WaitTradeOptions 等待交易结果的选项
+type WaitTradeOptions struct {
+	AppAuthToken    string        // 应用授权令牌，服务商代调用时使用
+	InitialInterval time.Duration // 初始查询间隔，默认2秒
+	MaxInterval     time.Duration // 最大查询间隔，默认30秒
+	Multiplier      float64       // 每次查询后间隔的增长倍数，默认1.5，小于1时按1处理
+	Timeout         time.Duration // 等待的超时时间，为0时只受 ctx 控制
+	CloseOnTimeout  bool          // 超时后是否调用 TradeClose 关闭交易
+}
+
+// WaitTradeResult 等待交易的最终结果
+type WaitTradeResult struct {
+	TradeNo       string                    // 支付宝交易号
+	OutTradeNo    string                    // 商户订单号
+	TradeStatus   TradeStatus               // 交易状态，成功关闭交易后为 TRADE_CLOSED
+	Paid          bool                      // 是否已支付（TRADE_SUCCESS 或 TRADE_FINISHED）
+	ClosedByWait  bool                      // 是否因超时由 WaitForTrade 关闭了交易
+	QueryResponse *TradeQueryResponseParams // 最后一次成功查询的响应
+}
+
+// WaitForTrade 按退避间隔轮询 TradeQuery，直到交易进入终态（TRADE_SUCCESS/TRADE_FINISHED/TRADE_CLOSED）
+// 交易不存在（ACQ.TRADE_NOT_EXIST，如预创建的二维码尚未被扫描）及 WAIT_BUYER_PAY 时继续等待，系统异常及网络错误时重试
+// 超过 Timeout 时：CloseOnTimeout 为 true 则关闭交易，关闭失败时再查询一次确认交易是否已支付；否则返回 ErrWaitTradeTimeout
+// ctx 取消时返回 ctx.Err()
+func (a *Client) WaitForTrade(ctx context.Context, outTradeNo string, opts *WaitTradeOptions) (result WaitTradeResult, err error) {
+	options := WaitTradeOptions{}
+	if opts != nil {
+		options = *opts
+	}
+	if options.InitialInterval <= 0 {
+		options.InitialInterval = DefaultWaitTradeInitialInterval
+	}
+	if options.MaxInterval <= 0 {
+		options.MaxInterval = DefaultWaitTradeMaxInterval
+	}
+	if options.Multiplier == 0 {
+		options.Multiplier = DefaultWaitTradeMultiplier
+	}
+	if options.Multiplier < 1 {
+		options.Multiplier = 1
+	}
+	result.OutTradeNo = outTradeNo
+
+	var deadline <-chan time.Time
+	if options.Timeout > 0 {
+		deadlineTimer := time.NewTimer(options.Timeout)
+		defer deadlineTimer.Stop()
+		deadline = deadlineTimer.C
+	}
+	interval := options.InitialInterval
+	for {
+		var done bool
+		if done, err = a.queryTradeOnce(&result, options.AppAuthToken); done || err != nil {
+			return
+		}
+
+		wait := time.NewTimer(interval)
+		select {
+		case <-ctx.Done():
+			wait.Stop()
+			err = ctx.Err()
+			return
+		case <-deadline:
+			wait.Stop()
+			if !options.CloseOnTimeout {
+				err = ErrWaitTradeTimeout
+				return
+			}
+			err = a.closeTimeoutTrade(&result, options.AppAuthToken)
+			return
+		case <-wait.C:
+		}
+		if interval = time.Duration(float64(interval) * options.Multiplier); interval > options.MaxInterval {
+			interval = options.MaxInterval
+		}
+	}
+}
+
+// queryTradeOnce 查询一次交易，交易进入终态时 done 为 true，查询出现不可重试的错误时返回error
+func (a *Client) queryTradeOnce(result *WaitTradeResult, appAuthToken string) (done bool, err error) {
+	queryResponse, queryErr := a.TradeQuery(TradeQueryRequestParams{
+		OtherRequestParams: OtherRequestParams{AppAuthToken: appAuthToken},
+		OutTradeNo:         result.OutTradeNo,
+	})
+	if queryErr != nil {
+		if IsValidationError(queryErr) {
+			return false, queryErr
+		}
+		// 网络错误等，继续重试
+		return false, nil
+	}
+	data := queryResponse.Data
+	switch {
+	case data.Code == SuccessCode:
+	case data.SubCode == SubCodeTradeNotExist || data.SubCode == SubCodeSystemError || data.Code == UnknownErrorCode:
+		return false, nil
+	default:
+		return false, fmt.Errorf("query trade %s fail: %s %s %s", result.OutTradeNo, data.Code, data.SubCode, data.SubMsg)
+	}
+	result.QueryResponse = &queryResponse
+	result.TradeNo = data.TradeNo
+	result.TradeStatus = data.TradeStatus
+	result.Paid = data.TradeStatus.IsPaid()
+	return result.Paid || data.TradeStatus.IsTerminal(), nil
+}
+
+// closeTimeoutTrade 关闭超时未支付的交易，关闭失败时再查询一次，避免关闭过程中买家完成了支付
+func (a *Client) closeTimeoutTrade(result *WaitTradeResult, appAuthToken string) (err error) {
+	closeResponse, closeErr := a.TradeClose(TradeCloseRequestParams{
+		OtherRequestParams: OtherRequestParams{AppAuthToken: appAuthToken},
+		OutTradeNo:         result.OutTradeNo,
+	})
+	if closeErr == nil && (closeResponse.Data.Code == SuccessCode || closeResponse.Data.SubCode == SubCodeTradeNotExist) {
+		// 交易不存在说明买家未扫码，无需关闭
+		result.ClosedByWait = true
+		result.TradeStatus = TradeStatusClosed
+		return nil
+	}
+	if done, queryErr := a.queryTradeOnce(result, appAuthToken); done && queryErr == nil {
+		return nil
+	}
+	if closeErr == nil {
+		closeErr = fmt.Errorf("%s %s %s", closeResponse.Data.Code, closeResponse.Data.SubCode, closeResponse.Data.SubMsg)
+	}
+	return fmt.Errorf("close trade %s after wait timeout: %w", result.OutTradeNo, closeErr)
+}
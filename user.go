@@ -0,0 +1,54 @@
+package alipay
+
+import "net/url"
+
+// UserAgreementPageSign 支付宝个人协议页面签约接口
+// httpMethod 为 GET 时返回跳转URL，为 POST 时返回String形式的form；签约结果通过 AsyncNotifyUserAgreement 处理异步通知或通过 UserAgreementQuery 查询
+func (a *Client) UserAgreementPageSign(httpMethod string, requestParam UserAgreementPageSignRequestParams) (result string, urlResult *url.URL, err error) {
+	return a.HandlerPageRequest(httpMethod, &requestParam)
+}
+
+// UserAgreementQuery 支付宝个人代扣协议查询接口
+func (a *Client) UserAgreementQuery(requestParam UserAgreementQueryRequestParams) (
+	responseParam UserAgreementQueryResponseParams, err error) {
+	if err = a.HandlerRequest("POST", &requestParam, &responseParam); err != nil {
+		return
+	}
+	return
+}
+
+// UserAgreementUnsign 支付宝个人代扣协议解约接口
+func (a *Client) UserAgreementUnsign(requestParam UserAgreementUnsignRequestParams) (
+	responseParam UserAgreementUnsignResponseParams, err error) {
+	if err = a.HandlerRequest("POST", &requestParam, &responseParam); err != nil {
+		return
+	}
+	return
+}
+
+// UserAgreementExecutionPlanModify 周期性扣款协议执行计划修改接口，用于延后下一次扣款时间
+func (a *Client) UserAgreementExecutionPlanModify(requestParam UserAgreementExecutionPlanModifyRequestParams) (
+	responseParam UserAgreementExecutionPlanModifyResponseParams, err error) {
+	if err = a.HandlerRequest("POST", &requestParam, &responseParam); err != nil {
+		return
+	}
+	return
+}
+
+// AgreementTradePay 协议扣款，使用 alipay.trade.pay 根据已签约的代扣协议扣款，未指定 ProductCode 时默认为 CYCLE_PAY_AUTH
+func (a *Client) AgreementTradePay(requestParam AgreementTradePayRequestParams) (
+	responseParam TradePayResponseParams, err error) {
+	if requestParam.ProductCode == "" {
+		requestParam.ProductCode = ProductCodeCyclePayAuth
+	}
+	if err = a.HandlerRequest("POST", &requestParam, &responseParam); err != nil {
+		return
+	}
+	return
+}
+
+// AsyncNotifyUserAgreement 处理个人代扣协议签约、解约异步通知
+func (a *Client) AsyncNotifyUserAgreement(rawBody string) (notifyResult UserAgreementNotificationParams, err error) {
+	err = a.verifyAndDecodeNotify(rawBody, false, &notifyResult, nil)
+	return
+}
@@ -0,0 +1,144 @@
+package utils
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+)
+
+const (
+	// KeyFormatPKCS1 PKCS#1格式私钥，NewClient 需要的格式，一般用于非Java语言
+	KeyFormatPKCS1 = "PKCS1"
+	// KeyFormatPKCS8 PKCS#8格式私钥，一般用于Java语言
+	KeyFormatPKCS8 = "PKCS8"
+)
+
+// oidEmailAddress 证书主题中的邮箱字段
+var oidEmailAddress = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 9, 1}
+
+// RSAKeyPair RSA密钥对
+type RSAKeyPair struct {
+	PrivateKey    *rsa.PrivateKey
+	PrivateKeyPem string // 私钥PEM格式（包含begin，end），格式由生成时的format决定
+	PublicKeyPem  string // 公钥PEM格式（包含begin，end）
+	RawPrivateKey string // 私钥原始base64字符串（不包含begin，end），格式由生成时的format决定
+	RawPublicKey  string // 公钥原始base64字符串（不包含begin，end），即上传到支付宝开放平台的应用公钥
+}
+
+// CSRSubject 证书签名请求主题，对应支付宝开放平台密钥工具生成CSR时填写的信息
+type CSRSubject struct {
+	CommonName         string // 公用名称，必填，一般为应用名称或公司名称
+	Organization       string // 组织/公司名称，必填，需与支付宝账号认证主体名称一致
+	OrganizationalUnit string // 部门
+	Country            string // 国家/地区，默认为CN
+	Province           string // 省份
+	Locality           string // 城市
+	Email              string // 邮箱
+}
+
+// GenerateRSAKeyPair 生成RSA密钥对
+// bits 密钥长度，支付宝推荐RSA2使用2048位
+// format 私钥格式：PKCS1、PKCS8
+func GenerateRSAKeyPair(bits int, format string) (keyPair *RSAKeyPair, err error) {
+	if bits < 1024 {
+		return nil, fmt.Errorf("the key size must be at least 1024 bits, got %d", bits)
+	}
+	privateKey, err := rsa.GenerateKey(rand.Reader, bits)
+	if err != nil {
+		return
+	}
+	var privateDer []byte
+	var privateBlockType string
+	switch format {
+	case KeyFormatPKCS1:
+		privateDer = x509.MarshalPKCS1PrivateKey(privateKey)
+		privateBlockType = "RSA PRIVATE KEY"
+	case KeyFormatPKCS8:
+		if privateDer, err = x509.MarshalPKCS8PrivateKey(privateKey); err != nil {
+			return
+		}
+		privateBlockType = "PRIVATE KEY"
+	default:
+		return nil, fmt.Errorf("unsupported private key format %q, only PKCS1 and PKCS8 are supported", format)
+	}
+	publicDer, err := x509.MarshalPKIXPublicKey(&privateKey.PublicKey)
+	if err != nil {
+		return
+	}
+	keyPair = &RSAKeyPair{
+		PrivateKey:    privateKey,
+		PrivateKeyPem: string(pem.EncodeToMemory(&pem.Block{Type: privateBlockType, Bytes: privateDer})),
+		PublicKeyPem:  string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: publicDer})),
+		RawPrivateKey: base64.StdEncoding.EncodeToString(privateDer),
+		RawPublicKey:  base64.StdEncoding.EncodeToString(publicDer),
+	}
+	return
+}
+
+// ParsePrivateKey 解析私钥，支持PKCS#1和PKCS#8格式
+func ParsePrivateKey(privateKeyPemStr string) (privateKey *rsa.PrivateKey, err error) {
+	block, _ := pem.Decode([]byte(privateKeyPemStr))
+	if block == nil {
+		return nil, errors.New("failed to decode PEM block containing the private key")
+	}
+	if privateKey, err = x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return
+	}
+	key, pkcs8Err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if pkcs8Err != nil {
+		// 返回PKCS#1的解析错误，与 ParsePKCS1PrivateKey 保持一致
+		return nil, err
+	}
+	privateKey, ok := key.(*rsa.PrivateKey)
+	if !ok {
+		return nil, errors.New("the private key is not an RSA private key")
+	}
+	return privateKey, nil
+}
+
+// GenerateCSR 生成证书签名请求（CSR），用于在支付宝开放平台上传并申请应用公钥证书
+// 返回PEM格式（包含begin，end）的CSR
+func GenerateCSR(privateKey *rsa.PrivateKey, subject CSRSubject) (csrPem string, err error) {
+	if privateKey == nil {
+		return "", errors.New("the private key is empty")
+	}
+	if subject.CommonName == "" || subject.Organization == "" {
+		return "", errors.New("the common name and organization of the CSR subject are required")
+	}
+	if subject.Country == "" {
+		subject.Country = "CN"
+	}
+	name := pkix.Name{
+		CommonName:   subject.CommonName,
+		Organization: []string{subject.Organization},
+		Country:      []string{subject.Country},
+	}
+	if subject.OrganizationalUnit != "" {
+		name.OrganizationalUnit = []string{subject.OrganizationalUnit}
+	}
+	if subject.Province != "" {
+		name.Province = []string{subject.Province}
+	}
+	if subject.Locality != "" {
+		name.Locality = []string{subject.Locality}
+	}
+	if subject.Email != "" {
+		name.ExtraNames = append(name.ExtraNames, pkix.AttributeTypeAndValue{Type: oidEmailAddress, Value: subject.Email})
+	}
+	template := &x509.CertificateRequest{
+		Subject:            name,
+		SignatureAlgorithm: x509.SHA256WithRSA,
+	}
+	csrDer, err := x509.CreateCertificateRequest(rand.Reader, template, privateKey)
+	if err != nil {
+		return
+	}
+	csrPem = string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE REQUEST", Bytes: csrDer}))
+	return
+}
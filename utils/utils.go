@@ -9,6 +9,7 @@ import (
 	"encoding/base64"
 	"encoding/hex"
 	"encoding/pem"
+	"errors"
 	"os"
 )
 
@@ -16,6 +17,10 @@ import (
 func ParsePKCS1PrivateKey(privateKeyPemStr string) (privateKey *rsa.PrivateKey, err error) {
 	// pem解码
 	block, _ := pem.Decode([]byte(privateKeyPemStr))
+	if block == nil {
+		err = errors.New("failed to decode PEM block containing the private key")
+		return
+	}
 	// x509解码
 	// ParsePKCS1PrivateKey解析ASN.1 PKCS#1 DER编码的rsa私钥。
 	privateKey, err = x509.ParsePKCS1PrivateKey(block.Bytes)
@@ -26,6 +31,9 @@ func ParsePKCS1PrivateKey(privateKeyPemStr string) (privateKey *rsa.PrivateKey,
 func ParsePKIXPublicKey(publicKeyPemStr string) (publicKey *rsa.PublicKey, err error) {
 	// pem解码
 	block, _ := pem.Decode([]byte(publicKeyPemStr))
+	if block == nil {
+		return nil, errors.New("failed to decode PEM block containing the public key")
+	}
 	// x509解码
 	// ParsePKIXPublicKey解析一个DER编码的公钥。这些公钥一般在以"BEGIN PUBLIC KEY"出现的PEM块中
 	publicKeyInterface, err := x509.ParsePKIXPublicKey(block.Bytes)
@@ -33,7 +41,10 @@ func ParsePKIXPublicKey(publicKeyPemStr string) (publicKey *rsa.PublicKey, err e
 		return nil, err
 	}
 	// 类型断言
-	publicKey = publicKeyInterface.(*rsa.PublicKey)
+	publicKey, ok := publicKeyInterface.(*rsa.PublicKey)
+	if !ok {
+		return nil, errors.New("the public key is not an RSA public key")
+	}
 	return
 }
 
@@ -42,6 +53,7 @@ func ParseX509Certificate(certPemStr string) (x509Cert *x509.Certificate, err er
 	// pem解码
 	block, _ := pem.Decode([]byte(certPemStr))
 	if block == nil {
+		err = errors.New("failed to decode PEM block containing the certificate")
 		return
 	}
 	x509Cert, err = x509.ParseCertificate(block.Bytes)
@@ -0,0 +1,289 @@
+package alipay
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+// ValidateTagName 请求参数校验规则的struct tag名称
+// 支持的规则（多个规则以逗号分隔，pattern 必须放在最后）：
+//
+//	required         必填，不能为零值
+//	min=N / max=N    字符串字符数或数组长度的范围
+//	amount           金额需在 [0.01,100000000] 范围内（未填时不校验），结构体指定标价币种 trans_currency 时按该币种的小数位数校验，如JPY为[1,100000000]
+//	oneof=group      同一group的字段至少填写一个，如 trade_no 与 out_trade_no
+//	exclusive=group  同一group的字段最多填写一个，如 enable_pay_channels 与 disable_pay_channels
+//	pattern=REGEXP   字符串需匹配正则表达式（未填时不校验），也可以是预定义的规则名，如 pattern=out_trade_no
+//
+// 例如：`alipay:"required,max=64,pattern=out_trade_no"`
+const ValidateTagName = "alipay"
+
+// OutTradeNoPattern 商户订单号规则：仅支持字母、数字、下划线
+const OutTradeNoPattern = `^[A-Za-z0-9_]+$`
+
+// namedPatterns 可在 pattern 规则中按名称引用的正则表达式
+var namedPatterns = map[string]string{
+	"out_trade_no": OutTradeNoPattern,
+}
+
+// ValidationError 请求参数校验错误
+type ValidationError struct {
+	Field   string // 字段名，使用json名称，嵌套字段以.分隔，如 goods_detail[0].goods_id
+	Rule    string // 未通过的规则，如 required、max
+	Message string // 错误描述
+}
+
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("field %s: %s", e.Field, e.Message)
+}
+
+// ValidationErrors 请求参数校验错误列表
+type ValidationErrors []*ValidationError
+
+func (e ValidationErrors) Error() string {
+	messages := make([]string, 0, len(e))
+	for _, err := range e {
+		messages = append(messages, err.Error())
+	}
+	return "request params validation failed: " + strings.Join(messages, "; ")
+}
+
+// IsValidationError 判断错误是否为请求参数校验错误，支持被 fmt.Errorf("%w") 等包装的错误
+func IsValidationError(err error) bool {
+	var errs ValidationErrors
+	return errors.As(err, &errs)
+}
+
+var patternCache sync.Map // map[string]*regexp.Regexp
+
+// ValidateRequest 根据 alipay struct tag 校验请求参数，HandlerRequest 等方法在签名前会自动调用
+// 校验未通过时返回 ValidationErrors
+func ValidateRequest(requestParams interface{}) error {
+	value := reflect.ValueOf(requestParams)
+	for value.Kind() == reflect.Ptr || value.Kind() == reflect.Interface {
+		if value.IsNil() {
+			return nil
+		}
+		value = value.Elem()
+	}
+	if value.Kind() != reflect.Struct {
+		return nil
+	}
+	var errs ValidationErrors
+	validateStruct(value, "", &errs)
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+// groupState 记录 oneof/exclusive 分组内已填写的字段
+type groupState struct {
+	rule   string
+	fields []string
+	filled []string
+}
+
+func validateStruct(value reflect.Value, prefix string, errs *ValidationErrors) {
+	groups := make(map[string]*groupState)
+	currency := transCurrency(value)
+	valueType := value.Type()
+	for i := 0; i < valueType.NumField(); i++ {
+		field := valueType.Field(i)
+		if field.PkgPath != "" {
+			continue
+		}
+		fieldValue := value.Field(i)
+		// 嵌入的结构体（如 OtherRequestParams）没有业务字段，跳过
+		if field.Anonymous {
+			continue
+		}
+		name := fieldName(field)
+		if name == "-" {
+			continue
+		}
+		path := prefix + name
+		tag := field.Tag.Get(ValidateTagName)
+		if tag != "" {
+			validateField(fieldValue, path, tag, currency, groups, errs)
+		}
+		validateNested(fieldValue, path, errs)
+	}
+
+	groupNames := make([]string, 0, len(groups))
+	for groupName := range groups {
+		groupNames = append(groupNames, groupName)
+	}
+	sort.Strings(groupNames)
+	for _, groupName := range groupNames {
+		group := groups[groupName]
+		switch {
+		case group.rule == "oneof" && len(group.filled) == 0:
+			*errs = append(*errs, &ValidationError{
+				Field:   prefix + strings.Join(group.fields, "|"),
+				Rule:    "oneof",
+				Message: "at least one of " + strings.Join(group.fields, ", ") + " is required",
+			})
+		case group.rule == "exclusive" && len(group.filled) > 1:
+			*errs = append(*errs, &ValidationError{
+				Field:   prefix + strings.Join(group.filled, "|"),
+				Rule:    "exclusive",
+				Message: strings.Join(group.filled, ", ") + " are mutually exclusive",
+			})
+		}
+	}
+}
+
+// transCurrency 结构体的标价币种（trans_currency），金额字段以该币种的最小单位保存，未指定时为空
+func transCurrency(value reflect.Value) Currency {
+	valueType := value.Type()
+	for i := 0; i < valueType.NumField(); i++ {
+		field := valueType.Field(i)
+		if field.PkgPath == "" && !field.Anonymous && fieldName(field) == "trans_currency" {
+			if currency, ok := value.Field(i).Interface().(Currency); ok {
+				return currency
+			}
+		}
+	}
+	return ""
+}
+
+func validateField(fieldValue reflect.Value, path, tag string, currency Currency, groups map[string]*groupState, errs *ValidationErrors) {
+	isZero := fieldValue.IsZero()
+	name := path[strings.LastIndex(path, ".")+1:]
+	for _, rule := range splitRules(tag) {
+		ruleName, param := rule, ""
+		if index := strings.IndexByte(rule, '='); index >= 0 {
+			ruleName, param = rule[:index], rule[index+1:]
+		}
+		var message string
+		switch ruleName {
+		case "required":
+			if isZero {
+				message = "is required"
+			}
+		case "min", "max":
+			limit, err := strconv.Atoi(param)
+			if err != nil || isZero {
+				break
+			}
+			length := valueLength(fieldValue)
+			if ruleName == "min" && length < limit {
+				message = fmt.Sprintf("length must be at least %d, got %d", limit, length)
+			}
+			if ruleName == "max" && length > limit {
+				message = fmt.Sprintf("length must be at most %d, got %d", limit, length)
+			}
+		case "amount":
+			if isZero {
+				break
+			}
+			switch amount := fieldValue.Interface().(type) {
+			case Amount:
+				if err := amount.ValidateIn(currency); err != nil {
+					message = err.Error()
+				}
+			case NumberAmount:
+				if err := amount.Validate(); err != nil {
+					message = err.Error()
+				}
+			}
+		case "oneof", "exclusive":
+			key := ruleName + ":" + param
+			group := groups[key]
+			if group == nil {
+				group = &groupState{rule: ruleName}
+				groups[key] = group
+			}
+			group.fields = append(group.fields, name)
+			if !isZero {
+				group.filled = append(group.filled, name)
+			}
+		case "pattern":
+			if isZero || fieldValue.Kind() != reflect.String {
+				break
+			}
+			if named, ok := namedPatterns[param]; ok {
+				param = named
+			}
+			pattern, err := compilePattern(param)
+			if err != nil {
+				message = "invalid pattern " + param
+			} else if !pattern.MatchString(fieldValue.String()) {
+				message = "does not match pattern " + param
+			}
+		}
+		if message != "" {
+			*errs = append(*errs, &ValidationError{Field: path, Rule: ruleName, Message: message})
+		}
+	}
+}
+
+// validateNested 校验嵌套的结构体、结构体指针及其数组
+func validateNested(fieldValue reflect.Value, path string, errs *ValidationErrors) {
+	switch fieldValue.Kind() {
+	case reflect.Ptr:
+		if !fieldValue.IsNil() {
+			validateNested(fieldValue.Elem(), path, errs)
+		}
+	case reflect.Struct:
+		validateStruct(fieldValue, path+".", errs)
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < fieldValue.Len(); i++ {
+			validateNested(fieldValue.Index(i), fmt.Sprintf("%s[%d]", path, i), errs)
+		}
+	}
+}
+
+// splitRules 以逗号分隔规则，pattern 之后的内容整体作为正则表达式
+func splitRules(tag string) (rules []string) {
+	for tag != "" {
+		if strings.HasPrefix(tag, "pattern=") {
+			return append(rules, tag)
+		}
+		index := strings.IndexByte(tag, ',')
+		if index < 0 {
+			return append(rules, tag)
+		}
+		rules = append(rules, tag[:index])
+		tag = tag[index+1:]
+	}
+	return
+}
+
+func compilePattern(pattern string) (*regexp.Regexp, error) {
+	if cached, ok := patternCache.Load(pattern); ok {
+		return cached.(*regexp.Regexp), nil
+	}
+	compiled, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, err
+	}
+	patternCache.Store(pattern, compiled)
+	return compiled, nil
+}
+
+func valueLength(fieldValue reflect.Value) int {
+	switch fieldValue.Kind() {
+	case reflect.String:
+		return utf8.RuneCountInString(fieldValue.String())
+	case reflect.Slice, reflect.Array, reflect.Map:
+		return fieldValue.Len()
+	}
+	return 0
+}
+
+func fieldName(field reflect.StructField) string {
+	name := strings.Split(field.Tag.Get("json"), ",")[0]
+	if name == "" {
+		return field.Name
+	}
+	return name
+}